@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amarin/gomorphy/pkg/morph"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+// app provides index loader and input and output streams to commands.
+type app struct {
+	program  string              // program name
+	name     string              // running command name
+	usage    string              // running command arguments usage
+	loader   *opencorpora.Loader // compiled index loader
+	analyzer *morph.Analyzer     // loaded index analyzer, nil until load
+	json     bool                // output JSON lines instead of text
+	input    io.Reader           // words input if no words in arguments
+	output   io.Writer           // results output
+	errors   io.Writer           // not found words and errors output
+}
+
+// parseFlags parses command flags reporting usage to errors output.
+// Returns false if flags are wrong, command exits with exitUsage then.
+func (app *app) parseFlags(flags *flag.FlagSet, args []string) bool {
+	flags.SetOutput(app.errors)
+	flags.Usage = func() {
+		fmt.Fprintf(app.errors, "Usage: %s %s %s\n", app.program, app.name, app.usage)
+		flags.PrintDefaults()
+	}
+
+	return flags.Parse(args) == nil
+}
+
+// load loads compiled index reporting error to errors output. Returns false if index is not loaded.
+func (app *app) load() bool {
+	loaded, err := app.loader.LoadIndex()
+	if err != nil {
+		fmt.Fprintf(app.errors, "load %v: %v\n", app.loader.CompiledFile(), err)
+		return false
+	}
+
+	app.analyzer = morph.NewAnalyzer(loaded)
+
+	return true
+}
+
+// eachWord calls fn for every word of arguments or for every non-empty input line if no arguments.
+// Words are lower cased as index keeps words. Output is flushed after every word, so input may be fed
+// interactively by pipe. Returns exit code: exitNotFound if fn reported any word not found,
+// exitError if fn or output failed.
+func (app *app) eachWord(args []string, fn func(output *bufio.Writer, word string) (bool, error)) int {
+	output := bufio.NewWriter(app.output)
+	exitCode := exitOK
+
+	process := func(word string) bool {
+		found, err := fn(output, strings.ToLower(word))
+		if err == nil {
+			err = output.Flush()
+		}
+
+		if err != nil {
+			fmt.Fprintf(app.errors, "%s: `%s`: %v\n", app.name, word, err)
+			exitCode = exitError
+
+			return false
+		}
+
+		if !found {
+			fmt.Fprintf(app.errors, "%s: `%s`: not found\n", app.name, word)
+			exitCode = exitNotFound
+		}
+
+		return true
+	}
+
+	if len(args) > 0 {
+		for _, word := range args {
+			if !process(word) {
+				break
+			}
+		}
+
+		return exitCode
+	}
+
+	scanner := bufio.NewScanner(app.input)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" && !process(word) {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(app.errors, "%s: read words: %v\n", app.name, err)
+		exitCode = exitError
+	}
+
+	return exitCode
+}
+
+// writeJSON writes value as single JSON line.
+func writeJSON(output io.Writer, value interface{}) error {
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+
+	return encoder.Encode(value)
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/export"
+	"github.com/amarin/gomorphy/pkg/morph"
+	"github.com/amarin/gomorphy/pkg/stats"
+)
+
+// parseResult provides parse command JSON output line.
+type parseResult struct {
+	Word   string        `json:"word"`
+	Parses []morph.Parse `json:"parses"`
+}
+
+// lemmaResult provides lemma command JSON output line.
+type lemmaResult struct {
+	Word   string   `json:"word"`
+	Lemmas []string `json:"lemmas"`
+}
+
+// lexemeResult provides lexeme command JSON output line.
+type lexemeResult struct {
+	Word    string         `json:"word"`
+	Lexemes []morph.Lexeme `json:"lexemes"`
+}
+
+// inflectResult provides inflect command JSON output line.
+type inflectResult struct {
+	Word  string       `json:"word"`
+	Forms []morph.Form `json:"forms"`
+}
+
+// grammeme provides tags command JSON output line.
+type grammeme struct {
+	Name   dag.TagName `json:"name"`
+	Parent dag.TagName `json:"parent"`
+}
+
+// runParse outputs word, lemma normal form, lemma ID and tags line per every word parse.
+func runParse(app *app, args []string) int {
+	flags := flag.NewFlagSet(app.name, flag.ContinueOnError)
+	if !app.parseFlags(flags, args) {
+		return exitUsage
+	}
+
+	if !app.load() {
+		return exitError
+	}
+
+	return app.eachWord(flags.Args(), func(output *bufio.Writer, word string) (bool, error) {
+		parses, err := app.analyzer.Parse(word)
+		if err != nil {
+			return false, err
+		}
+
+		if app.json {
+			return len(parses) > 0, writeJSON(output, parseResult{Word: word, Parses: nonNil(parses)})
+		}
+
+		for _, parse := range parses {
+			if _, err = fmt.Fprintf(output, "%s\t%s\t%d\t%s\n",
+				word, parse.Normal, parse.Lemma, morph.JoinTags(parse.Tags)); err != nil {
+				return false, err
+			}
+		}
+
+		return len(parses) > 0, nil
+	})
+}
+
+// runLemma outputs word and normal form line per every word lemma.
+func runLemma(app *app, args []string) int {
+	flags := flag.NewFlagSet(app.name, flag.ContinueOnError)
+	if !app.parseFlags(flags, args) {
+		return exitUsage
+	}
+
+	if !app.load() {
+		return exitError
+	}
+
+	return app.eachWord(flags.Args(), func(output *bufio.Writer, word string) (bool, error) {
+		normals, err := app.analyzer.Lemmas(word)
+		if err != nil {
+			return false, err
+		}
+
+		if app.json {
+			return len(normals) > 0, writeJSON(output, lemmaResult{Word: word, Lemmas: nonNil(normals)})
+		}
+
+		for _, normal := range normals {
+			if _, err = fmt.Fprintf(output, "%s\t%s\n", word, normal); err != nil {
+				return false, err
+			}
+		}
+
+		return len(normals) > 0, nil
+	})
+}
+
+// runInflect outputs word, form and form tags line per every form of word lemmas having requested grammemes.
+func runInflect(app *app, args []string) int {
+	flags := flag.NewFlagSet(app.name, flag.ContinueOnError)
+	grammemesList := flags.String("g", "", "comma separated grammemes forms must have, e.g. plur,gent")
+
+	if !app.parseFlags(flags, args) {
+		return exitUsage
+	}
+
+	grammemes := make([]dag.TagName, 0)
+	for _, name := range strings.Split(*grammemesList, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			grammemes = append(grammemes, dag.TagName(name))
+		}
+	}
+
+	if len(grammemes) == 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if !app.load() {
+		return exitError
+	}
+
+	return app.eachWord(flags.Args(), func(output *bufio.Writer, word string) (bool, error) {
+		forms, err := app.analyzer.Inflect(word, grammemes...)
+		if err != nil {
+			return false, err
+		}
+
+		if app.json {
+			return len(forms) > 0, writeJSON(output, inflectResult{Word: word, Forms: nonNil(forms)})
+		}
+
+		for _, form := range forms {
+			if _, err = fmt.Fprintf(output, "%s\t%s\t%s\n", word, form.Word, morph.JoinTags(form.Tags)); err != nil {
+				return false, err
+			}
+		}
+
+		return len(forms) > 0, nil
+	})
+}
+
+// runLexeme outputs word, lemma normal form, form and form tags line per every form of word lemmas.
+func runLexeme(app *app, args []string) int {
+	flags := flag.NewFlagSet(app.name, flag.ContinueOnError)
+	if !app.parseFlags(flags, args) {
+		return exitUsage
+	}
+
+	if !app.load() {
+		return exitError
+	}
+
+	return app.eachWord(flags.Args(), func(output *bufio.Writer, word string) (bool, error) {
+		lexemes, err := app.analyzer.Lexemes(word)
+		if err != nil {
+			return false, err
+		}
+
+		if app.json {
+			return len(lexemes) > 0, writeJSON(output, lexemeResult{Word: word, Lexemes: nonNil(lexemes)})
+		}
+
+		for _, lexeme := range lexemes {
+			for _, form := range lexeme.Forms {
+				if _, err = fmt.Fprintf(output, "%s\t%s\t%s\t%s\n",
+					word, lexeme.Normal, form.Word, morph.JoinTags(form.Tags)); err != nil {
+					return false, err
+				}
+			}
+		}
+
+		return len(lexemes) > 0, nil
+	})
+}
+
+// runTags outputs grammeme and its parent line per every known or requested grammeme.
+// Grammemes are requested by arguments only, input is not read.
+func runTags(app *app, args []string) int {
+	flags := flag.NewFlagSet(app.name, flag.ContinueOnError)
+	if !app.parseFlags(flags, args) {
+		return exitUsage
+	}
+
+	if !app.load() {
+		return exitError
+	}
+
+	known := make(map[string]grammeme)
+	names := make([]string, 0)
+
+	for _, tag := range app.analyzer.Index().Tags() {
+		parent := tag.Parent
+		if parent == dag.EmptyTagName {
+			parent = ""
+		}
+
+		known[strings.ToLower(string(tag.Name))] = grammeme{Name: tag.Name, Parent: parent}
+		names = append(names, string(tag.Name))
+	}
+
+	if flags.NArg() > 0 {
+		names = flags.Args()
+	}
+
+	output := bufio.NewWriter(app.output)
+	exitCode := exitOK
+
+	for _, name := range names {
+		found, ok := known[strings.ToLower(name)]
+		if !ok {
+			fmt.Fprintf(app.errors, "%s: `%s`: not found\n", app.name, name)
+			exitCode = exitNotFound
+
+			continue
+		}
+
+		var err error
+		if app.json {
+			err = writeJSON(output, found)
+		} else {
+			_, err = fmt.Fprintf(output, "%s\t%s\n", found.Name, found.Parent)
+		}
+
+		if err != nil {
+			fmt.Fprintf(app.errors, "%s: %v\n", app.name, err)
+			return exitError
+		}
+	}
+
+	if err := output.Flush(); err != nil {
+		fmt.Fprintf(app.errors, "%s: %v\n", app.name, err)
+		return exitError
+	}
+
+	return exitCode
+}
+
+// runStats outputs compiled index statistics as text or JSON object.
+func runStats(app *app, args []string) int {
+	flags := flag.NewFlagSet(app.name, flag.ContinueOnError)
+	top := flags.Int("n", 20, "count of most frequent tag sets listed, 0 to list all tag sets")
+
+	if !app.parseFlags(flags, args) {
+		return exitUsage
+	}
+
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if !app.load() {
+		return exitError
+	}
+
+	report, err := stats.Collect(app.analyzer.Index(), *top)
+	if err == nil && app.json {
+		err = report.WriteJSON(app.output)
+	} else if err == nil {
+		err = report.WriteText(app.output)
+	}
+
+	if err != nil {
+		fmt.Fprintf(app.errors, "%s: %v\n", app.name, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// runExport writes compiled index in requested format into file or output.
+// JSON lines format is taken by default if JSON output requested.
+func runExport(app *app, args []string) int {
+	defaultFormat := export.FormatTSV
+	if app.json {
+		defaultFormat = export.FormatJSONL
+	}
+
+	formatNames := make([]string, len(export.Formats))
+	for idx, format := range export.Formats {
+		formatNames[idx] = string(format)
+	}
+
+	flags := flag.NewFlagSet(app.name, flag.ContinueOnError)
+	formatName := flags.String("f", string(defaultFormat), "export format, one of "+strings.Join(formatNames, ", "))
+	outputFile := flags.String("o", "", "output file, standard output if not set")
+
+	if !app.parseFlags(flags, args) {
+		return exitUsage
+	}
+
+	format, err := export.ParseFormat(*formatName)
+	if err != nil || flags.NArg() > 0 {
+		flags.Usage()
+		return exitUsage
+	}
+
+	if !app.load() {
+		return exitError
+	}
+
+	if *outputFile == "" {
+		err = export.Write(app.output, app.analyzer.Index(), format)
+	} else {
+		err = exportFile(*outputFile, app, format)
+	}
+
+	if err != nil {
+		fmt.Fprintf(app.errors, "%s: %v\n", app.name, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// exportFile writes compiled index into file.
+func exportFile(fileName string, app *app, format export.Format) error {
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	if err = export.Write(file, app.analyzer.Index(), format); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// nonNil returns empty slice instead of nil one, so JSON output has empty array instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return make([]T, 0)
+	}
+
+	return items
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/amarin/logging"
+
+	"github.com/amarin/gomorphy/pkg/common"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+const (
+	programDescription = "Parse, lemmatize and inflect words, list grammemes, report statistics and export compiled index"
+)
+
+// Exit codes.
+const (
+	exitOK       = 0 // all words found
+	exitNotFound = 1 // some words or grammemes not found, found ones are output
+	exitUsage    = 2 // wrong command or flags
+	exitError    = 3 // index not loaded or output failed
+)
+
+// command defines gomorphy subcommand.
+type command struct {
+	args        string                            // command arguments usage
+	description string                            // command description
+	run         func(app *app, args []string) int // runs command returning exit code
+}
+
+// commands maps subcommands by names.
+var commands = map[string]command{
+	"parse": {
+		args:        "[word ...]",
+		description: "output tag sets and lemmas of every word",
+		run:         runParse,
+	},
+	"lemma": {
+		args:        "[word ...]",
+		description: "output normal forms of every word",
+		run:         runLemma,
+	},
+	"inflect": {
+		args:        "-g grammemes [word ...]",
+		description: "output forms of every word lemmas having all comma separated grammemes",
+		run:         runInflect,
+	},
+	"lexeme": {
+		args:        "[word ...]",
+		description: "output all forms of every word lemmas",
+		run:         runLexeme,
+	},
+	"tags": {
+		args:        "[grammeme ...]",
+		description: "output grammemes with their parents, all known grammemes if none specified",
+		run:         runTags,
+	},
+	"stats": {
+		args:        "[-n top]",
+		description: "output compiled index statistics",
+		run:         runStats,
+	},
+	"export": {
+		args:        "[-f format] [-o file]",
+		description: "export compiled index as TSV, JSON lines or OpenCorpora XML",
+		run:         runExport,
+	},
+}
+
+// usage prints usage of program having flags.
+func usage(program string, flags *flag.FlagSet) {
+	output := flags.Output()
+
+	fmt.Fprintf(output, "%s - %s\n\n", program, programDescription)
+	fmt.Fprintf(output, "Usage: %s [options] command [command options] [arguments]\n\n", program)
+	fmt.Fprintf(output, "Words are taken from arguments or read from standard input one per line if no arguments.\n")
+	fmt.Fprintf(output, "Exit code is %d if all words found, %d if some not found, %d on usage and %d on other errors.\n\n",
+		exitOK, exitNotFound, exitUsage, exitError)
+	fmt.Fprintf(output, "Commands:\n")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(output, "  %s %s\n    \t%s\n", name, commands[name].args, commands[name].description)
+	}
+
+	fmt.Fprintf(output, "\nOptions:\n")
+	flags.PrintDefaults()
+}
+
+func main() {
+	os.Exit(run(os.Args, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs command specified by program arguments args having program name first and returns exit code.
+// Words are read from input if no words in arguments, results are written to output,
+// usage, not found words and errors are written to errors output.
+func run(args []string, input io.Reader, output io.Writer, errors io.Writer) int {
+	options := opencorpora.OptionsFromEnv()
+	program := path.Base(args[0])
+	flags := flag.NewFlagSet(program, flag.ContinueOnError)
+
+	dataPath := flags.String(
+		"p",
+		options.DataPath,
+		"directory of compiled index, defaults to "+common.EnvDataPath+" environment variable or .data in working directory",
+	)
+	compiledFile := flags.String(
+		"c",
+		options.CompiledFile,
+		"compiled index file path, defaults to "+opencorpora.EnvCompiledFile+" environment variable or "+opencorpora.LocalCompiledFilename+" in data directory",
+	)
+	jsonOutput := flags.Bool(
+		"j",
+		false,
+		"output JSON object line per word instead of tab separated text",
+	)
+	debugLogging := flags.Bool(
+		"d",
+		false,
+		"switch on debug logging causes very noisy logging output",
+	)
+	usageOutput := flags.Bool(
+		"h",
+		false,
+		"Output this usage screen",
+	)
+
+	flags.SetOutput(errors)
+	flags.Usage = func() { usage(program, flags) }
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	if *usageOutput {
+		usage(program, flags)
+		return exitOK
+	}
+
+	selected, found := commands[flags.Arg(0)]
+	if !found {
+		usage(program, flags)
+		return exitUsage
+	}
+
+	logLevel := logging.LevelWarn
+	if *debugLogging {
+		logLevel = logging.LevelDebug
+	}
+
+	if err := logging.Init(logging.WithLevel(logLevel)); err != nil {
+		fmt.Fprintf(errors, "logging: init: %v\n", err)
+		return exitError
+	}
+
+	options.DataPath = *dataPath
+	options.CompiledFile = *compiledFile
+	options.Logger = logging.NewNamedLogger("gomorphy").WithLevel(logLevel)
+
+	return selected.run(&app{
+		program:  program,
+		name:     flags.Arg(0),
+		usage:    selected.args,
+		loader:   opencorpora.NewLoaderWithOptions(options),
+		analyzer: nil,
+		json:     *jsonOutput,
+		input:    input,
+		output:   output,
+		errors:   errors,
+	}, flags.Args()[1:])
+}
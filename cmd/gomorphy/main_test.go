@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/testdict"
+)
+
+// runTest runs program with arguments args having compiled test dictionary and words input.
+// Returns exit code, output and errors output.
+func runTest(t *testing.T, input string, args ...string) (int, string, string) {
+	t.Helper()
+
+	loader := testdict.Loader(t, testdict.Dictionary)
+	output := new(bytes.Buffer)
+	errors := new(bytes.Buffer)
+
+	exitCode := run(
+		append([]string{"gomorphy", "-c", loader.CompiledFile()}, args...),
+		strings.NewReader(input),
+		output,
+		errors,
+	)
+
+	return exitCode, output.String(), errors.String()
+}
+
+func TestRun(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		input      string
+		args       []string
+		wantCode   int
+		wantOutput string
+		wantErrors string
+	}{
+		{
+			name:       "lemma_arguments",
+			input:      "",
+			args:       []string{"lemma", "ежа", "стоят"},
+			wantCode:   exitOK,
+			wantOutput: "ежа\tёж\nстоят\tстоит\n",
+			wantErrors: "",
+		},
+		{
+			name:       "lemma_input",
+			input:      "ежи\n\n  кота  \n",
+			args:       []string{"lemma"},
+			wantCode:   exitOK,
+			wantOutput: "ежи\tёж\nкота\tкот\n",
+			wantErrors: "",
+		},
+		{
+			name:       "lemma_json",
+			input:      "",
+			args:       []string{"-j", "lemma", "ежа"},
+			wantCode:   exitOK,
+			wantOutput: "{\"word\":\"ежа\",\"lemmas\":[\"ёж\"]}\n",
+			wantErrors: "",
+		},
+		{
+			name:       "lemma_not_found",
+			input:      "ежа\nслон\nкот\n",
+			args:       []string{"lemma"},
+			wantCode:   exitNotFound,
+			wantOutput: "ежа\tёж\nкот\tкот\n",
+			wantErrors: "lemma: `слон`: not found\n",
+		},
+		{
+			name:       "lemma_json_not_found",
+			input:      "слон\n",
+			args:       []string{"-j", "lemma"},
+			wantCode:   exitNotFound,
+			wantOutput: "{\"word\":\"слон\",\"lemmas\":[]}\n",
+			wantErrors: "lemma: `слон`: not found\n",
+		},
+		{
+			name:       "tags_not_found",
+			input:      "",
+			args:       []string{"tags", "NOUN", "XXXX"},
+			wantCode:   exitNotFound,
+			wantOutput: "NOUN\tPOST\n",
+			wantErrors: "tags: `XXXX`: not found\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode, output, errors := runTest(t, tt.input, tt.args...)
+			require.Equal(t, tt.wantErrors, errors)
+			require.Equal(t, tt.wantOutput, output)
+			require.Equal(t, tt.wantCode, exitCode)
+		})
+	}
+}
+
+func TestRun_Help(t *testing.T) {
+	exitCode, output, errors := runTest(t, "", "-h")
+	require.Equal(t, exitOK, exitCode)
+	require.Empty(t, output)
+	require.Contains(t, errors, "Usage: gomorphy")
+	require.Contains(t, errors, "Exit code is 0 if all words found, 1 if some not found, 2 on usage and 3 on other errors.")
+}
+
+func TestRun_Usage(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		args       []string
+		wantErrors string
+	}{
+		{name: "no_command", args: []string{}, wantErrors: "Usage: gomorphy"},
+		{name: "unknown_command", args: []string{"unknown"}, wantErrors: "Usage: gomorphy"},
+		{name: "unknown_flag", args: []string{"-x", "lemma"}, wantErrors: "flag provided but not defined: -x"},
+		{name: "unknown_command_flag", args: []string{"lemma", "-x"}, wantErrors: "Usage: gomorphy lemma"},
+		{name: "inflect_no_grammemes", args: []string{"inflect", "ежа"}, wantErrors: "Usage: gomorphy inflect"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode, output, errors := runTest(t, "ежа\n", tt.args...)
+			require.Equal(t, exitUsage, exitCode)
+			require.Empty(t, output)
+			require.Contains(t, errors, tt.wantErrors)
+		})
+	}
+}
+
+func TestRun_Error(t *testing.T) {
+	output := new(bytes.Buffer)
+	errors := new(bytes.Buffer)
+
+	exitCode := run(
+		[]string{"gomorphy", "-c", filepath.Join(t.TempDir(), "missing.bin"), "lemma", "ежа"},
+		strings.NewReader(""),
+		output,
+		errors,
+	)
+
+	require.Equal(t, exitError, exitCode)
+	require.Empty(t, output.String())
+	require.Contains(t, errors.String(), "missing.bin")
+}
+
+func TestApp_eachWord(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		args       []string
+		input      string
+		wantCode   int
+		wantWords  []string
+		wantErrors string
+	}{
+		{
+			name:       "arguments_ignore_input",
+			args:       []string{"Ежа", "кот"},
+			input:      "слон\n",
+			wantCode:   exitOK,
+			wantWords:  []string{"ежа", "кот"},
+			wantErrors: "",
+		},
+		{
+			name:       "input_lines",
+			args:       []string{},
+			input:      " ЁЖ \n\nкот\r\n",
+			wantCode:   exitOK,
+			wantWords:  []string{"ёж", "кот"},
+			wantErrors: "",
+		},
+		{
+			name:       "not_found_continues",
+			args:       []string{},
+			input:      "слон\nкот\n",
+			wantCode:   exitNotFound,
+			wantWords:  []string{"слон", "кот"},
+			wantErrors: "test: `слон`: not found\n",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			errors := new(bytes.Buffer)
+			testApp := &app{
+				program:  "gomorphy",
+				name:     "test",
+				usage:    "",
+				loader:   nil,
+				analyzer: nil,
+				json:     false,
+				input:    strings.NewReader(tt.input),
+				output:   output,
+				errors:   errors,
+			}
+
+			words := make([]string, 0)
+			exitCode := testApp.eachWord(tt.args, func(output *bufio.Writer, word string) (bool, error) {
+				words = append(words, word)
+				_, err := output.WriteString(word + "\n")
+
+				return word != "слон", err
+			})
+
+			require.Equal(t, tt.wantCode, exitCode)
+			require.Equal(t, tt.wantWords, words)
+			require.Equal(t, strings.Join(tt.wantWords, "\n")+"\n", output.String())
+			require.Equal(t, tt.wantErrors, errors.String())
+		})
+	}
+}
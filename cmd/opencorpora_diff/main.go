@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/amarin/logging"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/diff"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+const (
+	programDescription = "Compare two compiled indexes and report added, removed and changed words and grammemes"
+)
+
+// readWords reads non-empty lines of words file.
+func readWords(fileName string) ([]string, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+
+	return words, scanner.Err()
+}
+
+func main() {
+	jsonOutput := flag.Bool(
+		"j",
+		false,
+		"output report as JSON object instead of human readable text",
+	)
+	wordsFile := flag.String(
+		"w",
+		"",
+		"compare only words listed in file one per line, e.g. top query words, all words compared if not set",
+	)
+	limit := flag.Int(
+		"n",
+		20,
+		"count of words listed in every text report section, 0 to list all words",
+	)
+	debugLogging := flag.Bool(
+		"d",
+		false,
+		"switch on debug logging causes very noisy logging output",
+	)
+	usageOutput := flag.Bool(
+		"h",
+		false,
+		"Output this usage screen",
+	)
+
+	flag.Parse()
+	if *usageOutput || flag.NArg() != 2 {
+		fmt.Fprintf(flag.CommandLine.Output(), "%s - %s\n\n", path.Base(os.Args[0]), programDescription)
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] old.dat new.dat\n\n", path.Base(os.Args[0]))
+		flag.PrintDefaults()
+
+		if *usageOutput {
+			os.Exit(0)
+		}
+
+		os.Exit(2)
+	}
+
+	loggingOpts := make([]logging.Option, 0)
+	if *debugLogging {
+		loggingOpts = append(loggingOpts, logging.WithLevel(logging.LevelDebug))
+	}
+	if err := logging.Init(loggingOpts...); err != nil {
+		fmt.Printf("logging: init: %v\n", err)
+		os.Exit(1)
+	}
+
+	var words []string
+
+	if *wordsFile != "" {
+		var err error
+		if words, err = readWords(*wordsFile); err != nil {
+			fmt.Printf("words: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	loader := opencorpora.NewLoader("")
+	indexes := make([]*index.ReadOnlyIndex, 0, 2)
+
+	for _, fileName := range flag.Args() {
+		loaded, err := loader.LoadIndexFile(fileName)
+		if err != nil {
+			fmt.Printf("load %v: %v\n", fileName, err)
+			os.Exit(1)
+		}
+
+		indexes = append(indexes, loaded)
+	}
+
+	report, err := diff.Compare(indexes[0], indexes[1], words...)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		err = report.WriteJSON(os.Stdout)
+	} else {
+		err = report.WriteText(os.Stdout, *limit)
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/amarin/logging"
+
+	"github.com/amarin/gomorphy/pkg/common"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+	"github.com/amarin/gomorphy/pkg/stats"
+)
+
+const (
+	programDescription = "Report compiled index statistics: counts, storage tables, ambiguity, frequent tag sets and sections sizes"
+)
+
+func main() {
+	options := opencorpora.OptionsFromEnv()
+
+	dataPath := flag.String(
+		"p",
+		options.DataPath,
+		"directory of compiled index, defaults to "+common.EnvDataPath+" environment variable or .data in working directory",
+	)
+	compiledFile := flag.String(
+		"c",
+		options.CompiledFile,
+		"compiled index file path, defaults to "+opencorpora.EnvCompiledFile+" environment variable or "+opencorpora.LocalCompiledFilename+" in data directory",
+	)
+	textOutput := flag.Bool(
+		"t",
+		false,
+		"output report as human readable text instead of JSON object",
+	)
+	top := flag.Int(
+		"n",
+		20,
+		"count of most frequent tag sets listed, 0 to list all tag sets",
+	)
+	debugLogging := flag.Bool(
+		"d",
+		false,
+		"switch on debug logging causes very noisy logging output",
+	)
+	usageOutput := flag.Bool(
+		"h",
+		false,
+		"Output this usage screen",
+	)
+
+	flag.Parse()
+	if *usageOutput || flag.NArg() > 1 {
+		fmt.Fprintf(flag.CommandLine.Output(), "%s - %s\n\n", path.Base(os.Args[0]), programDescription)
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options] [index.dat]\n\n", path.Base(os.Args[0]))
+		flag.PrintDefaults()
+
+		if *usageOutput {
+			os.Exit(0)
+		}
+
+		os.Exit(2)
+	}
+
+	loggingOpts := make([]logging.Option, 0)
+	if *debugLogging {
+		loggingOpts = append(loggingOpts, logging.WithLevel(logging.LevelDebug))
+	}
+	if err := logging.Init(loggingOpts...); err != nil {
+		fmt.Printf("logging: init: %v\n", err)
+		os.Exit(1)
+	}
+
+	options.DataPath = *dataPath
+	options.CompiledFile = *compiledFile
+	loader := opencorpora.NewLoaderWithOptions(options)
+
+	fileName := loader.CompiledFile()
+	if flag.NArg() == 1 {
+		fileName = flag.Arg(0)
+	}
+
+	loaded, err := loader.LoadIndexFile(fileName)
+	if err != nil {
+		fmt.Printf("load %v: %v\n", fileName, err)
+		os.Exit(1)
+	}
+
+	report, err := stats.Collect(loaded, *top)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *textOutput {
+		err = report.WriteText(os.Stdout)
+	} else {
+		err = report.WriteJSON(os.Stdout)
+	}
+
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
@@ -1,19 +1,25 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/amarin/logging"
 	"github.com/chzyer/readline"
 
 	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/common"
 	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/export"
+	"github.com/amarin/gomorphy/pkg/morph"
 	"github.com/amarin/gomorphy/pkg/opencorpora"
 )
 
@@ -30,18 +36,26 @@ const (
 	cmdNodeShort   = "n"
 	cmdReload      = "reload"
 	cmdReloadShort = "r"
+	cmdVerify      = "verify"
+	cmdVerifyShort = "vf"
+	cmdExport      = "export"
+	cmdExportShort = "ex"
 )
 
 var (
-	idx *index.Index
-
-	ErrTag  = errors.New(cmdTag)
-	ErrSet  = errors.New(cmdSet)
-	ErrVar  = errors.New(cmdVar)
-	ErrNode = errors.New(cmdNode)
+	handle *morph.Handle
+
+	ErrTag    = errors.New(cmdTag)
+	ErrSet    = errors.New(cmdSet)
+	ErrVar    = errors.New(cmdVar)
+	ErrNode   = errors.New(cmdNode)
+	ErrReload = errors.New(cmdReload)
+	ErrVerify = errors.New(cmdVerify)
+	ErrExport = errors.New(cmdExport)
 )
 
 func processSearch(logger logging.Logger, line string) {
+	idx := handle.Index()
 	var (
 		forms dag.Node
 		err   error
@@ -65,6 +79,7 @@ func processSearch(logger logging.Logger, line string) {
 }
 
 func processTag(logger logging.Logger, items ...string) error {
+	idx := handle.Index()
 	const (
 		subCmdCount = "count"
 	)
@@ -105,6 +120,7 @@ func processTag(logger logging.Logger, items ...string) error {
 }
 
 func processSet(logger logging.Logger, items ...string) error {
+	idx := handle.Index()
 	const (
 		subCmdTables = "tables"
 		subCmdTable  = "table"
@@ -182,6 +198,7 @@ func processSet(logger logging.Logger, items ...string) error {
 }
 
 func processVar(logger logging.Logger, items ...string) error {
+	idx := handle.Index()
 	const subCmdID = "id"
 
 	if len(items) < 2 {
@@ -231,6 +248,7 @@ func processVar(logger logging.Logger, items ...string) error {
 }
 
 func processNode(logger logging.Logger, items ...string) error {
+	idx := handle.Index()
 	const (
 		subCmdCount = "count"
 		subCmdInfo  = "info"
@@ -249,7 +267,7 @@ func processNode(logger logging.Logger, items ...string) error {
 	default:
 		var (
 			item dag.Node
-			node *index.Node
+			node *index.ReadOnlyNode
 		)
 		if len(items) != 2 {
 			return fmt.Errorf("%w.%v: 2 items required", ErrNode, subCmdInfo)
@@ -330,14 +348,66 @@ func processNode(logger logging.Logger, items ...string) error {
 	}
 }
 
-func processReload(logger logging.Logger) (err error) {
+func processReload(logger logging.Logger) error {
 	logger.Infof("reloading index")
-	loader := opencorpora.NewLoader("")
-	if idx, err = loader.LoadIndex(); err != nil {
-		logger.Error("load index: %v", err)
-		os.Exit(1)
+	if err := handle.Reload(); err != nil {
+		return fmt.Errorf("%w: %v", ErrReload, err)
+	}
+
+	return nil
+}
+
+func processVerify(logger logging.Logger) error {
+	idx := handle.Index()
+	started := time.Now()
+	if err := idx.Verify(); err != nil {
+		var verifyError *index.VerifyError
+		if !errors.As(err, &verifyError) {
+			return fmt.Errorf("%w: %v", ErrVerify, err)
+		}
+
+		for _, problem := range verifyError.Problems {
+			fmt.Printf("- %v\n", problem)
+		}
+
+		return fmt.Errorf("%w: %d problems found, eta %v", ErrVerify, len(verifyError.Problems), time.Since(started))
 	}
 
+	logger.Infof("%v: ok, eta %v", cmdVerify, time.Since(started))
+
+	return nil
+}
+
+func processExport(logger logging.Logger, items ...string) (err error) {
+	idx := handle.Index()
+	var file *os.File
+
+	if len(items) != 3 {
+		return fmt.Errorf("%w: expected format and file: %v", ErrExport, export.Formats)
+	}
+
+	format, err := export.ParseFormat(items[1])
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrExport, err)
+	}
+
+	started := time.Now()
+	if file, err = os.Create(items[2]); err != nil {
+		return fmt.Errorf("%w: %v", ErrExport, err)
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("%w: %v", ErrExport, closeErr)
+		}
+	}()
+
+	if err = export.Write(file, idx, format); err != nil {
+		return fmt.Errorf("%w: %v", ErrExport, err)
+	}
+
+	logger.Infof("%v: %v written to %v, eta %v", cmdExport, format, items[2], time.Since(started))
+
 	return nil
 }
 
@@ -357,6 +427,10 @@ func processInput(logger logging.Logger, line string) {
 		err = processVar(logger, items...)
 	case cmdReload, cmdReloadShort:
 		err = processReload(logger)
+	case cmdVerify, cmdVerifyShort:
+		err = processVerify(logger)
+	case cmdExport, cmdExportShort:
+		err = processExport(logger, items...)
 	case cmdExit, cmdExitShort:
 		logger.Infof("exiting")
 		os.Exit(0)
@@ -379,6 +453,29 @@ func main() {
 		line   string
 	)
 
+	options := opencorpora.OptionsFromEnv()
+	dataPath := flag.String(
+		"p",
+		options.DataPath,
+		"directory of compiled index, defaults to "+common.EnvDataPath+" environment variable or .data in working directory",
+	)
+	compiledFile := flag.String(
+		"c",
+		options.CompiledFile,
+		"compiled index file path, defaults to "+opencorpora.EnvCompiledFile+" environment variable or "+opencorpora.LocalCompiledFilename+" in data directory",
+	)
+
+	watchInterval := flag.Duration(
+		"w",
+		0,
+		"check compiled index file every interval and reload it when changed, e.g. 1m, disabled if 0",
+	)
+
+	flag.Parse()
+
+	options.DataPath = *dataPath
+	options.CompiledFile = *compiledFile
+
 	if err = logging.Init(logging.WithLevel(logging.LevelDebug)); err != nil {
 		fmt.Printf("logging: init: %v\n", err)
 		os.Exit(1)
@@ -387,14 +484,23 @@ func main() {
 	logger = logging.NewNamedLogger("opencorpora")
 
 	started := time.Now()
-	loader := opencorpora.NewLoader("")
-	if idx, err = loader.LoadIndex(); err != nil {
-		logger.Error("load index: %v", err)
+	loader := opencorpora.NewLoaderWithOptions(options)
+	if handle, err = morph.NewLoaderHandle(loader); err != nil {
+		logger.Errorf("load index: %v", err)
 		os.Exit(1)
 	}
 
 	logger.Debug("loaded in ", time.Since(started))
-	logger.Debugf("indexed %d words %d nodes", idx.WordsCount(), idx.NodesCount())
+	logger.Debugf("indexed %d words %d nodes", handle.Index().WordsCount(), handle.Index().NodesCount())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go handle.WatchSignals(ctx, syscall.SIGHUP)
+
+	if *watchInterval > 0 {
+		go handle.WatchFile(ctx, loader.CompiledFile(), *watchInterval)
+	}
 
 	if rl, err = readline.New("> "); err != nil {
 		logger.Error("readline: %v", err)
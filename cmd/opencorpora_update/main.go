@@ -1,31 +1,85 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path"
+	"runtime"
+	"syscall"
 
 	"github.com/amarin/logging"
 
+	"github.com/amarin/gomorphy/pkg/common"
 	"github.com/amarin/gomorphy/pkg/opencorpora"
+	"github.com/amarin/gomorphy/pkg/stats"
 )
 
 const (
 	programDescription = "Load and/or build compiled index from downloaded opencorpora.ru dictionary"
+	statsTopTagSets    = 20 // count of most frequent tag sets listed in statistics report
 )
 
 func main() {
+	options := opencorpora.OptionsFromEnv()
+
+	dataPath := flag.String(
+		"p",
+		options.DataPath,
+		"directory of downloaded, unpacked and compiled files, defaults to "+common.EnvDataPath+" environment variable or .data in working directory",
+	)
+	compiledFile := flag.String(
+		"c",
+		options.CompiledFile,
+		"compiled index file path, defaults to "+opencorpora.EnvCompiledFile+" environment variable or "+opencorpora.LocalCompiledFilename+" in data directory",
+	)
+	sourceURL := flag.String(
+		"u",
+		options.SourceURL,
+		"dictionary download URL, defaults to "+opencorpora.EnvSourceURL+" environment variable or "+opencorpora.RemoteURL,
+	)
+	retries := flag.Int(
+		"r",
+		opencorpora.DefaultRetries,
+		"count of download retries on network and server errors, interrupted download is resumed by retry",
+	)
+	checksum := flag.String(
+		"s",
+		options.Checksum,
+		"expected SHA-256 hex digest of downloaded dictionary, defaults to "+opencorpora.EnvChecksum+" environment variable, not verified if empty",
+	)
+	compression := flag.String(
+		"z",
+		string(options.Compression),
+		"compiled index compression: none, gzip or zstd if built with zstd tag, defaults to "+opencorpora.EnvCompression+" environment variable or none",
+	)
 	forceRecompile := flag.Bool(
 		"f",
 		true,
-		"force rebuild index from previously downloaded data even if compiled index already present",
+		"force rebuild index from previously downloaded data even if compiled index already present, set false to apply only changed lemmas to compiled index",
 	)
 	debugLogging := flag.Bool(
 		"d",
 		false,
 		"switch on debug logging causes very noisy logging output",
 	)
+	workers := flag.Int(
+		"w",
+		runtime.NumCPU(),
+		"count of goroutines filling index while compiling, 1 to compile sequentially",
+	)
+	textDump := flag.String(
+		"t",
+		"",
+		"compile index from OpenCorpora plain-text dump file instead of downloaded XML dictionary, grammemes are taken from previously downloaded XML dictionary if present",
+	)
+	statsFile := flag.String(
+		"j",
+		"",
+		"write compiled index statistics JSON report into file after compilation, not written if empty",
+	)
 	usageOutput := flag.Bool(
 		"h",
 		false,
@@ -48,11 +102,74 @@ func main() {
 		os.Exit(1)
 	}
 
-	loader := opencorpora.NewLoader("")
+	options.DataPath = *dataPath
+	options.CompiledFile = *compiledFile
+	options.SourceURL = *sourceURL
+	options.Checksum = *checksum
+
+	options.Retries = *retries
+	if options.Retries == 0 {
+		options.Retries = -1 // zero retries requested, not default ones
+	}
+
+	compressionValue, err := opencorpora.ParseCompression(*compression)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	options.Compression = compressionValue
 
-	if err := loader.Update(*forceRecompile); err != nil {
+	loader := opencorpora.NewLoaderWithOptions(options)
+	loader.SetWorkers(*workers)
+
+	// interrupted build keeps compiled index untouched, interrupted download is resumed by next run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	if *textDump != "" {
+		err = loader.CompileContext(ctx, *textDump, *forceRecompile)
+	} else {
+		err = loader.UpdateContext(ctx, *forceRecompile)
+	}
+
+	stop()
+
+	if err != nil {
 		os.Exit(1)
 	}
 
+	if *statsFile != "" {
+		if err = writeStats(loader, *statsFile); err != nil {
+			fmt.Printf("stats: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	os.Exit(0)
 }
+
+// writeStats writes statistics JSON report of loader compiled index into file.
+func writeStats(loader *opencorpora.Loader, fileName string) (err error) {
+	loaded, err := loader.LoadIndex()
+	if err != nil {
+		return err
+	}
+
+	report, err := stats.Collect(loaded, statsTopTagSets)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	return report.WriteJSON(file)
+}
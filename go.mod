@@ -7,6 +7,7 @@ require (
 	github.com/amarin/libxml v0.1.2
 	github.com/amarin/logging v0.1.0
 	github.com/chzyer/readline v0.0.0-20180603132655-2972be24d48e
+	github.com/klauspost/compress v1.18.0
 	github.com/stretchr/testify v1.8.4
 )
 
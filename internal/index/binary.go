@@ -0,0 +1,371 @@
+package index
+
+import (
+	"fmt"
+
+	"github.com/amarin/binutils"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/storage"
+)
+
+const (
+	binaryFormatPrefix   = "FV"
+	binaryTagsPrefix     = "TD"
+	binaryColIdxPrefix   = "CD"
+	binaryItemsIdxPrefix = "ID"
+	binaryLemmasPrefix   = "LD"
+)
+
+// FormatVersion identifies revision of compiled index binary format.
+type FormatVersion uint8
+
+const (
+	// FormatLegacy identifies original headerless format using single byte counts and tag IDs.
+	FormatLegacy FormatVersion = 1
+	// FormatVarint identifies format having version header and using variable-length counts and tag IDs.
+	FormatVarint FormatVersion = 2
+	// FormatTagNames identifies FormatVarint revision having variable-length UTF-8 tag names.
+	FormatTagNames FormatVersion = 3
+	// FormatLemmas identifies FormatTagNames revision having dictionary lemmas section with lemmas normal forms.
+	FormatLemmas FormatVersion = 4
+	// FormatCurrent identifies format used to write index.
+	FormatCurrent = FormatLemmas
+)
+
+// readCount reads count or ID value written in specified format.
+// Legacy format uses single byte values, later formats use varints limited by specified maximum.
+func readCount(reader *binutils.BinaryReader, format FormatVersion, maxValue uint64) (int, error) {
+	if format == FormatLegacy {
+		value, err := reader.ReadUint8()
+
+		return int(value), err
+	}
+
+	value, err := storage.ReadUvarintMax(reader, maxValue)
+
+	return int(value), err
+}
+
+// BinaryWriteTo writes index data using specified binutils.BinaryWriter.
+// Data is always written in FormatCurrent.
+// Implements binutils.BinaryWriterTo.
+func (index *ReadOnlyIndex) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
+	if err = writeFormatHeader(writer); err != nil {
+		return err
+	}
+	if err = index.writeTagsDefinitions(writer); err != nil {
+		return err
+	}
+	if err = index.writeTagSetsDefinitions(writer); err != nil {
+		return err
+	}
+	if err = index.writeCollectionsDefinitions(writer); err != nil {
+		return err
+	}
+	if err = index.writeItemsDefinitions(writer); err != nil {
+		return err
+	}
+	if err = index.writeLemmasDefinitions(writer); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeFormatHeader writes FormatCurrent header into specified binutils.BinaryWriter.
+// A companion of readFormatHeader.
+// Used from BinaryWriteTo.
+func writeFormatHeader(writer *binutils.BinaryWriter) (err error) {
+	if err = writer.WriteStringZ(binaryFormatPrefix); err != nil {
+		return fmt.Errorf("%w: write: format prefix: %v", Error, err)
+	}
+	if err = writer.WriteUint8(uint8(FormatCurrent)); err != nil {
+		return fmt.Errorf("%w: write: format version: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readFormatHeader reads format header from specified binutils.BinaryReader.
+// Legacy format has no header and starts with tags section, so its prefix is taken here.
+// Returns detected format and the first section prefix following header.
+// A companion of writeFormatHeader.
+// Used from BinaryReadFrom.
+func readFormatHeader(reader *binutils.BinaryReader) (format FormatVersion, section string, err error) {
+	var version uint8
+
+	if section, err = reader.ReadStringZ(); err != nil {
+		return 0, "", fmt.Errorf("%w: read: format prefix: %v", Error, err)
+	}
+	if section != binaryFormatPrefix {
+		return FormatLegacy, section, nil
+	}
+
+	if version, err = reader.ReadUint8(); err != nil {
+		return 0, "", fmt.Errorf("%w: read: format version: %v", Error, err)
+	}
+
+	format = FormatVersion(version)
+	if format <= FormatLegacy || format > FormatCurrent {
+		return 0, "", fmt.Errorf("%w: read: unsupported format version %d", Error, version)
+	}
+
+	if section, err = reader.ReadStringZ(); err != nil {
+		return 0, "", fmt.Errorf("%w: read: tags prefix: %v", Error, err)
+	}
+
+	return format, section, nil
+}
+
+// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
+// A companion of readTagsDefinitions.
+// Used from BinaryWriteTo.
+func (index *ReadOnlyIndex) writeTagsDefinitions(writer *binutils.BinaryWriter) (err error) {
+	if err = writer.WriteStringZ(binaryTagsPrefix); err != nil {
+		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
+	}
+	if err = index.tags.BinaryWriteTo(writer); err != nil {
+		return fmt.Errorf("%w: write: tags index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readTagsDefinitions reads tags index written in specified format from binutils.BinaryReader.
+// Tags section prefix is taken by readFormatHeader, so it is passed here to check.
+// A companion of writeTagsDefinitions.
+// Used from BinaryReadFrom.
+func (index *ReadOnlyIndex) readTagsDefinitions(
+	reader *binutils.BinaryReader, format FormatVersion, section string,
+) (err error) {
+	if section != binaryTagsPrefix {
+		return fmt.Errorf("%w: read: expected section %v ", Error, binaryTagsPrefix)
+	}
+
+	if format < FormatTagNames {
+		err = index.readFixedTags(reader, format)
+	} else {
+		err = index.tags.BinaryReadFrom(reader)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: read: tags index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readFixedTags reads tags index written in formats having fixed-width 4 bytes tag names.
+// Used from readTagsDefinitions.
+func (index *ReadOnlyIndex) readFixedTags(reader *binutils.BinaryReader, format FormatVersion) (err error) {
+	var tagsCount int
+
+	if tagsCount, err = readCount(reader, format, dag.MaxTagsCount); err != nil {
+		return fmt.Errorf("%w: read: tags count: %v", Error, err)
+	}
+
+	index.tags = make(dag.Idx, tagsCount)
+
+	for idx := range index.tags {
+		if err = index.tags[idx].BinaryReadFixedFrom(reader); err != nil {
+			return fmt.Errorf("%w: read: tag %d: %v", Error, idx, err)
+		}
+	}
+
+	return nil
+}
+
+// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
+// A companion of readTagsDefinitions.
+// Used from BinaryWriteTo.
+func (index *ReadOnlyIndex) writeTagSetsDefinitions(writer *binutils.BinaryWriter) (err error) {
+	if err = writer.WriteStringZ(binaryTagSetPrefix); err != nil {
+		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
+	}
+	if err = index.tagSets.BinaryWriteTo(writer); err != nil {
+		return fmt.Errorf("%w: write: tags sets index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readTagsDefinitions reads tags index from specified binutils.BinaryReader.
+// A companion of writeTagsDefinitions.
+// Used from BinaryReadFrom.
+func (index *ReadOnlyIndex) readTagSetsDefinitions(reader *binutils.BinaryReader, format FormatVersion) (err error) {
+	var section string
+
+	if section, err = reader.ReadStringZ(); err != nil {
+		return fmt.Errorf("%w: read: tags prefix: %v", Error, err)
+	}
+	if section != binaryTagSetPrefix {
+		return fmt.Errorf("%w: read: expected section %v ", Error, binaryTagSetPrefix)
+	}
+
+	if err = index.tagSets.binaryReadFrom(reader, format); err != nil {
+		return fmt.Errorf("%w: write: tags sets index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
+// A companion of readTagsDefinitions.
+// Used from BinaryWriteTo.
+func (index *ReadOnlyIndex) writeCollectionsDefinitions(writer *binutils.BinaryWriter) (err error) {
+	if err = writer.WriteStringZ(binaryColIdxPrefix); err != nil {
+		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
+	}
+	if err = index.collectionIdx.BinaryWriteTo(writer); err != nil {
+		return fmt.Errorf("%w: write: tags set collections index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readTagsDefinitions reads tags index from specified binutils.BinaryReader.
+// A companion of writeTagsDefinitions.
+// Used from BinaryReadFrom.
+func (index *ReadOnlyIndex) readCollectionsDefinitions(
+	reader *binutils.BinaryReader, format FormatVersion,
+) (err error) {
+	var section string
+
+	if section, err = reader.ReadStringZ(); err != nil {
+		return fmt.Errorf("%w: read: tags prefix: %v", Error, err)
+	}
+	if section != binaryColIdxPrefix {
+		return fmt.Errorf("%w: read: expected section %v ", Error, binaryColIdxPrefix)
+	}
+
+	if err = index.collectionIdx.binaryReadFrom(reader, format); err != nil {
+		return fmt.Errorf("%w: write: tags set collections index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
+// A companion of readTagsDefinitions.
+// Used from BinaryWriteTo.
+func (index *ReadOnlyIndex) writeItemsDefinitions(writer *binutils.BinaryWriter) (err error) {
+	if err = writer.WriteStringZ(binaryItemsIdxPrefix); err != nil {
+		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
+	}
+	items := Items{items: index.items, mu: nil, nextID: dag.ID(len(index.items))}
+	if err = items.BinaryWriteTo(writer); err != nil {
+		return fmt.Errorf("%w: write: tags set collections index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readTagsDefinitions reads tags index from specified binutils.BinaryReader.
+// A companion of writeTagsDefinitions.
+// Used from BinaryReadFrom.
+func (index *ReadOnlyIndex) readItemsDefinitions(reader *binutils.BinaryReader) (err error) {
+	var section string
+
+	if section, err = reader.ReadStringZ(); err != nil {
+		return fmt.Errorf("%w: read: tags prefix: %v", Error, err)
+	}
+	if section != binaryItemsIdxPrefix {
+		return fmt.Errorf("%w: read: expected section %v ", Error, binaryItemsIdxPrefix)
+	}
+
+	items := NewItems()
+	if err = items.BinaryReadFrom(reader); err != nil {
+		return fmt.Errorf("%w: read: node index: %v", Error, err)
+	}
+
+	index.items = items.items[:items.NextID()]
+
+	return nil
+}
+
+// writeLemmasDefinitions writes lemmas index into specified binutils.BinaryWriter.
+// A companion of readLemmasDefinitions.
+// Used from BinaryWriteTo.
+func (index *ReadOnlyIndex) writeLemmasDefinitions(writer *binutils.BinaryWriter) (err error) {
+	if err = writer.WriteStringZ(binaryLemmasPrefix); err != nil {
+		return fmt.Errorf("%w: write: lemmas prefix: %v", Error, err)
+	}
+	if err = index.lemmas.BinaryWriteTo(writer); err != nil {
+		return fmt.Errorf("%w: write: lemmas index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readLemmasDefinitions reads lemmas index written in specified format from binutils.BinaryReader.
+// Formats before FormatLemmas have no lemmas section, so lemmas index is empty then.
+// A companion of writeLemmasDefinitions.
+// Used from BinaryReadFrom.
+func (index *ReadOnlyIndex) readLemmasDefinitions(reader *binutils.BinaryReader, format FormatVersion) (err error) {
+	var section string
+
+	index.lemmas = make(LemmaIndex)
+	if format < FormatLemmas {
+		return nil
+	}
+
+	if section, err = reader.ReadStringZ(); err != nil {
+		return fmt.Errorf("%w: read: lemmas prefix: %v", Error, err)
+	}
+	if section != binaryLemmasPrefix {
+		return fmt.Errorf("%w: read: expected section %v ", Error, binaryLemmasPrefix)
+	}
+
+	if err = index.lemmas.BinaryReadFrom(reader); err != nil {
+		return fmt.Errorf("%w: read: lemmas index: %v", Error, err)
+	}
+
+	return nil
+}
+
+// rebuildChildrenIndex builds compacted children relations and counts words using loaded items.
+func (index *ReadOnlyIndex) rebuildChildrenIndex() {
+	index.children = BuildChildIndex(index.items)
+	index.wordsCount = 0
+
+	for _, item := range index.items {
+		if item.Variants != 0 {
+			index.wordsCount++
+		}
+	}
+}
+
+// BinaryReadFrom reads index data from specified binutils.BinaryReader.
+// Format is detected automatically, data written in any known FormatVersion is accepted.
+// Implements binutils.BinaryReaderFrom.
+// Note that ReadOnlyIndex must not be used by readers until BinaryReadFrom finished.
+func (index *ReadOnlyIndex) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	var (
+		format  FormatVersion
+		section string
+	)
+
+	if format, section, err = readFormatHeader(reader); err != nil {
+		return err
+	}
+	if err = index.readTagsDefinitions(reader, format, section); err != nil {
+		return fmt.Errorf("%w: read: tags: %v", Error, err)
+	}
+	if err = index.readTagSetsDefinitions(reader, format); err != nil {
+		return fmt.Errorf("%w: read: tags: %v", Error, err)
+	}
+	if err = index.readCollectionsDefinitions(reader, format); err != nil {
+		return fmt.Errorf("%w: read: collections index: %v", Error, err)
+	}
+	if err = index.readItemsDefinitions(reader); err != nil {
+		return err
+	}
+	if err = index.readLemmasDefinitions(reader, format); err != nil {
+		return err
+	}
+
+	index.rebuildChildrenIndex()
+
+	return nil
+}
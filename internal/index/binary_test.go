@@ -0,0 +1,140 @@
+package index_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func tagSetsStrings(node dag.Node) (res []string) {
+	for _, tagSet := range node.TagSets() {
+		res = append(res, tagSet.String())
+	}
+
+	return res
+}
+
+func TestReadOnlyIndex_BinaryReadFrom_PreviousFormats(t *testing.T) {
+	for _, fileName := range []string{
+		"testdata/legacy.idx",   // FormatLegacy
+		"testdata/varint.idx",   // FormatVarint
+		"testdata/tagnames.idx", // FormatTagNames
+	} {
+		fileName := fileName
+		t.Run(fileName, func(t *testing.T) {
+			reader, err := binutils.OpenFile(fileName)
+			require.NoError(t, err)
+			defer func() { require.NoError(t, reader.Close()) }()
+
+			previous := new(index.ReadOnlyIndex)
+			require.NoError(t, previous.BinaryReadFrom(reader))
+			require.Equal(t, 9, previous.Tags().Len())
+			require.Equal(t, 6, previous.WordsCount())
+
+			for word, expected := range map[string][]string{
+				"кот":   {"NOUN,sing,nomn"},
+				"котов": {"NOUN,plur,gent"},
+				"леса":  {"NOUN,sing,gent", "NOUN,plur,nomn"},
+			} {
+				node, err := previous.FetchString(word)
+				require.NoError(t, err, word)
+				require.ElementsMatch(t, expected, tagSetsStrings(node), word)
+			}
+
+			// index is written in current format and loaded back the same
+			buffer := new(bytes.Buffer)
+			require.NoError(t, previous.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+			loaded := new(index.ReadOnlyIndex)
+			require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+			require.Equal(t, previous.Tags(), loaded.Tags())
+			require.Equal(t, previous.WordsCount(), loaded.WordsCount())
+			node, err := loaded.FetchString("леса")
+			require.NoError(t, err)
+			require.ElementsMatch(t, []string{"NOUN,sing,gent", "NOUN,plur,nomn"}, tagSetsStrings(node))
+		})
+	}
+}
+
+func TestReadOnlyIndex_BinaryWriteTo_TagNames(t *testing.T) {
+	idx := index.NewBuilder()
+	idx.TagID("Abbr", "")
+	idx.TagID("Brand", "")
+	idx.TagID("Sku", "Brand")
+	idx.TagID("Товар", "")
+	require.NoError(t, idx.AddTagSet("gomorphy", "Brand", "Sku", "Товар"))
+	require.NoError(t, idx.AddTagSet("гм", "Abbr"))
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, idx.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	loaded := new(index.ReadOnlyIndex)
+	require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+	require.Equal(t, idx.Tags(), loaded.Tags())
+
+	node, err := loaded.FetchString("gomorphy")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Brand,Sku,Товар"}, tagSetsStrings(node))
+
+	node, err = loaded.FetchString("гм")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Abbr"}, tagSetsStrings(node))
+}
+
+func TestReadOnlyIndex_BinaryWriteTo_Wide(t *testing.T) {
+	const (
+		tagsCount     = 300 // over single byte tags limit
+		variantsCount = 300 // over single byte variants limit
+	)
+
+	idx := index.NewBuilder()
+	for tagIdx := 0; tagIdx < tagsCount; tagIdx++ {
+		idx.TagID(dag.TagName(fmt.Sprintf("%04d", tagIdx)), "")
+	}
+
+	allTags := make([]dag.TagName, 0, tagsCount)
+	for _, tag := range idx.Tags() {
+		allTags = append(allTags, tag.Name)
+	}
+
+	require.NoError(t, idx.AddTagSet("длинный", allTags...))
+
+	for variantIdx := 0; variantIdx < variantsCount; variantIdx++ {
+		require.NoError(t, idx.AddTagSet("многозначный", allTags[variantIdx], allTags[tagsCount-1]))
+	}
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, idx.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	loaded := new(index.ReadOnlyIndex)
+	require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+	require.Equal(t, tagsCount, loaded.Tags().Len())
+
+	node, err := loaded.FetchString("длинный")
+	require.NoError(t, err)
+	require.Len(t, node.TagSets(), 1)
+	require.Len(t, node.TagSets()[0], tagsCount)
+	require.Equal(t, dag.TagName("0299"), node.TagSets()[0][tagsCount-1].Name)
+
+	node, err = loaded.FetchString("многозначный")
+	require.NoError(t, err)
+	require.Len(t, node.TagSets(), variantsCount)
+}
+
+func TestReadOnlyIndex_BinaryReadFrom_UnknownFormat(t *testing.T) {
+	for _, version := range []uint8{0, uint8(index.FormatLegacy), uint8(index.FormatCurrent) + 1} {
+		buffer := new(bytes.Buffer)
+		writer := binutils.NewBinaryWriter(buffer)
+		require.NoError(t, writer.WriteStringZ("FV"))
+		require.NoError(t, writer.WriteUint8(version))
+
+		err := new(index.ReadOnlyIndex).BinaryReadFrom(binutils.NewBinaryReader(buffer))
+		require.ErrorIs(t, err, index.Error, "version %d", version)
+	}
+}
@@ -0,0 +1,233 @@
+package index
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// canonicalize renumbers tag sets, variants collections and items into canonical order.
+// Canonical order depends only on index content but not on the order words and tag sets were added,
+// so indexes having the same content are always written into the same bytes.
+// Tag sets and collections are ordered by their content within each table,
+// items are numbered in depth-first order visiting children in letters order.
+// Tag sets not used by any collection and items having no words in their subtree are dropped.
+// Lemma forms refer renumbered items and tag sets, so they are renumbered and sorted too,
+// lemma normal form is kept separately.
+// Returns error if any collection refers unknown tag set.
+func (index *IndexBuilder) canonicalize() error {
+	tagSetReplacements := index.canonicalizeTagSets()
+
+	variantReplacements, err := index.canonicalizeCollections(tagSetReplacements)
+	if err != nil {
+		return err
+	}
+
+	itemReplacements := index.canonicalizeItems(variantReplacements)
+	index.canonicalizeLemmas(tagSetReplacements, itemReplacements)
+
+	return nil
+}
+
+// canonicalizeTagSets drops TagSet's not used by any collection and sorts remaining ones
+// in each TagSetTable by content.
+// Returns replacements map of old TagSetID to new one, dropped TagSet's have no replacements.
+func (index *IndexBuilder) canonicalizeTagSets() map[TagSetID]TagSetID {
+	replacements := make(map[TagSetID]TagSetID, index.tagSets.Size())
+	used := make(map[TagSetID]bool, index.tagSets.Size())
+
+	for _, table := range index.collectionIdx {
+		for _, collection := range table {
+			for _, tagSetID := range collection {
+				used[tagSetID] = true
+			}
+		}
+	}
+
+	for tableIdx, table := range index.tagSets {
+		tableNumber := TagSetTableNumber(tableIdx)
+		order := make([]int, 0, len(table))
+
+		for position := range table {
+			if used[tableNumber.TagSetID(TagSetSubID(position))] {
+				order = append(order, position)
+			}
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			return lessTagSet(table[order[i]], table[order[j]])
+		})
+
+		sortedTable := make(TagSetTable, len(order))
+		for newPosition, oldPosition := range order {
+			sortedTable[newPosition] = table[oldPosition]
+			replacements[tableNumber.TagSetID(TagSetSubID(oldPosition))] = tableNumber.TagSetID(TagSetSubID(newPosition))
+		}
+
+		index.tagSets[tableIdx] = sortedTable
+	}
+
+	return replacements
+}
+
+// canonicalizeCollections replaces TagSetID's in collections using specified replacements
+// then sorts collections in each VariantsTable by content.
+// Returns replacements map of old VariantID to new one or error if collection refers unknown tag set.
+func (index *IndexBuilder) canonicalizeCollections(
+	tagSetReplacements map[TagSetID]TagSetID,
+) (map[VariantID]VariantID, error) {
+	replacements := make(map[VariantID]VariantID)
+
+	for tableIdx, table := range index.collectionIdx {
+		for _, collection := range table {
+			for position, tagSetID := range collection {
+				replacement, ok := tagSetReplacements[tagSetID]
+				if !ok {
+					return nil, fmt.Errorf("collection %v refers unknown tag set %#08x", collection, tagSetID)
+				}
+
+				collection[position] = replacement
+			}
+
+			sort.Sort(collection)
+		}
+
+		order := make([]int, len(table))
+		for position := range order {
+			order[position] = position
+		}
+
+		sort.SliceStable(order, func(i, j int) bool {
+			return lessCollection(table[order[i]], table[order[j]])
+		})
+
+		tableNumber := CollectionTableNumber(tableIdx).Add(1)
+		sortedTable := make(VariantsTable, len(table))
+		for newPosition, oldPosition := range order {
+			sortedTable[newPosition] = table[oldPosition]
+			replacements[tableNumber.VariantID(VariantSubID(oldPosition))] = tableNumber.VariantID(VariantSubID(newPosition))
+		}
+
+		index.collectionIdx[tableIdx] = sortedTable
+	}
+
+	return replacements, nil
+}
+
+// canonicalizeItems renumbers items in depth-first order visiting children in letters order.
+// Items having no words in their subtree are dead branches left after removals, they are dropped.
+// Items variants are replaced using specified replacements.
+// Returns replacements list of new item IDs addressed by old ones, dropped items are replaced by zero ID.
+func (index *IndexBuilder) canonicalizeItems(variantReplacements map[VariantID]VariantID) []dag.ID {
+	oldItems := index.items.items[:index.items.NextID()]
+	order := make([]dag.ID, 0, len(oldItems))
+	stack := make([]dag.ID, 0)
+
+	pushChildren := func(parentID dag.ID) {
+		children := index.children.Children(parentID)
+		for idx := len(children) - 1; idx >= 0; idx-- {
+			stack = append(stack, children[idx].ID)
+		}
+	}
+
+	pushChildren(0)
+
+	for len(stack) > 0 {
+		oldID := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		order = append(order, oldID)
+		pushChildren(oldID)
+	}
+
+	// descendants follow their ancestors in depth-first order, so reversed order visits children first
+	live := make([]bool, len(oldItems))
+	for idx := len(order) - 1; idx >= 0; idx-- {
+		oldID := order[idx]
+		if oldItems[oldID].Variants != 0 || live[oldID] {
+			live[oldID] = true
+			live[oldItems[oldID].Parent] = true
+		}
+	}
+
+	newIDs := make([]dag.ID, len(oldItems))
+	newItems := make([]Item, 1, len(oldItems))
+
+	for _, oldID := range order {
+		if !live[oldID] {
+			continue
+		}
+
+		oldItem := oldItems[oldID]
+		newID := dag.ID(len(newItems))
+		newIDs[oldID] = newID
+		newItems = append(newItems, Item{
+			Parent:   newIDs[oldItem.Parent],
+			ID:       newID,
+			Letter:   oldItem.Letter,
+			Variants: variantReplacements[oldItem.Variants],
+		})
+	}
+
+	index.items.items = newItems
+	index.items.nextID = dag.ID(len(newItems))
+	index.children = BuildChildIndex(newItems)
+
+	return newIDs
+}
+
+// canonicalizeLemmas replaces lemma forms and normal forms items and tag sets using specified replacements
+// then sorts forms of each lemma. Forms referring dropped items or tag sets are dropped,
+// normal form referring them is forgotten.
+func (index *IndexBuilder) canonicalizeLemmas(tagSetReplacements map[TagSetID]TagSetID, itemReplacements []dag.ID) {
+	replace := func(form LemmaForm) (LemmaForm, bool) {
+		if int(form.Node) >= len(itemReplacements) || itemReplacements[form.Node] == 0 {
+			return LemmaForm{}, false
+		}
+
+		tagSetID, ok := tagSetReplacements[form.TagSet]
+		if !ok {
+			return LemmaForm{}, false
+		}
+
+		return LemmaForm{Node: itemReplacements[form.Node], TagSet: tagSetID}, true
+	}
+
+	for _, lemma := range index.lemmas {
+		forms := lemma.Forms[:0]
+
+		for _, form := range lemma.Forms {
+			if replaced, ok := replace(form); ok {
+				forms = append(forms, replaced)
+			}
+		}
+
+		sort.Slice(forms, func(i, j int) bool { return lessLemmaForm(forms[i], forms[j]) })
+		lemma.Forms = forms
+		lemma.Normal, _ = replace(lemma.Normal)
+	}
+
+	index.formRefs = nil
+}
+
+// lessTagSet reports whether TagSet a must sort before TagSet b.
+func lessTagSet(a TagSet, b TagSet) bool {
+	for idx := 0; idx < len(a) && idx < len(b); idx++ {
+		if a[idx] != b[idx] {
+			return a[idx] < b[idx]
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// lessCollection reports whether TagSetIDCollection a must sort before TagSetIDCollection b.
+func lessCollection(a TagSetIDCollection, b TagSetIDCollection) bool {
+	for idx := 0; idx < len(a) && idx < len(b); idx++ {
+		if a[idx] != b[idx] {
+			return a[idx] < b[idx]
+		}
+	}
+
+	return len(a) < len(b)
+}
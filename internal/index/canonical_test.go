@@ -0,0 +1,109 @@
+package index_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+type testForm struct {
+	word string
+	tags []dag.TagName
+}
+
+var canonicalTestForms = []testForm{
+	{"кот", []dag.TagName{"NOUN", "sing"}},
+	{"коты", []dag.TagName{"NOUN", "plur"}},
+	{"печь", []dag.TagName{"NOUN", "sing"}},
+	{"печь", []dag.TagName{"VERB"}},
+	{"пёк", []dag.TagName{"VERB", "sing"}},
+	{"кот", []dag.TagName{"NOUN", "plur"}},
+	{"лес", []dag.TagName{"NOUN", "sing"}},
+}
+
+func writeOptimized(t *testing.T, builder *index.IndexBuilder) []byte {
+	t.Helper()
+
+	_, err := builder.Optimize()
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, builder.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	return buffer.Bytes()
+}
+
+func mustBuild(t *testing.T, builder *index.IndexBuilder) *index.ReadOnlyIndex {
+	t.Helper()
+
+	built, err := builder.Build()
+	require.NoError(t, err)
+
+	return built
+}
+
+func newTagsBuilder() *index.IndexBuilder {
+	builder := index.NewBuilder()
+	builder.TagID("NOUN", "POST")
+	builder.TagID("VERB", "POST")
+	builder.TagID("sing", "NMbr")
+	builder.TagID("plur", "NMbr")
+
+	return builder
+}
+
+func TestIndexBuilder_OptimizeCanonical(t *testing.T) {
+	forward := newTagsBuilder()
+	for _, form := range canonicalTestForms {
+		require.NoError(t, forward.AddTagSet(form.word, form.tags...))
+	}
+
+	backward := newTagsBuilder()
+	for idx := len(canonicalTestForms) - 1; idx >= 0; idx-- {
+		require.NoError(t, backward.AddTagSet(canonicalTestForms[idx].word, canonicalTestForms[idx].tags...))
+	}
+
+	require.Equal(t, writeOptimized(t, forward), writeOptimized(t, backward))
+
+	node, err := forward.FetchString("кот")
+	require.NoError(t, err)
+	require.Len(t, node.TagSets(), 2)
+	require.Equal(t, 5, forward.WordsCount())
+}
+
+func TestIndexBuilder_Merge(t *testing.T) {
+	sequential := newTagsBuilder()
+	for _, form := range canonicalTestForms {
+		require.NoError(t, sequential.AddTagSet(form.word, form.tags...))
+	}
+
+	shards := []*index.IndexBuilder{newTagsBuilder(), newTagsBuilder()}
+	for _, form := range canonicalTestForms {
+		shard := shards[int([]rune(form.word)[0])%len(shards)]
+		require.NoError(t, shard.AddTagSet(form.word, form.tags...))
+	}
+
+	merged := index.NewBuilder() // tags are taken from merged shards
+	for idx := len(shards) - 1; idx >= 0; idx-- {
+		require.NoError(t, merged.Merge(shards[idx]))
+	}
+
+	require.Equal(t, sequential.WordsCount(), merged.WordsCount())
+	require.Equal(t, sequential.NodesCount(), merged.NodesCount())
+
+	node, err := merged.FetchString("печь")
+	require.NoError(t, err)
+	require.Len(t, node.TagSets(), 2)
+
+	merged = newTagsBuilder() // byte-identical requires the same tags order
+	for idx := len(shards) - 1; idx >= 0; idx-- {
+		require.NoError(t, merged.Merge(shards[idx]))
+	}
+
+	require.Equal(t, writeOptimized(t, sequential), writeOptimized(t, merged))
+}
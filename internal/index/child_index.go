@@ -0,0 +1,220 @@
+package index
+
+import (
+	"sort"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// ChildRef maps child item letter onto child item ID.
+type ChildRef struct {
+	Letter rune   // child item letter
+	ID     dag.ID // child item ID
+}
+
+// ChildIndex stores parent to children relations of index items.
+// While index is building it keeps a small letter-sorted list of children per parent item.
+// Compact packs lists into CSR-style layout: a single children array sorted by parent and letter
+// plus offsets array addressing each parent children range.
+// Both layouts are addressed by parent ID and use binary search to find child by letter.
+type ChildIndex struct {
+	lists   [][]ChildRef // per-parent letter-sorted children, nil if compacted
+	offsets []uint32     // compacted: children of parent P are refs[offsets[P]:offsets[P+1]]
+	refs    []ChildRef   // compacted children sorted by parent and letter
+}
+
+// NewChildIndex creates new empty ChildIndex ready to insert relations.
+func NewChildIndex() *ChildIndex {
+	return &ChildIndex{
+		lists:   make([][]ChildRef, 1),
+		offsets: nil,
+		refs:    nil,
+	}
+}
+
+// BuildChildIndex creates compacted ChildIndex from items list.
+// Items with zero ID are treated as unused slots and skipped.
+func BuildChildIndex(items []Item) *ChildIndex {
+	childIndex := &ChildIndex{
+		lists:   nil,
+		offsets: make([]uint32, len(items)+1),
+		refs:    nil,
+	}
+
+	for _, item := range items { // count children of each parent
+		if item.ID == 0 || int(item.Parent) >= len(items) {
+			continue
+		}
+		childIndex.offsets[item.Parent+1]++
+	}
+
+	for idx := 1; idx < len(childIndex.offsets); idx++ { // convert counts into offsets
+		childIndex.offsets[idx] += childIndex.offsets[idx-1]
+	}
+
+	childIndex.refs = make([]ChildRef, childIndex.offsets[len(items)])
+	fillPosition := make([]uint32, len(items))
+	copy(fillPosition, childIndex.offsets[:len(items)])
+
+	for _, item := range items {
+		if item.ID == 0 || int(item.Parent) >= len(items) {
+			continue
+		}
+		childIndex.refs[fillPosition[item.Parent]] = ChildRef{Letter: item.Letter, ID: item.ID}
+		fillPosition[item.Parent]++
+	}
+
+	for parent := 0; parent < len(items); parent++ {
+		children := childIndex.refs[childIndex.offsets[parent]:childIndex.offsets[parent+1]]
+		if len(children) > 1 {
+			sort.Slice(children, func(i, j int) bool { return children[i].Letter < children[j].Letter })
+		}
+	}
+
+	return childIndex
+}
+
+// IsCompact returns true if ChildIndex uses compacted layout.
+func (childIndex *ChildIndex) IsCompact() bool {
+	return childIndex.lists == nil
+}
+
+// Children returns letter-sorted children of specified parent.
+// Returned slice shares ChildIndex memory and must not be modified.
+func (childIndex *ChildIndex) Children(parent dag.ID) []ChildRef {
+	if childIndex.lists != nil {
+		if int(parent) >= len(childIndex.lists) {
+			return nil
+		}
+
+		return childIndex.lists[parent]
+	}
+
+	if int(parent)+1 >= len(childIndex.offsets) {
+		return nil
+	}
+
+	return childIndex.refs[childIndex.offsets[parent]:childIndex.offsets[parent+1]]
+}
+
+// Find returns ID of parent child having specified letter.
+// If no such child found returns zero ID and false found indicator.
+func (childIndex *ChildIndex) Find(parent dag.ID, letter rune) (id dag.ID, found bool) {
+	children := childIndex.Children(parent)
+	position := searchLetter(children, letter)
+
+	if position < len(children) && children[position].Letter == letter {
+		return children[position].ID, true
+	}
+
+	return 0, false
+}
+
+// Insert registers child ID having specified letter under parent.
+// If parent already has child with the same letter, its ID will be replaced.
+// Compacted ChildIndex is expanded into building layout first.
+func (childIndex *ChildIndex) Insert(parent dag.ID, letter rune, id dag.ID) {
+	if childIndex.lists == nil {
+		childIndex.expand()
+	}
+
+	if int(parent) >= len(childIndex.lists) {
+		childIndex.grow(int(parent) + 1)
+	}
+
+	children := childIndex.lists[parent]
+	position := searchLetter(children, letter)
+
+	if position < len(children) && children[position].Letter == letter {
+		children[position].ID = id
+		return
+	}
+
+	children = append(children, ChildRef{})
+	copy(children[position+1:], children[position:])
+	children[position] = ChildRef{Letter: letter, ID: id}
+	childIndex.lists[parent] = children
+}
+
+// Len returns count of registered relations.
+func (childIndex *ChildIndex) Len() (res int) {
+	if childIndex.lists == nil {
+		return len(childIndex.refs)
+	}
+
+	for _, children := range childIndex.lists {
+		res += len(children)
+	}
+
+	return res
+}
+
+// Compact packs building layout into compacted one. Does nothing if ChildIndex already compacted.
+func (childIndex *ChildIndex) Compact() {
+	if childIndex.lists == nil {
+		return
+	}
+
+	childIndex.offsets = make([]uint32, len(childIndex.lists)+1)
+	childIndex.refs = make([]ChildRef, 0, childIndex.Len())
+
+	for parent, children := range childIndex.lists {
+		childIndex.refs = append(childIndex.refs, children...)
+		childIndex.offsets[parent+1] = uint32(len(childIndex.refs))
+	}
+
+	childIndex.lists = nil
+}
+
+// expand unpacks compacted layout into per-parent lists to allow inserts.
+func (childIndex *ChildIndex) expand() {
+	parentsCount := len(childIndex.offsets) - 1
+	if parentsCount < 1 {
+		parentsCount = 1
+	}
+
+	childIndex.lists = make([][]ChildRef, parentsCount)
+
+	for parent := 0; parent+1 < len(childIndex.offsets); parent++ {
+		children := childIndex.refs[childIndex.offsets[parent]:childIndex.offsets[parent+1]]
+		if len(children) > 0 {
+			childIndex.lists[parent] = append(make([]ChildRef, 0, len(children)), children...)
+		}
+	}
+
+	childIndex.offsets = nil
+	childIndex.refs = nil
+}
+
+// grow extends per-parent lists to hold at least required parents count.
+func (childIndex *ChildIndex) grow(required int) {
+	if required <= cap(childIndex.lists) {
+		childIndex.lists = childIndex.lists[:required]
+		return
+	}
+
+	newCap := 2 * cap(childIndex.lists)
+	if newCap < required {
+		newCap = required
+	}
+
+	lists := make([][]ChildRef, required, newCap)
+	copy(lists, childIndex.lists)
+	childIndex.lists = lists
+}
+
+// searchLetter returns position of letter in letter-sorted children list
+// or position to insert letter if not found.
+func searchLetter(children []ChildRef, letter rune) int {
+	low, high := 0, len(children)
+	for low < high {
+		middle := int(uint(low+high) >> 1)
+		if children[middle].Letter < letter {
+			low = middle + 1
+		} else {
+			high = middle
+		}
+	}
+
+	return low
+}
@@ -0,0 +1,258 @@
+package index_test
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func TestChildIndex_InsertFind(t *testing.T) {
+	childIndex := index.NewChildIndex()
+	childIndex.Insert(0, 'b', 1)
+	childIndex.Insert(0, 'a', 2)
+	childIndex.Insert(2, 'c', 3)
+	childIndex.Insert(0, 'c', 4)
+
+	require.False(t, childIndex.IsCompact())
+	require.Equal(t, 4, childIndex.Len())
+	require.Equal(t,
+		[]index.ChildRef{{Letter: 'a', ID: 2}, {Letter: 'b', ID: 1}, {Letter: 'c', ID: 4}},
+		childIndex.Children(0))
+
+	for _, compact := range []bool{false, true} {
+		if compact {
+			childIndex.Compact()
+			require.True(t, childIndex.IsCompact())
+			require.Equal(t, 4, childIndex.Len())
+		}
+
+		id, found := childIndex.Find(0, 'a')
+		require.True(t, found)
+		require.EqualValues(t, 2, id)
+
+		id, found = childIndex.Find(2, 'c')
+		require.True(t, found)
+		require.EqualValues(t, 3, id)
+
+		_, found = childIndex.Find(2, 'a')
+		require.False(t, found)
+
+		_, found = childIndex.Find(100, 'a')
+		require.False(t, found)
+		require.Empty(t, childIndex.Children(100))
+	}
+
+	childIndex.Insert(3, 'd', 5) // insert into compacted index expands it
+	require.False(t, childIndex.IsCompact())
+	require.Equal(t, 5, childIndex.Len())
+	id, found := childIndex.Find(3, 'd')
+	require.True(t, found)
+	require.EqualValues(t, 5, id)
+	id, found = childIndex.Find(0, 'c')
+	require.True(t, found)
+	require.EqualValues(t, 4, id)
+}
+
+func TestBuildChildIndex(t *testing.T) {
+	words := randomWords(1000)
+	children := newMapChildren()
+
+	for _, word := range words {
+		children.add(word)
+	}
+
+	childIndex := index.BuildChildIndex(children.items)
+	require.True(t, childIndex.IsCompact())
+	require.Equal(t, len(children.items)-1, childIndex.Len())
+
+	for parent, letters := range children.children {
+		require.Len(t, childIndex.Children(parent), len(letters))
+
+		for letter, expectedID := range letters {
+			id, found := childIndex.Find(parent, letter)
+			require.True(t, found)
+			require.Equal(t, expectedID, id)
+		}
+	}
+}
+
+// mapChildren implements per-node children maps used by index before ChildIndex.
+// Used as a reference to compare ChildIndex with.
+type mapChildren struct {
+	items    []index.Item
+	children map[dag.ID]dag.IdMap
+}
+
+func newMapChildren() *mapChildren {
+	return &mapChildren{items: make([]index.Item, 1), children: map[dag.ID]dag.IdMap{0: {}}}
+}
+
+func (m *mapChildren) add(word string) {
+	parent := dag.ID(0)
+
+	for _, letter := range word {
+		childID, ok := m.children[parent][letter]
+		if !ok {
+			childID = dag.ID(len(m.items))
+			m.items = append(m.items, index.Item{Parent: parent, ID: childID, Letter: letter})
+			m.children[parent][letter] = childID
+			m.children[childID] = make(dag.IdMap)
+		}
+		parent = childID
+	}
+}
+
+func (m *mapChildren) find(parent dag.ID, letter rune) (dag.ID, bool) {
+	id, ok := m.children[parent][letter]
+
+	return id, ok
+}
+
+func randomWords(count int) []string {
+	letters := []rune("абвгдеёжзийклмнопрстуфхцчшщъыьэюя")
+	random := rand.New(rand.NewSource(int64(count))) //nolint:gosec
+	words := make([]string, count)
+
+	for idx := range words {
+		word := make([]rune, 3+random.Intn(10))
+		for letterIdx := range word {
+			word[letterIdx] = letters[random.Intn(len(letters))]
+		}
+		words[idx] = string(word)
+	}
+
+	return words
+}
+
+// heapInUse returns heap bytes used after garbage collection.
+func heapInUse() uint64 {
+	var stats runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&stats)
+
+	return stats.HeapInuse
+}
+
+const benchmarkWordsCount = 100000
+
+func BenchmarkMapChildren_Memory(b *testing.B) {
+	words := randomWords(benchmarkWordsCount)
+
+	for i := 0; i < b.N; i++ {
+		before := heapInUse()
+		children := newMapChildren()
+		for _, word := range words {
+			children.add(word)
+		}
+		items := children.items
+		children.items = nil
+		b.ReportMetric(float64(heapInUse()-before)/float64(len(items)), "B/node")
+		runtime.KeepAlive(children)
+	}
+}
+
+func BenchmarkChildIndex_Memory(b *testing.B) {
+	words := randomWords(benchmarkWordsCount)
+	children := newMapChildren()
+
+	for _, word := range words {
+		children.add(word)
+	}
+
+	items := children.items
+	children = nil
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		before := heapInUse()
+		childIndex := index.BuildChildIndex(items)
+		b.ReportMetric(float64(heapInUse()-before)/float64(len(items)), "B/node")
+		runtime.KeepAlive(childIndex)
+	}
+}
+
+func BenchmarkMapChildren_Find(b *testing.B) {
+	words := randomWords(benchmarkWordsCount)
+	children := newMapChildren()
+
+	for _, word := range words {
+		children.add(word)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		parent := dag.ID(0)
+		for _, letter := range words[i%len(words)] {
+			parent, _ = children.find(parent, letter)
+		}
+	}
+}
+
+func BenchmarkChildIndex_Find(b *testing.B) {
+	words := randomWords(benchmarkWordsCount)
+	children := newMapChildren()
+
+	for _, word := range words {
+		children.add(word)
+	}
+
+	childIndex := index.BuildChildIndex(children.items)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		parent := dag.ID(0)
+		for _, letter := range words[i%len(words)] {
+			parent, _ = childIndex.Find(parent, letter)
+		}
+	}
+}
+
+func BenchmarkChildIndex_Insert(b *testing.B) {
+	words := randomWords(benchmarkWordsCount)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		childIndex := index.NewChildIndex()
+		nextID := dag.ID(1)
+
+		for _, word := range words {
+			parent := dag.ID(0)
+			for _, letter := range word {
+				childID, found := childIndex.Find(parent, letter)
+				if !found {
+					childID = nextID
+					nextID++
+					childIndex.Insert(parent, letter, childID)
+				}
+				parent = childID
+			}
+		}
+	}
+}
+
+func BenchmarkMapChildren_Insert(b *testing.B) {
+	words := randomWords(benchmarkWordsCount)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		children := newMapChildren()
+		for _, word := range words {
+			children.add(word)
+		}
+	}
+}
@@ -9,3 +9,6 @@ var ErrNilWriter = fmt.Errorf("%w: nil writer", Error)
 
 // ErrNilReader indicates error when nil writer specified to BinaryReadFrom.
 var ErrNilReader = fmt.Errorf("%w: nil reader", Error)
+
+// ErrNotFound indicates word, tag set or lemma to remove is not known to index.
+var ErrNotFound = fmt.Errorf("%w: not found", Error)
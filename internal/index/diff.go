@@ -0,0 +1,135 @@
+package index
+
+import "github.com/amarin/gomorphy/pkg/dag"
+
+// WordDiff provides word tag sets differing between two indexes.
+type WordDiff struct {
+	Word    string       // word text
+	InOld   bool         // word has tag sets in old index
+	InNew   bool         // word has tag sets in new index
+	Removed []dag.TagSet // tag sets of old index missed in new one
+	Added   []dag.TagSet // tag sets of new index missed in old one
+}
+
+// DiffTagSets compares word tag sets of two indexes by tag names.
+// Returns tag sets of old list missed in new one and tag sets of new list missed in old one.
+func DiffTagSets(oldTagSets []dag.TagSet, newTagSets []dag.TagSet) (removed []dag.TagSet, added []dag.TagSet) {
+	oldKeys := make(map[string]bool, len(oldTagSets))
+	for _, tagSet := range oldTagSets {
+		oldKeys[tagSet.Key()] = true
+	}
+
+	newKeys := make(map[string]bool, len(newTagSets))
+	for _, tagSet := range newTagSets {
+		newKeys[tagSet.Key()] = true
+	}
+
+	for _, tagSet := range oldTagSets {
+		if !newKeys[tagSet.Key()] {
+			removed = append(removed, tagSet)
+		}
+	}
+
+	for _, tagSet := range newTagSets {
+		if !oldKeys[tagSet.Key()] {
+			added = append(added, tagSet)
+		}
+	}
+
+	return removed, added
+}
+
+// DiffWords walks old and new indexes together and calls fn for every word having different tag sets.
+// Words are visited in depth-first order taking children in letters order, so only current word
+// is kept in memory. Walking stops at first fn error which is returned as is.
+func DiffWords(oldIndex *ReadOnlyIndex, newIndex *ReadOnlyIndex, fn func(diff WordDiff) error) error {
+	type frame struct {
+		oldID dag.ID // old index item, 0 if word missed in old index
+		newID dag.ID // new index item, 0 if word missed in new index
+		depth int    // item letter position in word
+	}
+
+	word := make([]rune, 0)
+	stack := make([]frame, 0)
+
+	pushChildren := func(current frame) {
+		var oldChildren, newChildren []ChildRef
+
+		if current.oldID != 0 || current.depth == 0 {
+			oldChildren = oldIndex.children.Children(current.oldID)
+		}
+
+		if current.newID != 0 || current.depth == 0 {
+			newChildren = newIndex.children.Children(current.newID)
+		}
+
+		// merge letter-sorted children lists, then push in reverse order to pop in letters order
+		merged := make([]frame, 0, len(oldChildren)+len(newChildren))
+		for oldIdx, newIdx := 0, 0; oldIdx < len(oldChildren) || newIdx < len(newChildren); {
+			next := frame{oldID: 0, newID: 0, depth: current.depth + 1}
+
+			switch {
+			case newIdx == len(newChildren) || (oldIdx < len(oldChildren) && oldChildren[oldIdx].Letter < newChildren[newIdx].Letter):
+				next.oldID = oldChildren[oldIdx].ID
+				oldIdx++
+			case oldIdx == len(oldChildren) || newChildren[newIdx].Letter < oldChildren[oldIdx].Letter:
+				next.newID = newChildren[newIdx].ID
+				newIdx++
+			default:
+				next.oldID, next.newID = oldChildren[oldIdx].ID, newChildren[newIdx].ID
+				oldIdx++
+				newIdx++
+			}
+
+			merged = append(merged, next)
+		}
+
+		for idx := len(merged) - 1; idx >= 0; idx-- {
+			stack = append(stack, merged[idx])
+		}
+	}
+
+	pushChildren(frame{oldID: 0, newID: 0, depth: 0})
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		var oldTagSets, newTagSets []dag.TagSet
+
+		letter := rune(0)
+		if current.oldID != 0 {
+			oldTagSets = oldIndex.GetItem(current.oldID).TagSets()
+			if item := oldIndex.getItem(current.oldID); item != nil {
+				letter = item.Letter
+			}
+		}
+
+		if current.newID != 0 {
+			newTagSets = newIndex.GetItem(current.newID).TagSets()
+			if item := newIndex.getItem(current.newID); item != nil {
+				letter = item.Letter
+			}
+		}
+
+		word = append(word[:current.depth-1], letter)
+
+		if removed, added := DiffTagSets(oldTagSets, newTagSets); len(removed) > 0 || len(added) > 0 {
+			wordDiff := WordDiff{
+				Word:    string(word),
+				InOld:   len(oldTagSets) > 0,
+				InNew:   len(newTagSets) > 0,
+				Removed: removed,
+				Added:   added,
+			}
+
+			if err := fn(wordDiff); err != nil {
+				return err
+			}
+		}
+
+		pushChildren(current)
+	}
+
+	return nil
+}
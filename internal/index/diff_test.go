@@ -0,0 +1,60 @@
+package index_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func TestDiffTagSets(t *testing.T) {
+	nounSing := dag.TagSet{{Parent: "POST", Name: "NOUN"}, {Parent: "NMbr", Name: "sing"}}
+	singNoun := dag.TagSet{{Parent: "NMbr", Name: "sing"}, {Parent: "POST", Name: "NOUN"}}
+	verb := dag.TagSet{{Parent: "POST", Name: "VERB"}}
+
+	removed, added := index.DiffTagSets([]dag.TagSet{nounSing, verb}, []dag.TagSet{singNoun})
+	require.Equal(t, []dag.TagSet{verb}, removed)
+	require.Empty(t, added) // tags order does not matter
+
+	removed, added = index.DiffTagSets(nil, []dag.TagSet{verb})
+	require.Empty(t, removed)
+	require.Equal(t, []dag.TagSet{verb}, added)
+}
+
+func TestDiffWords(t *testing.T) {
+	oldIndex := mustBuild(t, newLemmasBuilder(t, lemmasRevision1))
+	newIndex := mustBuild(t, newLemmasBuilder(t, lemmasRevision2))
+	walked := make([]string, 0)
+	diffs := make(map[string]index.WordDiff)
+
+	require.NoError(t, index.DiffWords(oldIndex, newIndex, func(diff index.WordDiff) error {
+		walked = append(walked, diff.Word)
+		diffs[diff.Word] = diff
+
+		return nil
+	}))
+
+	// unchanged words are skipped, others are visited in letters order
+	require.Equal(t, []string{"лес", "печи", "печь", "пёк"}, walked)
+	require.False(t, diffs["лес"].InOld)
+	require.True(t, diffs["лес"].InNew)
+	require.True(t, diffs["печь"].InOld)
+	require.True(t, diffs["печь"].InNew)
+	require.Empty(t, diffs["печь"].Added)
+	require.Len(t, diffs["печь"].Removed, 1)
+	require.Equal(t, "VERB", diffs["печь"].Removed[0].String())
+	require.True(t, diffs["пёк"].InOld)
+	require.False(t, diffs["пёк"].InNew)
+
+	require.NoError(t, index.DiffWords(oldIndex, oldIndex, func(diff index.WordDiff) error {
+		return errors.New("unexpected diff: " + diff.Word)
+	}))
+
+	stop := errors.New("stop")
+	require.ErrorIs(t, index.DiffWords(oldIndex, newIndex, func(diff index.WordDiff) error {
+		return stop
+	}), stop)
+}
@@ -11,242 +11,120 @@ import (
 	"github.com/amarin/gomorphy/pkg/dag"
 )
 
-const (
-	binaryTagsPrefix     = "TD"
-	binaryColIdxPrefix   = "CD"
-	binaryItemsIdxPrefix = "ID"
-)
-
-// Index implements main dictionary index.
-type Index struct {
-	mu            *sync.Mutex          // protect internals below
-	tags          dag.Idx              // Tag's storage
-	tagSets       TagSetIndex          // TagSet's storage
-	collectionIdx VariantsIndex        // TagSetIDCollection storage
-	items         Items                // Items storage
-	childrenMap   map[dag.ID]dag.IdMap // children maps
+// IndexBuilder implements mutable dictionary index used to fill dictionary data.
+// Use Build or Freeze to get ReadOnlyIndex suitable for lookups.
+type IndexBuilder struct {
+	mu            *sync.Mutex       // protect internals below
+	tags          dag.Idx           // Tag's storage
+	tagSets       TagSetIndex       // TagSet's storage
+	collectionIdx VariantsIndex     // TagSetIDCollection storage
+	items         Items             // Items storage
+	children      *ChildIndex       // parent to children relations
+	lemmas        LemmaIndex        // dictionary lemmas forms and revisions
+	formRefs      map[LemmaForm]int // count of lemmas referring form, built on demand
+	logger        logging.Logger    // filling and optimization progress logger, nothing logged if nil
 	wordsCount    int
 }
 
 // Tags returns internal tags index.
-func (index Index) Tags() dag.Idx {
+func (index IndexBuilder) Tags() dag.Idx {
 	return index.tags
 }
 
-// New creates new empty list.
-func New() *Index {
-	return &Index{
+// NewBuilder creates new empty IndexBuilder.
+func NewBuilder() *IndexBuilder {
+	return &IndexBuilder{
 		mu:            new(sync.Mutex),
 		items:         *NewItems(),
 		tags:          dag.NewIndex(),
 		tagSets:       make(TagSetIndex, 0),
 		collectionIdx: make(VariantsIndex, 0),
-		childrenMap:   make(map[dag.ID]dag.IdMap),
+		children:      NewChildIndex(),
+		lemmas:        make(LemmaIndex),
+		formRefs:      nil,
+		logger:        nil,
 		wordsCount:    0,
 	}
 }
 
-func (index *Index) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
-	index.mu.Lock()
-	defer index.mu.Unlock()
-
-	if err = index.writeTagsDefinitions(writer); err != nil {
-		return err
-	}
-	if err = index.writeTagSetsDefinitions(writer); err != nil {
-		return err
-	}
-	if err = index.writeCollectionsDefinitions(writer); err != nil {
-		return err
-	}
-	if err = index.writeItemsDefinitions(writer); err != nil {
-		return err
-	}
-
-	return nil
-}
-
-// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
-// A companion of readTagsDefinitions.
-// Used from BinaryWriteTo.
-func (index *Index) writeTagsDefinitions(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteStringZ(binaryTagsPrefix); err != nil {
-		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
-	}
-	if err = index.tags.BinaryWriteTo(writer); err != nil {
-		return fmt.Errorf("%w: write: tags index: %v", Error, err)
-	}
-
-	return nil
-}
-
-// readTagsDefinitions reads tags index from specified binutils.BinaryReader.
-// A companion of writeTagsDefinitions.
-// Used from BinaryReadFrom.
-func (index *Index) readTagsDefinitions(reader *binutils.BinaryReader) (err error) {
-	var section string
-
-	if section, err = reader.ReadStringZ(); err != nil {
-		return fmt.Errorf("%w: read: tags prefix: %v", Error, err)
-	}
-	if section != binaryTagsPrefix {
-		return fmt.Errorf("%w: read: expected section %v ", Error, binaryTagsPrefix)
-	}
-
-	if err = index.tags.BinaryReadFrom(reader); err != nil {
-		return fmt.Errorf("%w: read: tags index: %v", Error, err)
-	}
-
-	return nil
+// SetLogger sets logger reporting index filling and optimization progress. Nothing is logged if logger is nil.
+func (index *IndexBuilder) SetLogger(logger logging.Logger) {
+	index.logger = logger
 }
 
-// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
-// A companion of readTagsDefinitions.
-// Used from BinaryWriteTo.
-func (index *Index) writeTagSetsDefinitions(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteStringZ(binaryTagSetPrefix); err != nil {
-		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
-	}
-	if err = index.tagSets.BinaryWriteTo(writer); err != nil {
-		return fmt.Errorf("%w: write: tags sets index: %v", Error, err)
-	}
-
-	return nil
+// Logger returns logger set by SetLogger or nil if none set.
+func (index *IndexBuilder) Logger() logging.Logger {
+	return index.logger
 }
 
-// readTagsDefinitions reads tags index from specified binutils.BinaryReader.
-// A companion of writeTagsDefinitions.
-// Used from BinaryReadFrom.
-func (index *Index) readTagSetsDefinitions(reader *binutils.BinaryReader) (err error) {
-	var section string
-
-	if section, err = reader.ReadStringZ(); err != nil {
-		return fmt.Errorf("%w: read: tags prefix: %v", Error, err)
-	}
-	if section != binaryTagSetPrefix {
-		return fmt.Errorf("%w: read: expected section %v ", Error, binaryTagSetPrefix)
-	}
-
-	if err = index.tagSets.BinaryReadFrom(reader); err != nil {
-		return fmt.Errorf("%w: write: tags sets index: %v", Error, err)
+// infof logs optimization progress at info level if logger set.
+func (index *IndexBuilder) infof(format string, args ...interface{}) {
+	if index.logger != nil {
+		index.logger.Infof(format, args...)
 	}
-
-	return nil
 }
 
-// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
-// A companion of readTagsDefinitions.
-// Used from BinaryWriteTo.
-func (index *Index) writeCollectionsDefinitions(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteStringZ(binaryColIdxPrefix); err != nil {
-		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
-	}
-	if err = index.collectionIdx.BinaryWriteTo(writer); err != nil {
-		return fmt.Errorf("%w: write: tags set collections index: %v", Error, err)
-	}
-
-	return nil
+// debugEnabled returns true if logger set and debug logging enabled.
+func (index *IndexBuilder) debugEnabled() bool {
+	return index.logger != nil && index.logger.IsEnabledForLevel(logging.LevelDebug)
 }
 
-// readTagsDefinitions reads tags index from specified binutils.BinaryReader.
-// A companion of writeTagsDefinitions.
-// Used from BinaryReadFrom.
-func (index *Index) readCollectionsDefinitions(reader *binutils.BinaryReader) (err error) {
-	var section string
-
-	if section, err = reader.ReadStringZ(); err != nil {
-		return fmt.Errorf("%w: read: tags prefix: %v", Error, err)
+// debugf logs optimization progress at debug level if logger set.
+func (index *IndexBuilder) debugf(format string, args ...interface{}) {
+	if index.debugEnabled() {
+		index.logger.Debugf(format, args...)
 	}
-	if section != binaryColIdxPrefix {
-		return fmt.Errorf("%w: read: expected section %v ", Error, binaryColIdxPrefix)
-	}
-
-	if err = index.collectionIdx.BinaryReadFrom(reader); err != nil {
-		return fmt.Errorf("%w: write: tags set collections index: %v", Error, err)
-	}
-
-	return nil
 }
 
-// writeTagsDefinitions writes tags index into specified binutils.BinaryWriter.
-// A companion of readTagsDefinitions.
-// Used from BinaryWriteTo.
-func (index *Index) writeItemsDefinitions(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteStringZ(binaryItemsIdxPrefix); err != nil {
-		return fmt.Errorf("%w: write: tags prefix: %v", Error, err)
-	}
-	if err = index.items.BinaryWriteTo(writer); err != nil {
-		return fmt.Errorf("%w: write: tags set collections index: %v", Error, err)
-	}
+// BinaryWriteTo writes index data using specified binutils.BinaryWriter.
+// Written data could be loaded later as ReadOnlyIndex. Implements binutils.BinaryWriterTo.
+func (index *IndexBuilder) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
+	index.mu.Lock()
+	defer index.mu.Unlock()
 
-	return nil
+	return index.view().BinaryWriteTo(writer)
 }
 
-// readTagsDefinitions reads tags index from specified binutils.BinaryReader.
-// A companion of writeTagsDefinitions.
-// Used from BinaryReadFrom.
-func (index *Index) readItemsDefinitions(reader *binutils.BinaryReader) (err error) {
-	var section string
-
-	if section, err = reader.ReadStringZ(); err != nil {
-		return fmt.Errorf("%w: read: tags prefix: %v", Error, err)
-	}
-	if section != binaryItemsIdxPrefix {
-		return fmt.Errorf("%w: read: expected section %v ", Error, binaryItemsIdxPrefix)
-	}
-
-	if err = index.items.BinaryReadFrom(reader); err != nil {
-		return fmt.Errorf("%w: read: node index: %v", Error, err)
+// view makes ReadOnlyIndex sharing builder data without children relations.
+// Used to write builder data, view must not be used after builder changed.
+func (index *IndexBuilder) view() *ReadOnlyIndex {
+	return &ReadOnlyIndex{
+		tags:          index.tags,
+		tagSets:       index.tagSets,
+		collectionIdx: index.collectionIdx,
+		items:         index.items.items[:index.items.NextID()],
+		children:      nil,
+		lemmas:        index.lemmas,
+		wordsCount:    index.wordsCount,
 	}
-
-	return nil
 }
 
-func (index *Index) rebuildChildrenIndex() {
-	index.GetChildrenIDMap(0)
-	for idx, item := range index.items.items {
-		nodeID := dag.ID(idx)
-		index.GetChildrenIDMap(item.Parent)
-		index.GetChildrenIDMap(nodeID)
-		index.childrenMap[item.Parent][item.Letter] = nodeID
-		if item.Variants != 0 {
-			index.wordsCount++
-		}
+// Build optimizes index and returns ReadOnlyIndex taking builder data without copying.
+// Builder is reset to empty state after Build.
+// Returns error if index optimization failed, builder is not reset then.
+func (index *IndexBuilder) Build() (*ReadOnlyIndex, error) {
+	if _, err := index.Optimize(); err != nil {
+		return nil, err
 	}
-}
 
-// BinaryReadFrom reads index data from specified binutils.BinaryReader.
-// Implements binutils.BinaryReaderFrom.
-func (index *Index) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
 	index.mu.Lock()
 	defer index.mu.Unlock()
 
-	if err = index.readTagsDefinitions(reader); err != nil {
-		return fmt.Errorf("%w: read: tags: %v", Error, err)
-	}
-	if err = index.readTagSetsDefinitions(reader); err != nil {
-		return fmt.Errorf("%w: read: tags: %v", Error, err)
-	}
-	if err = index.readCollectionsDefinitions(reader); err != nil {
-		return fmt.Errorf("%w: read: collections index: %v", Error, err)
-	}
-	if err = index.readItemsDefinitions(reader); err != nil {
-		return err
-	}
-
-	index.rebuildChildrenIndex()
+	readOnlyIndex := index.view()
+	readOnlyIndex.children = index.children
+	*index = *NewBuilder()
 
-	return nil
+	return readOnlyIndex, nil
 }
 
 // AddRunes adds runes sequence into container.
 // Returns final node filled with node data or error if add caused error.
-func (index *Index) AddRunes(runes []rune) (dag.Node, error) {
+func (index *IndexBuilder) AddRunes(runes []rune) (dag.Node, error) {
 	return index.AddToNode(0, runes)
 }
 
 // AddString adds string word into index. Returns final node or error if add caused error.
-func (index *Index) AddString(word string) (node dag.Node, err error) {
+func (index *IndexBuilder) AddString(word string) (node dag.Node, err error) {
 	if len(word) == 0 {
 		return nil, fmt.Errorf("%w: empty word", Error)
 	}
@@ -254,30 +132,42 @@ func (index *Index) AddString(word string) (node dag.Node, err error) {
 	return index.AddRunes([]rune(word))
 }
 
+// AddTagSet adds word into index and attaches specified tag set to word node.
+// All tags must be registered using TagID before.
+func (index *IndexBuilder) AddTagSet(word string, tagSet ...dag.TagName) (err error) {
+	var node dag.Node
+
+	if node, err = index.AddString(word); err != nil {
+		return err
+	}
+
+	return node.AddTagSet(tagSet...)
+}
+
 // FetchRunes lookups runes sequence in container.
 // If found returns final node or error if not found.
-func (index *Index) FetchRunes(runes []rune) (dag.Node, error) {
+func (index *IndexBuilder) FetchRunes(runes []rune) (dag.Node, error) {
 	return index.FetchFromItem(0, runes)
 }
 
 // FetchString lookups string in container.
 // If found returns final node or error if not found.
-func (index *Index) FetchString(word string) (dag.Node, error) {
+func (index *IndexBuilder) FetchString(word string) (dag.Node, error) {
 	return index.FetchRunes([]rune(word))
 }
 
 // Children returns rootNode runes mapped to its nodes. Implements dag.Index.
-func (index *Index) Children() dag.NodeMap {
+func (index *IndexBuilder) Children() dag.NodeMap {
 	return index.GetChildrenMap(0)
 }
 
 // getItem returns node by its index or error if no such node found. Implements dag.Index.
-func (index *Index) getItem(nodeIdx dag.ID) *Item {
+func (index *IndexBuilder) getItem(nodeIdx dag.ID) *Item {
 	return index.items.Get(nodeIdx)
 }
 
 // Get returns node by its index or error if no such node found. Implements dag.Index.
-func (index *Index) Get(nodeIdx dag.ID) (node dag.Node, err error) {
+func (index *IndexBuilder) Get(nodeIdx dag.ID) (node dag.Node, err error) {
 	if nodeIdx >= index.items.NextID() {
 		return nil, fmt.Errorf("%w: no such node: %d", Error, nodeIdx)
 	}
@@ -285,7 +175,7 @@ func (index *Index) Get(nodeIdx dag.ID) (node dag.Node, err error) {
 	return index.GetItem(nodeIdx), nil
 }
 
-func (index *Index) rootNode(letter rune) (root dag.Node) {
+func (index *IndexBuilder) rootNode(letter rune) (root dag.Node) {
 	var (
 		ok     bool
 		rootID dag.ID
@@ -295,7 +185,7 @@ func (index *Index) rootNode(letter rune) (root dag.Node) {
 
 	defer index.mu.Unlock()
 
-	if rootID, ok = index.childrenMap[0][letter]; !ok {
+	if rootID, ok = index.children.Find(0, letter); !ok {
 		return nil
 	}
 
@@ -310,17 +200,19 @@ func (index *Index) rootNode(letter rune) (root dag.Node) {
 // 	return index.GetItem(newItem.ID), nil
 // }
 
-// GetChildrenIDMap generates children nodes for Node specified by its ID.
-func (index *Index) GetChildrenIDMap(id dag.ID) (res dag.IdMap) {
-	var ok bool
+// GetChildrenIDMap generates children IDs map for Node specified by its ID.
+func (index *IndexBuilder) GetChildrenIDMap(id dag.ID) (res dag.IdMap) {
+	children := index.children.Children(id)
+	res = make(dag.IdMap, len(children))
 
-	if res, ok = index.childrenMap[id]; !ok {
-		index.childrenMap[id] = make(dag.IdMap)
+	for _, child := range children {
+		res[child.Letter] = child.ID
 	}
-	return index.childrenMap[id]
+
+	return res
 }
 
-func (index *Index) FetchItemFromParent(parentID dag.ID, runes []rune) (*Node, error) {
+func (index *IndexBuilder) FetchItemFromParent(parentID dag.ID, runes []rune) (*Node, error) {
 	var (
 		nextItemID      dag.ID
 		ok              bool
@@ -335,8 +227,7 @@ func (index *Index) FetchItemFromParent(parentID dag.ID, runes []rune) (*Node, e
 
 	for {
 		firstRune := runes[currentIndex]
-		childrenIDMap := index.GetChildrenIDMap(currentParentID)
-		if nextItemID, ok = childrenIDMap[firstRune]; !ok {
+		if nextItemID, ok = index.children.Find(currentParentID, firstRune); !ok {
 			node = index.GetItem(currentParentID)
 			if node == nil {
 				return nil, fmt.Errorf("%w: fetch: no node: `%s[%s]`", Error, string(runes[:currentIndex]), string(firstRune))
@@ -354,7 +245,7 @@ func (index *Index) FetchItemFromParent(parentID dag.ID, runes []rune) (*Node, e
 	}
 }
 
-func (index *Index) FetchFromItem(parentID dag.ID, runes []rune) (dag.Node, error) {
+func (index *IndexBuilder) FetchFromItem(parentID dag.ID, runes []rune) (dag.Node, error) {
 	var (
 		nextItemID      dag.ID
 		ok              bool
@@ -368,8 +259,7 @@ func (index *Index) FetchFromItem(parentID dag.ID, runes []rune) (dag.Node, erro
 
 	for {
 		firstRune := runes[currentIndex]
-		childrenIDMap := index.GetChildrenIDMap(currentParentID)
-		if nextItemID, ok = childrenIDMap[firstRune]; !ok {
+		if nextItemID, ok = index.children.Find(currentParentID, firstRune); !ok {
 			node := index.GetItem(currentParentID)
 			if node == nil {
 				return nil, fmt.Errorf("%w: fetch: no node: `%s[%s]`", Error, string(runes[:currentIndex]), string(firstRune))
@@ -387,10 +277,9 @@ func (index *Index) FetchFromItem(parentID dag.ID, runes []rune) (dag.Node, erro
 	}
 }
 
-func (index *Index) AddToNode(parentID dag.ID, runes []rune) (dag.Node, error) {
+func (index *IndexBuilder) AddToNode(parentID dag.ID, runes []rune) (dag.Node, error) {
 	var (
 		nextItemID      dag.ID
-		ok              bool
 		currentParentID = parentID
 		currentIndex    = 0
 	)
@@ -406,13 +295,7 @@ func (index *Index) AddToNode(parentID dag.ID, runes []rune) (dag.Node, error) {
 			}
 		}
 
-		rootLetter := runes[currentIndex]
-		childrenIDMap := index.GetChildrenIDMap(currentParentID)
-		if nextItemID, ok = childrenIDMap[rootLetter]; !ok {
-			newRootItem := index.items.NewChild(currentParentID, rootLetter)
-			nextItemID = newRootItem.ID
-			index.childrenMap[currentParentID][rootLetter] = newRootItem.ID
-		}
+		nextItemID = index.childID(currentParentID, runes[currentIndex])
 
 		if currentIndex == len(runes)-1 {
 			return index.Get(nextItemID)
@@ -423,50 +306,103 @@ func (index *Index) AddToNode(parentID dag.ID, runes []rune) (dag.Node, error) {
 	}
 }
 
+// childID returns ID of parent child having specified letter. Creates child if not exists.
+func (index *IndexBuilder) childID(parentID dag.ID, letter rune) dag.ID {
+	childID, found := index.children.Find(parentID, letter)
+	if !found {
+		childID = index.items.NewChild(parentID, letter).ID
+		index.children.Insert(parentID, letter, childID)
+	}
+
+	return childID
+}
+
+// addTagSetID adds TagSetID into item variants collection.
+func (index *IndexBuilder) addTagSetID(item *Item, tagSetID TagSetID) {
+	collection := index.collectionIdx.Get(item.Variants).Add(tagSetID)
+	if item.Variants == 0 {
+		index.wordsCount++
+	}
+
+	item.Variants = index.collectionIdx.Index(collection)
+}
+
+// removeTagSetID removes TagSetID from item variants collection.
+// Item having no more tag sets is not a word anymore.
+func (index *IndexBuilder) removeTagSetID(item *Item, tagSetID TagSetID) {
+	if item.Variants == 0 {
+		return
+	}
+
+	collection := index.collectionIdx.Get(item.Variants).Remove(tagSetID)
+	if len(collection) == 0 {
+		item.Variants = 0
+		index.wordsCount--
+
+		return
+	}
+
+	item.Variants = index.collectionIdx.Index(collection)
+}
+
+// tagSetID gets or creates TagSet having specified tag names and returns its ID.
+// All tags must be registered using TagID before.
+func (index *IndexBuilder) tagSetID(tagNames []dag.TagName) (TagSetID, error) {
+	var found bool
+
+	tagSet := make(TagSet, len(tagNames))
+	for idx, tagName := range tagNames {
+		if tagSet[idx], found = index.tags.Find(tagName); !found {
+			return 0, fmt.Errorf("unknown tag: %v", tagName)
+		}
+	}
+
+	return index.tagSets.Index(tagSet), nil
+}
+
 // WordsCount returns count of indexed words.
-func (index *Index) WordsCount() int {
+func (index *IndexBuilder) WordsCount() int {
 	return index.wordsCount
 }
 
 // NodesCount returns count of indexed nodes.
-func (index *Index) NodesCount() int {
+func (index *IndexBuilder) NodesCount() int {
 	return int(index.items.NextID() - 1)
 }
 
 // GetItem generates Node instance runtime.
-func (index *Index) GetItem(id dag.ID) *Node {
+func (index *IndexBuilder) GetItem(id dag.ID) *Node {
 	return newNode(index, id)
 }
 
 // GetChildrenMap generates children nodes for Node specified by its ID.
-func (index *Index) GetChildrenMap(id dag.ID) dag.NodeMap {
-	childrenItems := index.childrenMap[id]
+func (index *IndexBuilder) GetChildrenMap(id dag.ID) dag.NodeMap {
+	children := index.children.Children(id)
 
-	res := make(dag.NodeMap)
-	for letter, chidlID := range childrenItems {
-		res[letter] = index.GetItem(chidlID)
+	res := make(dag.NodeMap, len(children))
+	for _, child := range children {
+		res[child.Letter] = index.GetItem(child.ID)
 	}
 
 	return res
 }
 
-// getChildren generates children nodes for Node specified by its ID.
-func (index *Index) getChild(id dag.ID, letter rune) *Node {
-	childrenItems := index.childrenMap[id]
-
-	if len(childrenItems) == 0 {
+// getChild returns child Node of Node specified by its ID having required letter or nil if no such child.
+func (index *IndexBuilder) getChild(id dag.ID, letter rune) *Node {
+	childID, found := index.children.Find(id, letter)
+	if !found {
 		return nil
 	}
 
-	return index.GetItem(childrenItems[letter])
+	return index.GetItem(childID)
 }
 
 // TagID gets or creates tag in internal tag index and returns its ID.
-func (index *Index) TagID(name dag.TagName, parent dag.TagName) dag.TagID {
+func (index *IndexBuilder) TagID(name dag.TagName, parent dag.TagName) dag.TagID {
 	return index.tags.Index(name, parent)
 }
 
-func (index *Index) TagSet(tagSet TagSet) (res dag.TagSet, err error) {
+func (index *IndexBuilder) TagSet(tagSet TagSet) (res dag.TagSet, err error) {
 	res = make(dag.TagSet, len(tagSet))
 	for idx, tagID := range tagSet {
 		tag, found := index.tags.Get(tagID)
@@ -480,17 +416,64 @@ func (index *Index) TagSet(tagSet TagSet) (res dag.TagSet, err error) {
 }
 
 // TagSetIndex returns internal TagSetIndex.
-func (index *Index) TagSetIndex() TagSetIndex {
+func (index *IndexBuilder) TagSetIndex() TagSetIndex {
 	return index.tagSets
 }
 
-// Optimize reduces index deleting unused tag set's and collections;
-func (index *Index) Optimize() {
-	logger := logging.NewNamedLogger("optimize").WithLevel(logging.LevelDebug)
+// OptimizeReport summarizes index data reclaimed by Optimize.
+type OptimizeReport struct {
+	Nodes       int // dead nodes pruned
+	Collections int // unused variants collections dropped
+	TagSets     int // unused tag sets dropped
+	Tags        int // unused tags dropped
+}
+
+// String returns string representation of optimize report. Implements fmt.Stringer.
+func (report OptimizeReport) String() string {
+	return fmt.Sprintf(
+		"%d nodes, %d collections, %d tag sets, %d tags reclaimed",
+		report.Nodes, report.Collections, report.TagSets, report.Tags)
+}
+
+// Optimize compacts children relations and reduces index deleting dead nodes,
+// unused collections, tag set's and tags. Tags used as parents of used tags are kept.
+// Finally index data is renumbered into canonical order,
+// so indexes having the same content are always written into the same bytes.
+// Returns report of reclaimed data or error if index data is inconsistent.
+func (index *IndexBuilder) Optimize() (report OptimizeReport, err error) {
+	index.infof("compact %d children relations", index.children.Len())
+	index.children.Compact()
+
+	if report.Collections, err = index.dropUnusedCollections(); err != nil {
+		return report, fmt.Errorf("%w: optimize: %v", Error, err)
+	}
+
+	index.infof("canonicalize index")
+	nodesCount, tagSetsCount := index.NodesCount(), index.tagSets.Size()
+
+	if err = index.canonicalize(); err != nil {
+		return report, fmt.Errorf("%w: optimize: %v", Error, err)
+	}
+
+	report.Nodes = nodesCount - index.NodesCount()
+	report.TagSets = tagSetsCount - index.tagSets.Size()
+
+	if report.Tags, err = index.dropUnusedTags(); err != nil {
+		return report, fmt.Errorf("%w: optimize: %v", Error, err)
+	}
+
+	index.infof("optimized: %v", report)
+
+	return report, nil
+}
+
+// dropUnusedCollections removes collections not used by any item.
+// Returns count of removed collections.
+func (index *IndexBuilder) dropUnusedCollections() (int, error) {
 	usedCollectionID := make(map[VariantID][]dag.ID)
 	knownCollections := index.collectionIdx.KnownID()
-	logger.Infof("check %d known collections", len(knownCollections))
-	for _, node := range index.items.items {
+	index.infof("check %d known collections", len(knownCollections))
+	for _, node := range index.items.items[:index.items.NextID()] {
 		if node.Variants == 0 {
 			continue
 		}
@@ -500,7 +483,7 @@ func (index *Index) Optimize() {
 		usedCollectionID[node.Variants] = append(usedCollectionID[node.Variants], node.ID)
 	}
 
-	logger.Debugf("lookup unused collections")
+	index.debugf("lookup unused collections")
 	unusedCollections := make(CollectionIDList, 0)
 	for _, knownCollectionID := range knownCollections {
 		if _, ok := usedCollectionID[knownCollectionID]; !ok {
@@ -508,10 +491,10 @@ func (index *Index) Optimize() {
 		}
 	}
 
-	logger.Debugf("eliminate %d unused collections", len(unusedCollections))
+	index.debugf("eliminate %d unused collections", len(unusedCollections))
 	if len(unusedCollections) == 0 {
-		logger.Debugf("no unused collections")
-		return
+		index.debugf("no unused collections")
+		return 0, nil
 	}
 
 	sort.Sort(unusedCollections)
@@ -533,27 +516,26 @@ func (index *Index) Optimize() {
 			}
 			newCollection := newIndex.Get(newCollectionID)
 			if !collection.EqualTo(newCollection) {
-				logger.Errorf("old subID %X collection %v", collectionID, collection)
-				logger.Errorf("new subID %X collection %v", newCollectionID, newCollection)
-
-				panic(fmt.Errorf("replacement differs"))
+				return 0, fmt.Errorf(
+					"collection %#08x %v replacement %#08x differs: %v",
+					collectionID, collection, newCollectionID, newCollection)
 			}
 			replaceCollections = append(replaceCollections, replacementPair)
-			logger.Debugf("IDX? O%#08x N%#08x", replacementPair.old, replacementPair.new)
+			index.debugf("IDX? O%#08x N%#08x", replacementPair.old, replacementPair.new)
 		}
 	}
 
-	logger.Infof("have %d collectionID to replace in items", len(replaceCollections))
+	index.infof("have %d collectionID to replace in items", len(replaceCollections))
 	itemsUpdated := 0
 	for _, replacementPair := range replaceCollections {
 		itemsToUpdate, ok := usedCollectionID[replacementPair.old]
 		if !ok {
-			panic(fmt.Errorf("no items to update with collectionID %d", replacementPair.old))
+			return 0, fmt.Errorf("no items to update with collection %#08x", replacementPair.old)
 		}
 
 		for _, itemID := range itemsToUpdate {
-			if logger.IsEnabledForLevel(logging.LevelDebug) {
-				logger.Debugf(
+			if index.debugEnabled() {
+				index.debugf(
 					"IDX# I%08d O%#08x OL%v N%#08x NL%v",
 					itemID,
 					index.items.items[itemID].Variants,
@@ -565,15 +547,71 @@ func (index *Index) Optimize() {
 			itemsUpdated++
 		}
 	}
-	logger.Infof("%d items VariantID updated", itemsUpdated)
-	logger.Infof("reduced collection from %d to %d items", len(index.collectionIdx.KnownID()), len(newIndex.KnownID()))
+	index.infof("%d items VariantID updated", itemsUpdated)
+	index.infof("reduced collection from %d to %d items", len(index.collectionIdx.KnownID()), len(newIndex.KnownID()))
 	index.collectionIdx = newIndex
 
-	knownTagSets := index.tagSets.TableIDs()
-	logger.Infof("has %d possible tag sets", len(knownTagSets))
+	return len(unusedCollections), nil
+}
+
+// dropUnusedTags removes tags not used by any tag set except parents of used tags.
+// Remaining tags keep their relative order, so canonical order of tag sets stays the same.
+// Returns count of removed tags.
+func (index *IndexBuilder) dropUnusedTags() (int, error) {
+	used := make([]bool, index.tags.Len())
+
+	for _, table := range index.tagSets {
+		for _, tagSet := range table {
+			for _, tagID := range tagSet {
+				if int(tagID) >= len(used) {
+					return 0, fmt.Errorf("tag set %v refers unknown tag %d", tagSet, tagID)
+				}
+
+				index.markUsedTag(used, tagID)
+			}
+		}
+	}
+
+	newIDs := make([]dag.TagID, len(used))
+	tags := make(dag.Idx, 0, len(used))
+
+	for oldID, tag := range index.tags {
+		if used[oldID] {
+			newIDs[oldID] = dag.TagID(len(tags))
+			tags = append(tags, tag)
+		}
+	}
+
+	for _, table := range index.tagSets {
+		for _, tagSet := range table {
+			for idx, tagID := range tagSet {
+				tagSet[idx] = newIDs[tagID]
+			}
+		}
+	}
+
+	removed := index.tags.Len() - tags.Len()
+	index.tags = tags
+
+	return removed, nil
+}
+
+// markUsedTag marks specified tag and all its parents used.
+func (index *IndexBuilder) markUsedTag(used []bool, tagID dag.TagID) {
+	for !used[tagID] {
+		used[tagID] = true
+
+		tag, _ := index.tags.Get(tagID)
+		parentID, found := index.tags.Find(tag.Parent)
+		if !found {
+			return
+		}
+
+		tagID = parentID
+	}
 }
 
 // Variants returns TagSet collection
-func (index *Index) Variants(id VariantID) (tagSetCollection TagSetIDCollection, err error) {
+func (index *IndexBuilder) Variants(id VariantID) (tagSetCollection TagSetIDCollection, err error) {
 	return index.collectionIdx.Get(id), err
 }
@@ -1,8 +1,10 @@
 package index_test
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/amarin/binutils"
 	"github.com/stretchr/testify/require"
 
 	"github.com/amarin/gomorphy/internal/index"
@@ -10,7 +12,7 @@ import (
 )
 
 func TestNewIndex(t *testing.T) {
-	idx := index.New()
+	idx := index.NewBuilder()
 	require.Equal(t, idx.WordsCount(), 0)
 	require.Equal(t, idx.NodesCount(), 0)
 	last, err := idx.AddString("example")
@@ -43,7 +45,7 @@ func TestIndex_AddFetch(t *testing.T) {
 				err error
 			)
 
-			idxInstance := index.New()
+			idxInstance := index.NewBuilder()
 			if len(tt.addBefore) != 0 {
 				_, err = idxInstance.AddString(tt.addBefore)
 				require.NoErrorf(t, err, "%v", err)
@@ -60,7 +62,7 @@ func TestIndex_AddFetch(t *testing.T) {
 }
 
 func TestIndex_Add(t *testing.T) {
-	idx := index.New()
+	idx := index.NewBuilder()
 	_, err := idx.AddRunes([]rune{'a'})
 	require.NoError(t, err)
 	require.EqualValues(t, 1, idx.NodesCount())
@@ -94,7 +96,7 @@ func TestIndexImpl_Add(t *testing.T) {
 		{"test_&_check", []string{"test", "check"}, 9},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			idx := index.New()
+			idx := index.NewBuilder()
 			require.Equalf(t, 0, idx.NodesCount(), "expected empty index")
 
 			wordsAdded := 0
@@ -128,7 +130,7 @@ func TestIndex_NodesCount(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt := tt
 
-			idx := index.New()
+			idx := index.NewBuilder()
 			require.Equal(t, 0, idx.NodesCount())
 
 			for _, word := range tt.words {
@@ -142,7 +144,7 @@ func TestIndex_NodesCount(t *testing.T) {
 }
 
 func TestIndex_AddChild(t *testing.T) {
-	idx := index.New()
+	idx := index.NewBuilder()
 	_, err := idx.AddString("abcd")
 	require.NoError(t, err)
 
@@ -174,7 +176,7 @@ func TestIndex_AddStringCross(t *testing.T) {
 		{"write_x_wrote", "write", "wrote", "wrot", 7, 6},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			idx := index.New()
+			idx := index.NewBuilder()
 			_, err := idx.AddString(tt.addFirst)
 			require.NoError(t, err)
 			_, err = idx.AddString(tt.addSecond)
@@ -190,7 +192,7 @@ func TestIndex_AddStringCross(t *testing.T) {
 }
 
 func TestIndex_AddToNode(t *testing.T) {
-	idx := index.New()
+	idx := index.NewBuilder()
 	l0a, err := idx.AddToNode(0, []rune("a"))
 	require.NoError(t, err)
 	require.EqualValues(t, "a", l0a.Word())
@@ -207,3 +209,39 @@ func TestIndex_AddToNode(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, "abz", l0a1b2z.Word())
 }
+
+func TestIndex_BinaryWriteReadFetch(t *testing.T) {
+	words := []string{"кот", "кошка", "конь", "лес"}
+	idx := index.NewBuilder()
+	idx.TagID("NOUN", "POST")
+
+	for _, word := range words {
+		node, err := idx.AddString(word)
+		require.NoError(t, err)
+		require.NoError(t, node.AddTagSet("NOUN"))
+	}
+
+	_, err := idx.Optimize()
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, idx.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	loaded := new(index.ReadOnlyIndex)
+	require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+	require.Equal(t, idx.NodesCount(), loaded.NodesCount())
+	require.Equal(t, len(words), loaded.WordsCount())
+
+	for _, word := range words {
+		node, err := loaded.FetchString(word)
+		require.NoError(t, err)
+		require.Equal(t, word, node.Word())
+		require.Len(t, node.TagSets(), 1)
+	}
+
+	_, err = loaded.FetchString("ко")
+	require.NoError(t, err)
+	_, err = loaded.FetchString("кит")
+	require.Error(t, err)
+	require.Len(t, loaded.GetChildrenIDMap(0), 2)
+}
@@ -0,0 +1,216 @@
+package index
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/amarin/binutils"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/storage"
+)
+
+// LemmaID represents dictionary lemma ID.
+type LemmaID uint32
+
+// LemmaForm refers lemma word form node and tag set attached to node by lemma.
+type LemmaForm struct {
+	Node   dag.ID   // word form node ID
+	TagSet TagSetID // word form tag set ID
+}
+
+// lessLemmaForm reports whether LemmaForm a must sort before LemmaForm b.
+func lessLemmaForm(a LemmaForm, b LemmaForm) bool {
+	if a.Node != b.Node {
+		return a.Node < b.Node
+	}
+
+	return a.TagSet < b.TagSet
+}
+
+// Lemma stores dictionary lemma revision and forms added into index by lemma.
+// Used to find forms to update when dictionary lemma changed.
+// Forms are kept in index order, so lemma normal form is stored separately.
+type Lemma struct {
+	ID       LemmaID     // dictionary lemma ID
+	Revision uint32      // dictionary lemma revision
+	Normal   LemmaForm   // lemma normal form, i.e. first form dictionary lists, zero Node if unknown
+	Forms    []LemmaForm // unique lemma forms
+}
+
+// hasForm returns true if lemma already has specified form.
+func (lemma Lemma) hasForm(form LemmaForm) bool {
+	for _, existed := range lemma.Forms {
+		if existed == form {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clone makes a deep copy of Lemma.
+func (lemma Lemma) clone() *Lemma {
+	return &Lemma{
+		ID:       lemma.ID,
+		Revision: lemma.Revision,
+		Normal:   lemma.Normal,
+		Forms:    append([]LemmaForm{}, lemma.Forms...),
+	}
+}
+
+// BinaryWriteTo writes Lemma data using specified binutils.BinaryWriter instance.
+// Returns error if happens or nil.
+// Implements binutils.BinaryWriterTo.
+func (lemma Lemma) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
+	for _, value := range []uint64{uint64(lemma.ID), uint64(lemma.Revision)} {
+		if err = storage.WriteUvarint(writer, value); err != nil {
+			return fmt.Errorf("%w: lemma: %v", Error, err)
+		}
+	}
+
+	if err = writeLemmaForm(writer, lemma.Normal); err != nil {
+		return err
+	}
+
+	if err = storage.WriteUvarint(writer, uint64(len(lemma.Forms))); err != nil {
+		return fmt.Errorf("%w: lemma: %v", Error, err)
+	}
+
+	for _, form := range lemma.Forms {
+		if err = writeLemmaForm(writer, form); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeLemmaForm writes lemma form node and tag set IDs. A companion of readLemmaForm.
+func writeLemmaForm(writer *binutils.BinaryWriter, form LemmaForm) (err error) {
+	if err = storage.WriteUvarint(writer, uint64(form.Node)); err != nil {
+		return fmt.Errorf("%w: lemma: %v", Error, err)
+	}
+
+	if err = writer.WriteUint32(uint32(form.TagSet)); err != nil {
+		return fmt.Errorf("%w: lemma: %v", Error, err)
+	}
+
+	return nil
+}
+
+// readLemmaForm reads lemma form node and tag set IDs. A companion of writeLemmaForm.
+func readLemmaForm(reader *binutils.BinaryReader) (form LemmaForm, err error) {
+	var (
+		node     uint64
+		tagSetID uint32
+	)
+
+	if node, err = storage.ReadUvarintMax(reader, math.MaxUint32); err != nil {
+		return form, fmt.Errorf("%w: lemma: read form node: %v", Error, err)
+	}
+	if tagSetID, err = reader.ReadUint32(); err != nil {
+		return form, fmt.Errorf("%w: lemma: read form tag set: %v", Error, err)
+	}
+
+	return LemmaForm{Node: dag.ID(node), TagSet: TagSetID(tagSetID)}, nil
+}
+
+// BinaryReadFrom reads Lemma data using specified binutils.BinaryReader instance.
+// Returns error if happens or nil.
+// Implements binutils.BinaryReaderFrom.
+func (lemma *Lemma) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	var id, revision, formsCount uint64
+
+	if id, err = storage.ReadUvarintMax(reader, math.MaxUint32); err != nil {
+		return fmt.Errorf("%w: lemma: read id: %v", Error, err)
+	}
+	if revision, err = storage.ReadUvarintMax(reader, math.MaxUint32); err != nil {
+		return fmt.Errorf("%w: lemma: read revision: %v", Error, err)
+	}
+	if lemma.Normal, err = readLemmaForm(reader); err != nil {
+		return err
+	}
+	if formsCount, err = storage.ReadUvarintMax(reader, math.MaxUint32); err != nil {
+		return fmt.Errorf("%w: lemma: read forms count: %v", Error, err)
+	}
+
+	lemma.ID = LemmaID(id)
+	lemma.Revision = uint32(revision)
+	lemma.Forms = make([]LemmaForm, formsCount)
+
+	for idx := range lemma.Forms {
+		if lemma.Forms[idx], err = readLemmaForm(reader); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LemmaIndex stores dictionary lemmas by their IDs.
+type LemmaIndex map[LemmaID]*Lemma
+
+// IDs returns sorted list of known lemma IDs.
+func (lemmaIndex LemmaIndex) IDs() []LemmaID {
+	ids := make([]LemmaID, 0, len(lemmaIndex))
+	for id := range lemmaIndex {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// Clone makes a deep copy of LemmaIndex.
+func (lemmaIndex LemmaIndex) Clone() LemmaIndex {
+	res := make(LemmaIndex, len(lemmaIndex))
+	for id, lemma := range lemmaIndex {
+		res[id] = lemma.clone()
+	}
+
+	return res
+}
+
+// BinaryWriteTo writes LemmaIndex data ordered by lemma IDs using specified binutils.BinaryWriter instance.
+// Returns error if happens or nil.
+// Implements binutils.BinaryWriterTo.
+func (lemmaIndex LemmaIndex) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
+	if err = storage.WriteUvarint(writer, uint64(len(lemmaIndex))); err != nil {
+		return fmt.Errorf("%w: lemmas: %v", Error, err)
+	}
+
+	for _, id := range lemmaIndex.IDs() {
+		if err = lemmaIndex[id].BinaryWriteTo(writer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BinaryReadFrom reads LemmaIndex data using specified binutils.BinaryReader instance.
+// Returns error if happens or nil.
+// Implements binutils.BinaryReaderFrom.
+func (lemmaIndex *LemmaIndex) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	var lemmasCount uint64
+
+	if lemmasCount, err = storage.ReadUvarintMax(reader, math.MaxUint32); err != nil {
+		return fmt.Errorf("%w: lemmas: read count: %v", Error, err)
+	}
+
+	*lemmaIndex = make(LemmaIndex, lemmasCount)
+
+	for idx := 0; idx < int(lemmasCount); idx++ {
+		lemma := new(Lemma)
+		if err = lemma.BinaryReadFrom(reader); err != nil {
+			return err
+		}
+
+		(*lemmaIndex)[lemma.ID] = lemma
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package index
+
+import "fmt"
+
+// LemmaLookup finds tag sets and lemmas of single words.
+// Lemmas of forms are collected once on creation, so lookup is cheap while index itself is not changed.
+// LemmaLookup is safe for concurrent use.
+type LemmaLookup struct {
+	index *ReadOnlyIndex
+	links formLemmas
+}
+
+// LemmaLookup returns lookup of words tag sets and lemmas.
+func (index *ReadOnlyIndex) LemmaLookup() *LemmaLookup {
+	return &LemmaLookup{index: index, links: index.formLemmas()}
+}
+
+// Index returns looked up index.
+func (lookup *LemmaLookup) Index() *ReadOnlyIndex {
+	return lookup.index
+}
+
+// Word returns word tag sets in index order with lemmas having word form with every tag set.
+// Returns nil if word is not known to index or has no tag sets.
+// Returns error if index refers unknown collection or tag set.
+func (lookup *LemmaLookup) Word(word string) ([]WordEntry, error) {
+	node, err := lookup.index.FetchItemFromParent(0, []rune(word))
+	if err != nil {
+		return nil, nil
+	}
+
+	item := lookup.index.getItem(node.id)
+	if item == nil || item.Variants == 0 {
+		return nil, nil
+	}
+
+	collection, found := lookup.index.collectionIdx.Lookup(item.Variants)
+	if !found {
+		return nil, fmt.Errorf("%w: lookup: `%s`: no collection: %#08x", Error, word, item.Variants)
+	}
+
+	entries := make([]WordEntry, len(collection))
+
+	for idx, tagSetID := range collection {
+		tagSet, err := lookup.index.tagSet(tagSetID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: lookup: `%s`: %v", Error, word, err)
+		}
+
+		entries[idx] = WordEntry{
+			Word:   word,
+			TagSet: tagSet,
+			Lemmas: lookup.links.lemmas(LemmaForm{Node: node.id, TagSet: tagSetID}),
+		}
+	}
+
+	return entries, nil
+}
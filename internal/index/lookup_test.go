@@ -0,0 +1,54 @@
+package index_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func TestLemmaLookup_Word(t *testing.T) {
+	lookup := mustBuild(t, newLemmasBuilder(t, lemmasRevision1)).LemmaLookup()
+
+	entries, err := lookup.Word("печь")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "VERB", entries[0].TagSet.String())
+	require.Equal(t, []index.LemmaID{3}, entries[0].Lemmas)
+	require.Equal(t, "NOUN,sing", entries[1].TagSet.String())
+	require.Equal(t, []index.LemmaID{2}, entries[1].Lemmas)
+
+	for _, word := range []string{"", "ко", "собака"} {
+		entries, err = lookup.Word(word)
+		require.NoError(t, err)
+		require.Nil(t, entries, word) // unknown words and prefix nodes have no entries
+	}
+}
+
+func TestReadOnlyIndex_LemmaEntry(t *testing.T) {
+	compiled := mustBuild(t, newLemmasBuilder(t, lemmasRevision1))
+
+	entry, err := compiled.LemmaEntry(3)
+	require.NoError(t, err)
+	require.Equal(t, index.LemmaID(3), entry.ID)
+	require.Len(t, entry.Forms, 2)
+	require.Equal(t, "пёк", entry.Forms[1].Word)
+	require.Equal(t, "VERB,sing", entry.Forms[1].TagSet.String())
+
+	_, err = compiled.LemmaEntry(100)
+	require.ErrorIs(t, err, index.Error)
+
+	// normal form is listed first regardless of index order
+	compiled = mustBuild(t, newLemmasBuilder(t, []testLemma{{1, 1, []index.WordForm{
+		{Word: "ёж", Tags: []dag.TagName{"NOUN", "sing"}},
+		{Word: "ежи", Tags: []dag.TagName{"NOUN", "plur"}},
+	}}}))
+
+	entry, err = compiled.LemmaEntry(1)
+	require.NoError(t, err)
+	require.Len(t, entry.Forms, 2)
+	require.Equal(t, "ёж", entry.Forms[0].Word)
+	require.Equal(t, "ежи", entry.Forms[1].Word)
+}
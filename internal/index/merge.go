@@ -0,0 +1,142 @@
+package index
+
+import (
+	"fmt"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// Merge adds all words of another IndexBuilder with their tag sets and lemmas into index.
+// Tags unknown to index are registered using their names and parents.
+// Another builder is not changed but must not be modified concurrently.
+func (index *IndexBuilder) Merge(another *IndexBuilder) error {
+	type mergePair struct {
+		source dag.ID // node ID in another index
+		target dag.ID // node ID in index
+	}
+
+	tagIDs := make([]dag.TagID, another.tags.Len())
+	for tagIdx, tag := range another.tags {
+		tagIDs[tagIdx] = index.TagID(tag.Name, tag.Parent)
+	}
+
+	tagSetIDs := make(map[TagSetID]TagSetID)
+	targetTagSetID := func(sourceTagSetID TagSetID) (TagSetID, error) {
+		if known, ok := tagSetIDs[sourceTagSetID]; ok {
+			return known, nil
+		}
+
+		sourceTagSet, found := another.tagSets.Get(sourceTagSetID)
+		if !found {
+			return 0, fmt.Errorf("%w: merge: no tag set: %#08x", Error, sourceTagSetID)
+		}
+
+		targetTagSet := make(TagSet, len(sourceTagSet))
+		for tagIdx, sourceTagID := range sourceTagSet {
+			if int(sourceTagID) >= len(tagIDs) {
+				return 0, fmt.Errorf("%w: merge: no tag: %d", Error, sourceTagID)
+			}
+			targetTagSet[tagIdx] = tagIDs[sourceTagID]
+		}
+
+		tagSetIDs[sourceTagSetID] = index.tagSets.Index(targetTagSet)
+
+		return tagSetIDs[sourceTagSetID], nil
+	}
+
+	nodeIDs := make([]dag.ID, another.items.NextID())
+	stack := []mergePair{{source: 0, target: 0}}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, child := range another.children.Children(current.source) {
+			targetID := index.childID(current.target, child.Letter)
+			sourceItem := another.items.Get(child.ID)
+
+			if sourceItem == nil {
+				return fmt.Errorf("%w: merge: no item: %d", Error, child.ID)
+			}
+
+			nodeIDs[child.ID] = targetID
+
+			for _, sourceTagSetID := range another.collectionIdx.Get(sourceItem.Variants) {
+				tagSetID, err := targetTagSetID(sourceTagSetID)
+				if err != nil {
+					return err
+				}
+
+				index.addTagSetID(index.items.Get(targetID), tagSetID)
+			}
+
+			stack = append(stack, mergePair{source: child.ID, target: targetID})
+		}
+	}
+
+	return index.mergeLemmas(another, nodeIDs, targetTagSetID)
+}
+
+// mergeLemmas adds lemmas of another IndexBuilder into index using specified nodes and tag sets mapping.
+// Forms of lemmas known to both indexes are joined, revision of another lemma is taken.
+// Normal form of another lemma is taken only if lemma has no normal form yet.
+func (index *IndexBuilder) mergeLemmas(
+	another *IndexBuilder, nodeIDs []dag.ID, targetTagSetID func(TagSetID) (TagSetID, error),
+) error {
+	for _, id := range another.lemmas.IDs() {
+		sourceLemma := another.lemmas[id]
+
+		lemma, known := index.lemmas[id]
+		if !known {
+			lemma = &Lemma{
+				ID:       id,
+				Revision: sourceLemma.Revision,
+				Normal:   LemmaForm{},
+				Forms:    make([]LemmaForm, 0, len(sourceLemma.Forms)),
+			}
+			index.lemmas[id] = lemma
+		}
+
+		lemma.Revision = sourceLemma.Revision
+
+		targetForm := func(sourceForm LemmaForm) (LemmaForm, error) {
+			if int(sourceForm.Node) >= len(nodeIDs) || nodeIDs[sourceForm.Node] == 0 {
+				return LemmaForm{}, fmt.Errorf("%w: merge: lemma %d: no item: %d", Error, id, sourceForm.Node)
+			}
+
+			tagSetID, err := targetTagSetID(sourceForm.TagSet)
+			if err != nil {
+				return LemmaForm{}, err
+			}
+
+			return LemmaForm{Node: nodeIDs[sourceForm.Node], TagSet: tagSetID}, nil
+		}
+
+		if lemma.Normal.Node == 0 && sourceLemma.Normal.Node != 0 {
+			normal, err := targetForm(sourceLemma.Normal)
+			if err != nil {
+				return err
+			}
+
+			lemma.Normal = normal
+		}
+
+		for _, sourceForm := range sourceLemma.Forms {
+			form, err := targetForm(sourceForm)
+			if err != nil {
+				return err
+			}
+
+			if lemma.hasForm(form) {
+				continue
+			}
+
+			lemma.Forms = append(lemma.Forms, form)
+			if index.formRefs != nil {
+				index.formRefs[form]++
+			}
+		}
+	}
+
+	return nil
+}
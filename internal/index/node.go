@@ -8,7 +8,7 @@ import (
 
 // Node represents index node.
 type Node struct {
-	index *Index
+	index *IndexBuilder
 	id    dag.ID
 }
 
@@ -50,21 +50,12 @@ func (node *Node) TagSets() (res []dag.TagSet) {
 }
 
 func (node *Node) AddTagSet(newTagSet ...dag.TagName) error {
-	var found bool
-	tagSet := make(TagSet, len(newTagSet))
-	for idx, tagName := range newTagSet {
-		tagSet[idx], found = node.index.tags.Find(tagName)
-		if !found {
-			return fmt.Errorf("%w: add tag set: unknown tag: %v", Error, tagName)
-		}
+	tagSetID, err := node.index.tagSetID(newTagSet)
+	if err != nil {
+		return fmt.Errorf("%w: add tag set: %v", Error, err)
 	}
 
-	item := node.index.getItem(node.id)
-	collection := append(node.index.collectionIdx.Get(item.Variants).Add(node.index.tagSets.Index(tagSet)))
-	if item.Variants == 0 {
-		node.index.wordsCount++
-	}
-	item.Variants = node.index.collectionIdx.Index(collection)
+	node.index.addTagSetID(node.index.getItem(node.id), tagSetID)
 
 	return nil
 }
@@ -96,7 +87,7 @@ func (node *Node) String() string {
 	return "Node(" + w[:len(w)-1] + "[" + string(item.Letter) + "]" + item.String()[1:] + ")"
 }
 
-func newNode(index *Index, id dag.ID) *Node {
+func newNode(index *IndexBuilder, id dag.ID) *Node {
 	return &Node{
 		index: index,
 		id:    id,
@@ -9,7 +9,7 @@ import (
 )
 
 func TestNode_Add(t *testing.T) {
-	idx := index.New()
+	idx := index.NewBuilder()
 	_, err := idx.AddString("test")
 	require.NoError(t, err)
 	// require.Equal(t, 4, int(node1.ID()))
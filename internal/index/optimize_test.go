@@ -0,0 +1,21 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexBuilder_Optimize_Inconsistent(t *testing.T) {
+	builder := NewBuilder()
+	builder.TagID("NOUN", "POST")
+	require.NoError(t, builder.AddTagSet("кот", "NOUN"))
+
+	// collection referring tag set missed in index
+	item := builder.items.Get(builder.items.NextID() - 1)
+	item.Variants = builder.collectionIdx.Index(TagSetIDCollection{0x00050001})
+
+	_, err := builder.Optimize()
+	require.ErrorIs(t, err, Error)
+	require.Contains(t, err.Error(), "unknown tag set")
+}
@@ -0,0 +1,270 @@
+package index
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// ErrReadOnly indicates attempt to modify ReadOnlyIndex.
+var ErrReadOnly = fmt.Errorf("%w: read only", Error)
+
+// ReadOnlyIndex implements frozen dictionary index.
+// ReadOnlyIndex data never changes after creation, so it is safe for unlimited concurrent readers
+// without any locking. Lookups never write to internal structures.
+// Use IndexBuilder.Build or IndexBuilder.Freeze to make ReadOnlyIndex from filled index
+// or BinaryReadFrom to load it from compiled data.
+type ReadOnlyIndex struct {
+	tags          dag.Idx       // Tag's storage
+	tagSets       TagSetIndex   // TagSet's storage
+	collectionIdx VariantsIndex // TagSetIDCollection storage
+	items         []Item        // Items storage
+	children      *ChildIndex   // compacted parent to children relations
+	lemmas        LemmaIndex    // dictionary lemmas forms and revisions
+	wordsCount    int
+}
+
+// Freeze makes ReadOnlyIndex having a copy of current index data.
+// Later index changes are not visible in ReadOnlyIndex.
+func (index *IndexBuilder) Freeze() *ReadOnlyIndex {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	items := make([]Item, index.items.NextID())
+	copy(items, index.items.items)
+
+	return &ReadOnlyIndex{
+		tags:          append(make(dag.Idx, 0, index.tags.Len()), index.tags...),
+		tagSets:       index.tagSets.Clone(),
+		collectionIdx: index.collectionIdx.Clone(),
+		items:         items,
+		children:      BuildChildIndex(items),
+		lemmas:        index.lemmas.Clone(),
+		wordsCount:    index.wordsCount,
+	}
+}
+
+// Thaw makes IndexBuilder having a copy of ReadOnlyIndex data, so index could be updated.
+// Later builder changes are not visible in ReadOnlyIndex.
+func (index *ReadOnlyIndex) Thaw() *IndexBuilder {
+	items := make([]Item, len(index.items))
+	copy(items, index.items)
+
+	if len(items) == 0 {
+		items = make([]Item, 1)
+	}
+
+	return &IndexBuilder{
+		mu:            new(sync.Mutex),
+		tags:          append(make(dag.Idx, 0, index.tags.Len()), index.tags...),
+		tagSets:       index.tagSets.Clone(),
+		collectionIdx: index.collectionIdx.Clone(),
+		items:         Items{items: items, mu: new(sync.Mutex), nextID: dag.ID(len(items))},
+		children:      BuildChildIndex(items),
+		lemmas:        index.lemmas.Clone(),
+		formRefs:      nil,
+		logger:        nil,
+		wordsCount:    index.wordsCount,
+	}
+}
+
+// FetchRunes lookups runes sequence in index.
+// If found returns final node or error if not found.
+func (index *ReadOnlyIndex) FetchRunes(runes []rune) (dag.Node, error) {
+	node, err := index.FetchItemFromParent(0, runes)
+	if err != nil {
+		return nil, err
+	}
+
+	return node, nil
+}
+
+// FetchString lookups string in index.
+// If found returns final node or error if not found.
+func (index *ReadOnlyIndex) FetchString(word string) (dag.Node, error) {
+	return index.FetchRunes([]rune(word))
+}
+
+// FetchItemFromParent lookups runes sequence starting from specified parent node.
+// If found returns final node or error if not found.
+func (index *ReadOnlyIndex) FetchItemFromParent(parentID dag.ID, runes []rune) (*ReadOnlyNode, error) {
+	var (
+		nextItemID dag.ID
+		ok         bool
+	)
+
+	if len(runes) == 0 {
+		return nil, fmt.Errorf("%w: empty runes", Error)
+	}
+
+	currentParentID := parentID
+	for currentIndex, letter := range runes {
+		if nextItemID, ok = index.children.Find(currentParentID, letter); !ok {
+			if currentParentID != 0 && index.getItem(currentParentID) == nil {
+				return nil, fmt.Errorf("%w: fetch: no node: `%s[%s]`", Error, string(runes[:currentIndex]), string(letter))
+			}
+
+			return nil, fmt.Errorf("%w: fetch: not found: `%s[%s]`", Error, string(runes[:currentIndex]), string(letter))
+		}
+
+		currentParentID = nextItemID
+	}
+
+	return index.GetItem(currentParentID), nil
+}
+
+// Get returns node by its index or error if no such node found.
+func (index *ReadOnlyIndex) Get(nodeIdx dag.ID) (node dag.Node, err error) {
+	if index.getItem(nodeIdx) == nil {
+		return nil, fmt.Errorf("%w: no such node: %d", Error, nodeIdx)
+	}
+
+	return index.GetItem(nodeIdx), nil
+}
+
+// GetItem returns ReadOnlyNode instance for specified node ID.
+func (index *ReadOnlyIndex) GetItem(id dag.ID) *ReadOnlyNode {
+	return &ReadOnlyNode{index: index, id: id}
+}
+
+// getItem returns item by its ID or nil if no such item.
+func (index *ReadOnlyIndex) getItem(id dag.ID) *Item {
+	if id == 0 || int(id) >= len(index.items) {
+		return nil
+	}
+
+	return &index.items[id]
+}
+
+// GetChildrenIDMap generates children IDs map for node specified by its ID.
+func (index *ReadOnlyIndex) GetChildrenIDMap(id dag.ID) (res dag.IdMap) {
+	children := index.children.Children(id)
+	res = make(dag.IdMap, len(children))
+
+	for _, child := range children {
+		res[child.Letter] = child.ID
+	}
+
+	return res
+}
+
+// Tags returns internal tags index.
+func (index *ReadOnlyIndex) Tags() dag.Idx {
+	return index.tags
+}
+
+// TagSetIndex returns internal TagSetIndex.
+func (index *ReadOnlyIndex) TagSetIndex() TagSetIndex {
+	return index.tagSets
+}
+
+// Variants returns TagSet collection.
+func (index *ReadOnlyIndex) Variants(id VariantID) (tagSetCollection TagSetIDCollection, err error) {
+	return index.collectionIdx.Get(id), nil
+}
+
+// WordsCount returns count of indexed words.
+func (index *ReadOnlyIndex) WordsCount() int {
+	return index.wordsCount
+}
+
+// LemmasCount returns count of known dictionary lemmas.
+func (index *ReadOnlyIndex) LemmasCount() int {
+	return len(index.lemmas)
+}
+
+// Lemma returns a copy of known dictionary lemma by its ID.
+// If no such lemma known returns false found indicator.
+func (index *ReadOnlyIndex) Lemma(id LemmaID) (lemma Lemma, found bool) {
+	known, found := index.lemmas[id]
+	if !found {
+		return lemma, false
+	}
+
+	return *known.clone(), true
+}
+
+// NodesCount returns count of indexed nodes.
+func (index *ReadOnlyIndex) NodesCount() int {
+	if len(index.items) == 0 {
+		return 0
+	}
+
+	return len(index.items) - 1
+}
+
+// ReadOnlyNode represents ReadOnlyIndex node. Implements dag.Node.
+type ReadOnlyNode struct {
+	index *ReadOnlyIndex
+	id    dag.ID
+}
+
+// Id returns node ID.
+func (node *ReadOnlyNode) Id() dag.ID {
+	return node.id
+}
+
+// Item returns node Item.
+func (node *ReadOnlyNode) Item() Item {
+	return node.index.items[node.id]
+}
+
+// TagSets returns list of dag.TagSet. Implements dag.Node.
+func (node *ReadOnlyNode) TagSets() (res []dag.TagSet) {
+	item := node.index.getItem(node.id)
+	if item == nil {
+		return nil
+	}
+
+	collection := node.index.collectionIdx.Get(item.Variants)
+	res = make([]dag.TagSet, collection.Len())
+
+	for idx, tableID := range collection {
+		tagSetIDs, found := node.index.tagSets.Get(tableID)
+		if !found {
+			return res
+		}
+
+		res[idx] = make(dag.TagSet, tagSetIDs.Len())
+		for tagIdx, tagID := range tagSetIDs {
+			if tag, found := node.index.tags.Get(tagID); found {
+				res[idx][tagIdx] = tag
+			}
+		}
+	}
+
+	return res
+}
+
+// AddTagSet always returns ErrReadOnly as ReadOnlyIndex can't be changed. Implements dag.Node.
+func (node *ReadOnlyNode) AddTagSet(_ ...dag.TagName) error {
+	return fmt.Errorf("%w: add tag set", ErrReadOnly)
+}
+
+// Word returns sequence of characters from root upto current node wrapped into string. Implements dag.Node.
+func (node *ReadOnlyNode) Word() string {
+	letters := make([]rune, 0)
+
+	for item := node.index.getItem(node.id); item != nil; item = node.index.getItem(item.Parent) {
+		letters = append(letters, item.Letter)
+	}
+
+	for left, right := 0, len(letters)-1; left < right; left, right = left+1, right-1 {
+		letters[left], letters[right] = letters[right], letters[left]
+	}
+
+	return string(letters)
+}
+
+// String returns string representation of node. Implements fmt.Stringer.
+func (node *ReadOnlyNode) String() string {
+	item := node.index.getItem(node.id)
+	if item == nil {
+		return fmt.Sprintf("Node(no item: %v)", node.id)
+	}
+
+	w := []rune(node.Word())
+
+	return "Node(" + string(w[:len(w)-1]) + "[" + string(item.Letter) + "]" + item.String()[len(string(item.Letter)):] + ")"
+}
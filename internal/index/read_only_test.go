@@ -0,0 +1,144 @@
+package index_test
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func newTestIndex(t *testing.T, words ...string) *index.IndexBuilder {
+	t.Helper()
+
+	idx := index.NewBuilder()
+	idx.TagID("NOUN", "POST")
+	idx.TagID("sing", "NMbr")
+	idx.TagID("plur", "NMbr")
+
+	for wordIdx, word := range words {
+		node, err := idx.AddString(word)
+		require.NoError(t, err)
+		require.NoError(t, node.AddTagSet("NOUN", "sing"))
+		if wordIdx%2 == 0 {
+			require.NoError(t, node.AddTagSet("NOUN", "plur"))
+		}
+	}
+
+	return idx
+}
+
+func TestIndex_Freeze(t *testing.T) {
+	idx := newTestIndex(t, "кот", "кошка", "лес")
+	frozen := idx.Freeze()
+
+	require.Equal(t, idx.NodesCount(), frozen.NodesCount())
+	require.Equal(t, idx.WordsCount(), frozen.WordsCount())
+
+	node, err := frozen.FetchString("кот")
+	require.NoError(t, err)
+	require.Equal(t, "кот", node.Word())
+	require.Len(t, node.TagSets(), 2)
+	require.ErrorIs(t, node.AddTagSet("NOUN"), index.ErrReadOnly)
+
+	// changes of source index are not visible in frozen one
+	_, err = idx.AddString("лесник")
+	require.NoError(t, err)
+	catNode, err := idx.FetchString("кошка")
+	require.NoError(t, err)
+	require.NoError(t, catNode.AddTagSet("NOUN", "plur"))
+
+	_, err = frozen.FetchString("лесник")
+	require.Error(t, err)
+	node, err = frozen.FetchString("кошка")
+	require.NoError(t, err)
+	require.Len(t, node.TagSets(), 1)
+}
+
+func TestReadOnlyIndex_BinaryReadFrom(t *testing.T) {
+	idx := newTestIndex(t, "кот", "кошка", "лес")
+	buffer := new(bytes.Buffer)
+	require.NoError(t, idx.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	loaded := new(index.ReadOnlyIndex)
+	require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+	require.Equal(t, idx.NodesCount(), loaded.NodesCount())
+	require.Equal(t, 3, loaded.WordsCount())
+
+	node, err := loaded.FetchString("кошка")
+	require.NoError(t, err)
+	require.Equal(t, "кошка", node.Word())
+	require.Equal(t, []dag.TagSet{{{Parent: "POST", Name: "NOUN"}, {Parent: "NMbr", Name: "sing"}}}, node.TagSets())
+
+	_, err = loaded.FetchString("кит")
+	require.Error(t, err)
+	_, err = loaded.FetchString("")
+	require.Error(t, err)
+}
+
+// TestReadOnlyIndex_ConcurrentReaders ensures lookups are safe for concurrent readers.
+// Run with -race to detect data races.
+func TestReadOnlyIndex_ConcurrentReaders(t *testing.T) {
+	words := make([]string, 0, 200)
+	for wordIdx := 0; wordIdx < 200; wordIdx++ {
+		words = append(words, "слово"+strconv.Itoa(wordIdx))
+	}
+
+	frozen := newTestIndex(t, words...).Freeze()
+	readersCount := 16
+	waitGroup := new(sync.WaitGroup)
+	errs := make(chan error, readersCount)
+
+	for readerIdx := 0; readerIdx < readersCount; readerIdx++ {
+		waitGroup.Add(1)
+		go func(readerIdx int) {
+			defer waitGroup.Done()
+			for iteration := 0; iteration < 3; iteration++ {
+				for wordIdx := readerIdx; wordIdx < len(words); wordIdx += 3 {
+					node, err := frozen.FetchString(words[wordIdx])
+					if err != nil {
+						errs <- err
+						return
+					}
+					_ = node.Word()
+					_ = node.TagSets()
+					_ = node.String()
+					_ = frozen.GetChildrenIDMap(0)
+					_, _ = frozen.FetchString("несуществующее")
+				}
+			}
+		}(readerIdx)
+	}
+
+	waitGroup.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestIndexBuilder_Build(t *testing.T) {
+	builder := index.NewBuilder()
+	builder.TagID("NOUN", "POST")
+	builder.TagID("VERB", "POST")
+	require.NoError(t, builder.AddTagSet("печь", "NOUN"))
+	require.NoError(t, builder.AddTagSet("печь", "VERB"))
+	require.NoError(t, builder.AddTagSet("печка", "NOUN"))
+	require.Error(t, builder.AddTagSet("печка", "ADJF"))
+	require.Error(t, builder.AddTagSet("", "NOUN"))
+
+	built := mustBuild(t, builder)
+	require.Equal(t, 2, built.WordsCount())
+	require.Equal(t, 0, builder.NodesCount())
+	require.Equal(t, 0, builder.WordsCount())
+
+	node, err := built.FetchString("печь")
+	require.NoError(t, err)
+	require.Len(t, node.TagSets(), 2)
+}
@@ -0,0 +1,117 @@
+package index
+
+import (
+	"fmt"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// RemoveTagSet detaches specified tag set from word.
+// Word having no more tag sets is not counted as word and its dead branch is pruned by Optimize.
+// Lemmas forget removed word form, so later lemma updates will not restore it unless lemma changed.
+// Returns ErrNotFound if word unknown or has no such tag set.
+func (index *IndexBuilder) RemoveTagSet(word string, tagSet ...dag.TagName) error {
+	item, err := index.wordItem(word)
+	if err != nil {
+		return fmt.Errorf("%w: remove tag set: %v", ErrNotFound, err)
+	}
+
+	tagSetID, found := index.findTagSetID(tagSet)
+	if !found || !index.collectionIdx.Get(item.Variants).Has(tagSetID) {
+		return fmt.Errorf("%w: remove tag set: `%s` has no tag set %v", ErrNotFound, word, tagSet)
+	}
+
+	index.removeTagSetID(item, tagSetID)
+	index.forgetForm(LemmaForm{Node: item.ID, TagSet: tagSetID})
+
+	return nil
+}
+
+// RemoveWord detaches all tag sets from word.
+// Word is not counted as word anymore and its dead branch is pruned by Optimize.
+// Lemmas forget removed word forms, so later lemma updates will not restore them unless lemma changed.
+// Returns ErrNotFound if word unknown.
+func (index *IndexBuilder) RemoveWord(word string) error {
+	item, err := index.wordItem(word)
+	if err != nil {
+		return fmt.Errorf("%w: remove word: %v", ErrNotFound, err)
+	}
+
+	for _, tagSetID := range index.collectionIdx.Get(item.Variants) {
+		index.removeTagSetID(item, tagSetID)
+		index.forgetForm(LemmaForm{Node: item.ID, TagSet: tagSetID})
+	}
+
+	return nil
+}
+
+// RemoveLemma removes lemma and detaches its forms not shared with other lemmas.
+// Words having no more tag sets are pruned by Optimize.
+// Returns ErrNotFound if lemma unknown.
+func (index *IndexBuilder) RemoveLemma(id LemmaID) error {
+	if !index.removeLemma(id) {
+		return fmt.Errorf("%w: remove lemma: %d", ErrNotFound, id)
+	}
+
+	return nil
+}
+
+// wordItem returns item of indexed word.
+// Returns error if no such word or word has no tag sets.
+func (index *IndexBuilder) wordItem(word string) (*Item, error) {
+	if len(word) == 0 {
+		return nil, fmt.Errorf("empty word")
+	}
+
+	nodeID := dag.ID(0)
+	for _, letter := range word {
+		childID, found := index.children.Find(nodeID, letter)
+		if !found {
+			return nil, fmt.Errorf("unknown word `%s`", word)
+		}
+
+		nodeID = childID
+	}
+
+	item := index.items.Get(nodeID)
+	if item == nil || item.Variants == 0 {
+		return nil, fmt.Errorf("unknown word `%s`", word)
+	}
+
+	return item, nil
+}
+
+// findTagSetID returns ID of TagSet having specified tag names in any order.
+// If any tag or tag set is unknown returns false found indicator.
+func (index *IndexBuilder) findTagSetID(tagNames []dag.TagName) (tagSetID TagSetID, found bool) {
+	tagSet := make(TagSet, len(tagNames))
+	for idx, tagName := range tagNames {
+		if tagSet[idx], found = index.tags.Find(tagName); !found {
+			return 0, false
+		}
+	}
+
+	tagSet.Sort() // stored tag sets are sorted by tag IDs
+
+	return index.tagSets.Find(tagSet)
+}
+
+// forgetForm removes specified form from all lemmas forms. Lemma having form as normal form forgets it too.
+func (index *IndexBuilder) forgetForm(form LemmaForm) {
+	if index.formRefs != nil {
+		delete(index.formRefs, form)
+	}
+
+	for _, lemma := range index.lemmas {
+		if lemma.Normal == form {
+			lemma.Normal = LemmaForm{}
+		}
+
+		for idx, known := range lemma.Forms {
+			if known == form {
+				lemma.Forms = append(lemma.Forms[:idx], lemma.Forms[idx+1:]...)
+				break
+			}
+		}
+	}
+}
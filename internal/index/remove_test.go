@@ -0,0 +1,126 @@
+package index_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func TestIndexBuilder_RemoveTagSet(t *testing.T) {
+	builder := newLemmasBuilder(t, lemmasRevision1)
+
+	require.ErrorIs(t, builder.RemoveTagSet("печь", "NOUN", "plur"), index.ErrNotFound)
+	require.ErrorIs(t, builder.RemoveTagSet("печь", "ADJF"), index.ErrNotFound)
+	require.ErrorIs(t, builder.RemoveTagSet("печка", "VERB"), index.ErrNotFound)
+	require.NoError(t, builder.RemoveTagSet("печь", "VERB"))
+	require.ErrorIs(t, builder.RemoveTagSet("печь", "VERB"), index.ErrNotFound)
+	requireWordTagSets(t, builder, "печь", "NOUN,sing")
+	require.Equal(t, 4, builder.WordsCount())
+
+	compiled := mustBuild(t, builder)
+	requireWordTagSets(t, compiled, "печь", "NOUN,sing")
+
+	lemma, found := compiled.Lemma(3)
+	require.True(t, found)
+	require.Len(t, lemma.Forms, 1) // removed form is forgotten by lemma
+}
+
+func TestIndexBuilder_RemoveTagSet_TagsOrder(t *testing.T) {
+	builder := newTagsBuilder()
+	require.NoError(t, builder.AddTagSet("кот", "sing", "NOUN"))
+	require.NoError(t, builder.AddTagSet("коты", "plur", "NOUN"))
+	require.NoError(t, builder.AddTagSet("лес", "NOUN", "sing"))
+
+	require.NoError(t, builder.RemoveTagSet("кот", "sing", "NOUN"))  // added order, not tag IDs order
+	require.NoError(t, builder.RemoveTagSet("коты", "NOUN", "plur")) // order differs from added one
+	require.NoError(t, builder.RemoveTagSet("лес", "sing", "NOUN"))  // added in tag IDs order
+	require.Zero(t, builder.WordsCount())
+}
+
+func TestIndexBuilder_RemoveWord(t *testing.T) {
+	builder := newLemmasBuilder(t, lemmasRevision1)
+
+	require.ErrorIs(t, builder.RemoveWord("ко"), index.ErrNotFound) // prefix node is not a word
+	require.NoError(t, builder.RemoveWord("коты"))
+	require.ErrorIs(t, builder.RemoveWord("коты"), index.ErrNotFound)
+	require.NoError(t, builder.RemoveWord("пёк"))
+	require.Equal(t, 2, builder.WordsCount())
+
+	compiled := mustBuild(t, builder)
+	require.Equal(t, 2, compiled.WordsCount())
+	require.Equal(t, len([]rune("кот"))+len([]rune("печь")), compiled.NodesCount()) // dead branches pruned
+	requireWordTagSets(t, compiled, "кот", "NOUN,sing")
+	requireWordTagSets(t, compiled, "печь", "NOUN,sing", "VERB")
+
+	for _, word := range []string{"коты", "пёк"} {
+		_, err := compiled.FetchString(word)
+		require.Error(t, err, word)
+	}
+
+	// tag sets not used by remaining words are dropped
+	require.Equal(t, 2, compiled.TagSetIndex().Size())
+}
+
+func TestIndexBuilder_RemoveLemma(t *testing.T) {
+	builder := newLemmasBuilder(t, lemmasRevision1)
+
+	require.ErrorIs(t, builder.RemoveLemma(100), index.ErrNotFound)
+	require.NoError(t, builder.RemoveLemma(3))
+	require.ErrorIs(t, builder.RemoveLemma(3), index.ErrNotFound)
+
+	compiled := mustBuild(t, builder)
+	require.Equal(t, 2, compiled.LemmasCount())
+	require.Equal(t, 3, compiled.WordsCount())
+	requireWordTagSets(t, compiled, "печь", "NOUN,sing")
+
+	_, err := compiled.FetchString("пёк")
+	require.Error(t, err)
+
+	// index is the same as built without removed lemma
+	expected := newLemmasBuilder(t, lemmasRevision1[:2])
+	require.Equal(t, writeOptimized(t, expected), writeOptimized(t, compiled.Thaw()))
+}
+
+func TestIndexBuilder_Optimize_KeepsLiveBranches(t *testing.T) {
+	builder := newTagsBuilder()
+	require.NoError(t, builder.AddTagSet("лес", dag.TagName("NOUN")))
+	require.NoError(t, builder.AddTagSet("лесник", dag.TagName("NOUN")))
+	require.NoError(t, builder.RemoveWord("лес"))
+
+	compiled := mustBuild(t, builder)
+	require.Equal(t, len([]rune("лесник")), compiled.NodesCount()) // nodes leading to live word are kept
+	requireWordTagSets(t, compiled, "лесник", "NOUN")
+	requireWordTagSets(t, compiled, "лес")
+}
+
+func TestIndexBuilder_Optimize_Report(t *testing.T) {
+	builder := newTagsBuilder()
+	builder.TagID("ADJF", "POST")
+	builder.TagID("NMbr", "")
+	builder.TagID("POST", "")
+	require.NoError(t, builder.AddTagSet("кот", "NOUN", "sing"))
+	require.NoError(t, builder.AddTagSet("коты", "NOUN", "plur"))
+	require.NoError(t, builder.AddTagSet("пёк", "VERB"))
+	require.NoError(t, builder.RemoveWord("пёк"))
+	require.NoError(t, builder.RemoveTagSet("коты", "NOUN", "plur"))
+
+	report, err := builder.Optimize()
+	require.NoError(t, err)
+	require.Equal(t, index.OptimizeReport{Nodes: 4, Collections: 2, TagSets: 2, Tags: 3}, report)
+
+	// unused VERB, plur and ADJF are dropped, parents of used tags are kept
+	tagNames := make([]dag.TagName, 0)
+	for _, tag := range builder.Tags() {
+		tagNames = append(tagNames, tag.Name)
+	}
+
+	require.Equal(t, []dag.TagName{"NOUN", "sing", "NMbr", "POST"}, tagNames)
+	requireWordTagSets(t, builder, "кот", "NOUN,sing")
+
+	report, err = builder.Optimize()
+	require.NoError(t, err)
+	require.Equal(t, index.OptimizeReport{Nodes: 0, Collections: 0, TagSets: 0, Tags: 0}, report)
+}
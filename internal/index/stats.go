@@ -0,0 +1,118 @@
+package index
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/amarin/binutils"
+)
+
+// SectionSizes provides sizes in bytes of compiled index binary sections written in FormatCurrent uncompressed.
+type SectionSizes struct {
+	Header   int // format header
+	Tags     int // tags definitions
+	TagSets  int // TagSetIndex tables
+	Variants int // VariantsIndex tables
+	Items    int // nodes
+	Lemmas   int // dictionary lemmas forms and revisions
+	Total    int // whole compiled index
+}
+
+// Stats provides compiled index contents statistics.
+type Stats struct {
+	Words         int              // count of indexed words
+	Forms         int              // count of words tag sets, i.e. indexed word forms
+	Nodes         int              // count of indexed nodes
+	Lemmas        int              // count of known dictionary lemmas
+	LemmaForms    int              // count of known dictionary lemmas forms
+	Tags          int              // count of known tags
+	TagSetTables  []int            // count of tag sets in every TagSetIndex table
+	VariantTables []int            // count of tag sets collections in every VariantsIndex table
+	Ambiguity     map[int]int      // count of words by count of their tag sets
+	TagSetUsage   map[TagSetID]int // count of words having tag set
+	Sections      SectionSizes     // compiled index sections sizes
+}
+
+// Stats collects index contents statistics walking all index items once.
+// Returns error if any item refers missed tag sets collection.
+func (index *ReadOnlyIndex) Stats() (stats Stats, err error) {
+	stats = Stats{
+		Words:         index.WordsCount(),
+		Forms:         0,
+		Nodes:         index.NodesCount(),
+		Lemmas:        index.LemmasCount(),
+		LemmaForms:    0,
+		Tags:          index.tags.Len(),
+		TagSetTables:  make([]int, len(index.tagSets)),
+		VariantTables: make([]int, len(index.collectionIdx)),
+		Ambiguity:     make(map[int]int),
+		TagSetUsage:   make(map[TagSetID]int),
+		Sections:      SectionSizes{Header: 0, Tags: 0, TagSets: 0, Variants: 0, Items: 0, Lemmas: 0, Total: 0},
+	}
+
+	for tableIdx, table := range index.tagSets {
+		stats.TagSetTables[tableIdx] = table.Len()
+	}
+
+	for tableIdx, table := range index.collectionIdx {
+		stats.VariantTables[tableIdx] = len(table)
+	}
+
+	for _, lemma := range index.lemmas {
+		stats.LemmaForms += len(lemma.Forms)
+	}
+
+	for itemIdx := 1; itemIdx < len(index.items); itemIdx++ {
+		item := index.items[itemIdx]
+		if item.Variants == 0 {
+			continue
+		}
+
+		collection, found := index.collectionIdx.Lookup(item.Variants)
+		if !found {
+			return stats, fmt.Errorf("%w: stats: item %d: no variants %#08x", Error, item.ID, item.Variants)
+		}
+
+		stats.Forms += collection.Len()
+		stats.Ambiguity[collection.Len()]++
+
+		for _, tagSetID := range collection {
+			stats.TagSetUsage[tagSetID]++
+		}
+	}
+
+	if stats.Sections, err = index.SectionSizes(); err != nil {
+		return stats, err
+	}
+
+	return stats, nil
+}
+
+// SectionSizes returns sizes of index binary sections as BinaryWriteTo writes them without compression.
+func (index *ReadOnlyIndex) SectionSizes() (sizes SectionSizes, err error) {
+	writer := binutils.NewBinaryWriter(io.Discard)
+	sections := []struct {
+		size  *int
+		write func(writer *binutils.BinaryWriter) error
+	}{
+		{&sizes.Header, writeFormatHeader},
+		{&sizes.Tags, index.writeTagsDefinitions},
+		{&sizes.TagSets, index.writeTagSetsDefinitions},
+		{&sizes.Variants, index.writeCollectionsDefinitions},
+		{&sizes.Items, index.writeItemsDefinitions},
+		{&sizes.Lemmas, index.writeLemmasDefinitions},
+	}
+
+	for _, section := range sections {
+		writer.ResetBytesWritten()
+
+		if err = section.write(writer); err != nil {
+			return sizes, err
+		}
+
+		*section.size = writer.BytesWritten()
+		sizes.Total += *section.size
+	}
+
+	return sizes, nil
+}
@@ -0,0 +1,58 @@
+package index_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+)
+
+func TestReadOnlyIndex_Stats(t *testing.T) {
+	compiled := mustBuild(t, newLemmasBuilder(t, lemmasRevision1))
+
+	stats, err := compiled.Stats()
+	require.NoError(t, err)
+	require.Equal(t, 4, stats.Words)
+	require.Equal(t, 5, stats.Forms)
+	require.Equal(t, compiled.NodesCount(), stats.Nodes)
+	require.Equal(t, 3, stats.Lemmas)
+	require.Equal(t, 5, stats.LemmaForms)
+	require.Equal(t, compiled.Tags().Len(), stats.Tags)
+	require.Equal(t, map[int]int{1: 3, 2: 1}, stats.Ambiguity) // печь is both noun and verb
+
+	tagSets := 0
+	for _, count := range stats.TagSetTables {
+		tagSets += count
+	}
+
+	require.Equal(t, compiled.TagSetIndex().Size(), tagSets)
+	require.Len(t, stats.TagSetUsage, 4) // NOUN sing, NOUN plur, VERB, VERB sing
+
+	for tagSetID, usage := range stats.TagSetUsage {
+		tagSet, found := compiled.TagSetIndex().Get(tagSetID)
+		require.True(t, found)
+
+		if usage == 2 {
+			require.Len(t, tagSet, 2, "NOUN sing used by кот and печь")
+		}
+	}
+
+	written := new(bytes.Buffer)
+	require.NoError(t, compiled.BinaryWriteTo(binutils.NewBinaryWriter(written)))
+	require.Equal(t, written.Len(), stats.Sections.Total)
+	require.Equal(t, stats.Sections.Total, stats.Sections.Header+stats.Sections.Tags+stats.Sections.TagSets+
+		stats.Sections.Variants+stats.Sections.Items+stats.Sections.Lemmas)
+	require.Positive(t, stats.Sections.Items)
+	require.Positive(t, stats.Sections.Lemmas)
+
+	empty, err := index.NewBuilder().Build()
+	require.NoError(t, err)
+
+	stats, err = empty.Stats()
+	require.NoError(t, err)
+	require.Zero(t, stats.Words)
+	require.Empty(t, stats.Ambiguity)
+}
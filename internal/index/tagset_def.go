@@ -10,11 +10,12 @@ import (
 	"github.com/amarin/binutils"
 
 	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/storage"
 )
 
 const prefixTagSet = "TS"
 
-// TagSet stores unique sorted ID8's ids sets.
+// TagSet stores unique sorted TagID's sets.
 // Requires external management to provide item-to-ID8 and vise-versa transitions.
 type TagSet []dag.TagID
 
@@ -32,20 +33,27 @@ func (tagSet TagSet) String() string {
 // Returns error if happens or nil.
 // Implements binutils.BinaryReaderFrom.
 func (tagSet *TagSet) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	return tagSet.binaryReadFrom(reader, FormatCurrent)
+}
+
+// binaryReadFrom reads TagSet data written in specified format.
+func (tagSet *TagSet) binaryReadFrom(reader *binutils.BinaryReader, format FormatVersion) (err error) {
 	var (
-		tagSetLen    uint8
-		currentTagID uint8
+		tagSetLen    int
+		currentTagID int
 	)
 
-	if tagSetLen, err = reader.ReadUint8(); err != nil {
+	if tagSetLen, err = readCount(reader, format, dag.MaxTagsCount); err != nil {
 		return fmt.Errorf("%w: read: tagset: %v", Error, err)
 	}
 
 	*tagSet = make(TagSet, tagSetLen)
-	for idx := 0; idx < int(tagSetLen); idx++ {
-		if currentTagID, err = reader.ReadUint8(); err != nil {
+
+	for idx := 0; idx < tagSetLen; idx++ {
+		if currentTagID, err = readCount(reader, format, dag.MaxTagsCount-1); err != nil {
 			return fmt.Errorf("%w: read: tagset: %v", Error, err)
 		}
+
 		(*tagSet)[idx] = dag.TagID(currentTagID)
 	}
 
@@ -56,12 +64,12 @@ func (tagSet *TagSet) BinaryReadFrom(reader *binutils.BinaryReader) (err error)
 // Returns error if happens or nil.
 // Implements binutils.BinaryWriterTo.
 func (tagSet TagSet) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteUint8(uint8(len(tagSet))); err != nil {
+	if err = storage.WriteUvarint(writer, uint64(len(tagSet))); err != nil {
 		return fmt.Errorf("%w: %v", Error, err)
 	}
 
 	for _, tagID := range tagSet {
-		if err = writer.WriteUint8(uint8(tagID)); err != nil {
+		if err = storage.WriteUvarint(writer, uint64(tagID)); err != nil {
 			return fmt.Errorf("%w: %v", Error, err)
 		}
 	}
@@ -119,28 +127,11 @@ func (tagSet TagSet) WriteTo(w io.Writer) (n int64, err error) {
 // Returns taken bytes count and error if occurs.
 // Implements io.ReaderFrom.
 func (tagSet *TagSet) ReadFrom(r io.Reader) (totalBytesTaken int64, err error) {
-	var (
-		bytesTaken  int
-		expectedLen uint8
-		nextUint8   uint8
-	)
-
 	reader := binutils.NewBinaryReader(r)
-	if expectedLen, err = reader.ReadUint8(); err != nil {
-		return int64(bytesTaken), fmt.Errorf("%v: readFrom: len: %w", Error, err)
-	}
-
-	bytesTaken += binutils.Uint8size
-	*tagSet = make(TagSet, expectedLen)
-
-	for i := 0; uint8(i) < expectedLen; i++ {
-		if nextUint8, err = reader.ReadUint8(); err != nil {
-			return int64(bytesTaken), fmt.Errorf("%v: readFrom: data[%v]: %w", Error, i, err)
-		}
 
-		bytesTaken += binutils.Uint8size
-		(*tagSet)[i] = dag.TagID(nextUint8)
+	if err = tagSet.BinaryReadFrom(reader); err != nil {
+		return int64(reader.BytesTaken()), fmt.Errorf("%v: readFrom: %w", Error, err)
 	}
 
-	return int64(bytesTaken), err
+	return int64(reader.BytesTaken()), nil
 }
@@ -36,6 +36,19 @@ func (tagSetIndex TagSetIndex) Size() (res int) {
 	return res
 }
 
+// Clone makes a deep copy of TagSetIndex.
+func (tagSetIndex TagSetIndex) Clone() TagSetIndex {
+	res := make(TagSetIndex, len(tagSetIndex))
+	for tableIdx, table := range tagSetIndex {
+		res[tableIdx] = make(TagSetTable, len(table))
+		for tagSetIdx, tagSet := range table {
+			res[tableIdx][tagSetIdx] = append(make(TagSet, 0, len(tagSet)), tagSet...)
+		}
+	}
+
+	return res
+}
+
 // BinaryWriteTo writes TagSetIndex data using specified binutils.BinaryWriter instance.
 // Returns error if happens or nil.
 // Implements binutils.BinaryWriterTo.
@@ -61,6 +74,11 @@ func (tagSetIndex TagSetIndex) BinaryWriteTo(writer *binutils.BinaryWriter) (err
 // Returns error if happens or nil.
 // Implements binutils.BinaryReaderFrom.
 func (tagSetIndex *TagSetIndex) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	return tagSetIndex.binaryReadFrom(reader, FormatCurrent)
+}
+
+// binaryReadFrom reads TagSetIndex data written in specified format.
+func (tagSetIndex *TagSetIndex) binaryReadFrom(reader *binutils.BinaryReader, format FormatVersion) (err error) {
 	if reader == nil {
 		return fmt.Errorf("%w: TagSetIndex", ErrNilReader)
 	}
@@ -73,7 +91,7 @@ func (tagSetIndex *TagSetIndex) BinaryReadFrom(reader *binutils.BinaryReader) (e
 
 	*tagSetIndex = make(TagSetIndex, tagSetIndexLen)
 	for idx := 0; idx < int(tagSetIndexLen); idx++ {
-		if err = (*tagSetIndex)[idx].BinaryReadFrom(reader); err != nil {
+		if err = (*tagSetIndex)[idx].binaryReadFrom(reader, format); err != nil {
 			return fmt.Errorf("%w: read: tagset: %v", Error, err)
 		}
 	}
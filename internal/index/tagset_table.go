@@ -30,6 +30,11 @@ func (tagSetTable TagSetTable) String() string {
 // Returns error if happens or nil.
 // Implements binutils.BinaryReaderFrom.
 func (tagSetTable *TagSetTable) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	return tagSetTable.binaryReadFrom(reader, FormatCurrent)
+}
+
+// binaryReadFrom reads TagSetTable data written in specified format.
+func (tagSetTable *TagSetTable) binaryReadFrom(reader *binutils.BinaryReader, format FormatVersion) (err error) {
 	var tagSetLen uint16
 
 	if reader == nil {
@@ -42,7 +47,7 @@ func (tagSetTable *TagSetTable) BinaryReadFrom(reader *binutils.BinaryReader) (e
 
 	*tagSetTable = make(TagSetTable, tagSetLen)
 	for idx := 0; idx < int(tagSetLen); idx++ {
-		if err = (*tagSetTable)[idx].BinaryReadFrom(reader); err != nil {
+		if err = (*tagSetTable)[idx].binaryReadFrom(reader, format); err != nil {
 			return fmt.Errorf("%w: read: tagset: %v", Error, err)
 		}
 	}
@@ -0,0 +1,182 @@
+package index
+
+import (
+	"fmt"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// WordForm represents lemma word form with its tags.
+type WordForm struct {
+	Word string        // word form text
+	Tags []dag.TagName // word form tag names
+}
+
+// AddLemma adds lemma word forms into index and registers lemma with its ID and revision.
+// First form is taken as lemma normal form unless lemma already has one.
+// All tags must be registered using TagID before.
+// If lemma already known its revision is replaced and specified forms are added to known lemma forms.
+func (index *IndexBuilder) AddLemma(id LemmaID, revision uint32, forms ...WordForm) error {
+	return index.addLemma(id, revision, true, forms)
+}
+
+// AddLemmaForms adds lemma word forms into index like AddLemma but never takes them as lemma normal form.
+// Used to add lemma forms split among several indexes, while normal form is added by AddLemma into one of them.
+func (index *IndexBuilder) AddLemmaForms(id LemmaID, revision uint32, forms ...WordForm) error {
+	return index.addLemma(id, revision, false, forms)
+}
+
+// addLemma adds lemma word forms into index taking first form as lemma normal form if withNormal set.
+func (index *IndexBuilder) addLemma(id LemmaID, revision uint32, withNormal bool, forms []WordForm) error {
+	lemma, known := index.lemmas[id]
+	if !known {
+		lemma = &Lemma{ID: id, Revision: revision, Normal: LemmaForm{}, Forms: make([]LemmaForm, 0, len(forms))}
+		index.lemmas[id] = lemma
+	}
+
+	lemma.Revision = revision
+
+	for formIdx, form := range forms {
+		if len(form.Word) == 0 {
+			return fmt.Errorf("%w: add lemma %d: empty word", Error, id)
+		}
+
+		nodeID := dag.ID(0)
+		for _, letter := range form.Word {
+			nodeID = index.childID(nodeID, letter)
+		}
+
+		tagSetID, err := index.tagSetID(form.Tags)
+		if err != nil {
+			return fmt.Errorf("%w: add lemma %d: form `%s`: %v", Error, id, form.Word, err)
+		}
+
+		index.addTagSetID(index.items.Get(nodeID), tagSetID)
+
+		lemmaForm := LemmaForm{Node: nodeID, TagSet: tagSetID}
+		if withNormal && formIdx == 0 && lemma.Normal.Node == 0 {
+			lemma.Normal = lemmaForm
+		}
+
+		if lemma.hasForm(lemmaForm) {
+			continue
+		}
+
+		lemma.Forms = append(lemma.Forms, lemmaForm)
+		if index.formRefs != nil {
+			index.formRefs[lemmaForm]++
+		}
+	}
+
+	return nil
+}
+
+// lemmaFormRefs returns count of lemmas referring each form. Counters are built on first use.
+func (index *IndexBuilder) lemmaFormRefs() map[LemmaForm]int {
+	if index.formRefs != nil {
+		return index.formRefs
+	}
+
+	index.formRefs = make(map[LemmaForm]int)
+	for _, lemma := range index.lemmas {
+		for _, form := range lemma.Forms {
+			index.formRefs[form]++
+		}
+	}
+
+	return index.formRefs
+}
+
+// removeLemma removes lemma and its forms not referred by other lemmas from index.
+// Returns false if no such lemma known.
+func (index *IndexBuilder) removeLemma(id LemmaID) bool {
+	lemma, known := index.lemmas[id]
+	if !known {
+		return false
+	}
+
+	refs := index.lemmaFormRefs()
+	for _, form := range lemma.Forms {
+		refs[form]--
+		if refs[form] > 0 {
+			continue
+		}
+
+		delete(refs, form)
+
+		if item := index.items.Get(form.Node); item != nil {
+			index.removeTagSetID(item, form.TagSet)
+		}
+	}
+
+	delete(index.lemmas, id)
+
+	return true
+}
+
+// UpdateStats counts lemmas processed by LemmaUpdate.
+type UpdateStats struct {
+	Added     int // lemmas not known before update
+	Changed   int // lemmas having another revision
+	Unchanged int // lemmas having the same revision
+	Removed   int // lemmas missed in update
+}
+
+// String returns string representation of update stats. Implements fmt.Stringer.
+func (stats UpdateStats) String() string {
+	return fmt.Sprintf(
+		"%d added, %d changed, %d unchanged, %d removed",
+		stats.Added, stats.Changed, stats.Unchanged, stats.Removed)
+}
+
+// LemmaUpdate applies lemmas of a newer dictionary revision to index.
+// Every lemma of new dictionary revision must be passed to Lemma, then Finish removes lemmas not passed.
+// Only lemmas having changed revision touch index data, so update is much faster than a full rebuild.
+type LemmaUpdate struct {
+	index *IndexBuilder
+	seen  map[LemmaID]struct{}
+	stats UpdateStats
+}
+
+// BeginUpdate starts applying a newer dictionary revision to index.
+func (index *IndexBuilder) BeginUpdate() *LemmaUpdate {
+	return &LemmaUpdate{
+		index: index,
+		seen:  make(map[LemmaID]struct{}, len(index.lemmas)),
+		stats: UpdateStats{Added: 0, Changed: 0, Unchanged: 0, Removed: 0},
+	}
+}
+
+// Lemma applies dictionary lemma. Lemma known with the same revision is skipped,
+// lemma known with another revision is replaced by specified forms, unknown lemma is added.
+// All tags must be registered using TagID before.
+func (update *LemmaUpdate) Lemma(id LemmaID, revision uint32, forms ...WordForm) error {
+	update.seen[id] = struct{}{}
+
+	if known, found := update.index.lemmas[id]; found {
+		if known.Revision == revision {
+			update.stats.Unchanged++
+
+			return nil
+		}
+
+		update.index.removeLemma(id)
+		update.stats.Changed++
+	} else {
+		update.stats.Added++
+	}
+
+	return update.index.AddLemma(id, revision, forms...)
+}
+
+// Finish removes lemmas not passed to Lemma since update begins and returns update stats.
+func (update *LemmaUpdate) Finish() UpdateStats {
+	for _, id := range update.index.lemmas.IDs() {
+		if _, seen := update.seen[id]; !seen {
+			update.index.removeLemma(id)
+			update.stats.Removed++
+		}
+	}
+
+	return update.stats
+}
@@ -0,0 +1,191 @@
+package index_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+type testLemma struct {
+	id       index.LemmaID
+	revision uint32
+	forms    []index.WordForm
+}
+
+var (
+	lemmasRevision1 = []testLemma{
+		{1, 1, []index.WordForm{{"кот", []dag.TagName{"NOUN", "sing"}}, {"коты", []dag.TagName{"NOUN", "plur"}}}},
+		{2, 1, []index.WordForm{{"печь", []dag.TagName{"NOUN", "sing"}}}},
+		{3, 1, []index.WordForm{{"печь", []dag.TagName{"VERB"}}, {"пёк", []dag.TagName{"VERB", "sing"}}}},
+	}
+	lemmasRevision2 = []testLemma{
+		{1, 1, []index.WordForm{{"кот", []dag.TagName{"NOUN", "sing"}}, {"коты", []dag.TagName{"NOUN", "plur"}}}},
+		{2, 2, []index.WordForm{{"печь", []dag.TagName{"NOUN", "sing"}}, {"печи", []dag.TagName{"NOUN", "plur"}}}},
+		{4, 1, []index.WordForm{{"лес", []dag.TagName{"NOUN", "sing"}}}},
+	}
+)
+
+func newLemmasBuilder(t *testing.T, lemmas []testLemma) *index.IndexBuilder {
+	t.Helper()
+
+	builder := newTagsBuilder()
+	for _, lemma := range lemmas {
+		require.NoError(t, builder.AddLemma(lemma.id, lemma.revision, lemma.forms...))
+	}
+
+	return builder
+}
+
+type wordFetcher interface {
+	FetchString(word string) (dag.Node, error)
+}
+
+func requireWordTagSets(t *testing.T, idx wordFetcher, word string, expected ...string) {
+	t.Helper()
+
+	node, err := idx.FetchString(word)
+	require.NoError(t, err, word)
+	require.ElementsMatch(t, expected, tagSetsStrings(node), word)
+}
+
+func TestIndexBuilder_BeginUpdate(t *testing.T) {
+	compiled := mustBuild(t, newLemmasBuilder(t, lemmasRevision1))
+
+	// compiled index keeps lemmas when written and loaded back
+	buffer := new(bytes.Buffer)
+	require.NoError(t, compiled.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	loaded := new(index.ReadOnlyIndex)
+	require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+	require.Equal(t, 3, loaded.LemmasCount())
+
+	lemma, found := loaded.Lemma(3)
+	require.True(t, found)
+	require.EqualValues(t, 1, lemma.Revision)
+	require.Len(t, lemma.Forms, 2)
+
+	builder := loaded.Thaw()
+	update := builder.BeginUpdate()
+
+	for _, lemma := range lemmasRevision2 {
+		require.NoError(t, update.Lemma(lemma.id, lemma.revision, lemma.forms...))
+	}
+
+	require.Equal(t, index.UpdateStats{Added: 1, Changed: 1, Unchanged: 1, Removed: 1}, update.Finish())
+
+	updated := mustBuild(t, builder)
+	expected := mustBuild(t, newLemmasBuilder(t, lemmasRevision2))
+	require.Equal(t, expected.WordsCount(), updated.WordsCount())
+	require.Equal(t, expected.LemmasCount(), updated.LemmasCount())
+
+	for _, idx := range []wordFetcher{expected, updated} {
+		requireWordTagSets(t, idx, "кот", "NOUN,sing")
+		requireWordTagSets(t, idx, "печь", "NOUN,sing")
+		requireWordTagSets(t, idx, "печи", "NOUN,plur")
+		requireWordTagSets(t, idx, "лес", "NOUN,sing")
+	}
+
+	_, found = updated.Lemma(3)
+	require.False(t, found)
+
+	lemma, found = updated.Lemma(2)
+	require.True(t, found)
+	require.EqualValues(t, 2, lemma.Revision)
+
+	// loaded index is not changed by update
+	requireWordTagSets(t, loaded, "печь", "NOUN,sing", "VERB")
+}
+
+func TestIndexBuilder_BeginUpdate_SharedForms(t *testing.T) {
+	builder := newLemmasBuilder(t, []testLemma{
+		{1, 1, []index.WordForm{{"печь", []dag.TagName{"VERB"}}}},
+		{2, 1, []index.WordForm{{"печь", []dag.TagName{"VERB"}}, {"пёк", []dag.TagName{"VERB", "sing"}}}},
+	})
+
+	update := builder.BeginUpdate()
+	require.NoError(t, update.Lemma(1, 1, index.WordForm{Word: "печь", Tags: []dag.TagName{"VERB"}}))
+	require.Equal(t, index.UpdateStats{Added: 0, Changed: 0, Unchanged: 1, Removed: 1}, update.Finish())
+
+	// form referred by remaining lemma is kept, form of removed lemma only is dropped
+	requireWordTagSets(t, builder, "печь", "VERB")
+	requireWordTagSets(t, builder, "пёк")
+	require.Equal(t, 1, builder.WordsCount())
+}
+
+func TestIndexBuilder_Merge_Lemmas(t *testing.T) {
+	first := newLemmasBuilder(t, lemmasRevision1[:2])
+	second := newLemmasBuilder(t, lemmasRevision1[2:])
+	second.TagID("nomn", "CAse") // shift tag IDs of second builder
+	require.NoError(t, second.AddLemma(2, 1, index.WordForm{Word: "печи", Tags: []dag.TagName{"NOUN", "nomn"}}))
+	require.NoError(t, first.Merge(second))
+
+	merged := mustBuild(t, first)
+	require.Equal(t, 3, merged.LemmasCount())
+
+	lemma, found := merged.Lemma(2)
+	require.True(t, found)
+	require.Len(t, lemma.Forms, 2)
+
+	lemma, found = merged.Lemma(3)
+	require.True(t, found)
+	require.Len(t, lemma.Forms, 2)
+
+	for _, form := range lemma.Forms {
+		node, err := merged.Get(form.Node)
+		require.NoError(t, err)
+		require.Contains(t, []string{"печь", "пёк"}, node.Word())
+	}
+}
+
+func requireNormalForm(t *testing.T, idx *index.ReadOnlyIndex, id index.LemmaID, expected string) {
+	t.Helper()
+
+	lemma, found := idx.Lemma(id)
+	require.True(t, found)
+
+	node, err := idx.Get(lemma.Normal.Node)
+	require.NoError(t, err)
+	require.Equal(t, expected, node.Word())
+	require.Contains(t, lemma.Forms, lemma.Normal)
+}
+
+func TestIndexBuilder_AddLemma_NormalForm(t *testing.T) {
+	hedgehog := []index.WordForm{
+		{Word: "ёж", Tags: []dag.TagName{"NOUN", "sing"}},
+		{Word: "ежа", Tags: []dag.TagName{"NOUN", "sing"}},
+		{Word: "ежи", Tags: []dag.TagName{"NOUN", "plur"}},
+	}
+
+	// normal form is kept while forms are sorted into index order
+	compiled := mustBuild(t, newLemmasBuilder(t, []testLemma{{1, 1, hedgehog}}))
+	requireNormalForm(t, compiled, 1, "ёж")
+
+	// normal form is written and loaded back
+	buffer := new(bytes.Buffer)
+	require.NoError(t, compiled.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	loaded := new(index.ReadOnlyIndex)
+	require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+	requireNormalForm(t, loaded, 1, "ёж")
+
+	// lemma forms split among builders get normal form of builder added it by AddLemma
+	first, second := newTagsBuilder(), newTagsBuilder()
+	require.NoError(t, first.AddLemmaForms(1, 1, hedgehog[1:]...))
+	require.NoError(t, second.AddLemma(1, 1, hedgehog[:1]...))
+	require.NoError(t, first.Merge(second))
+	requireNormalForm(t, mustBuild(t, first), 1, "ёж")
+
+	// removed normal form is forgotten
+	builder := loaded.Thaw()
+	require.NoError(t, builder.RemoveTagSet("ёж", "NOUN", "sing"))
+	removed := mustBuild(t, builder)
+	lemma, found := removed.Lemma(1)
+	require.True(t, found)
+	require.Zero(t, lemma.Normal.Node)
+	require.Len(t, lemma.Forms, 2)
+}
@@ -2,6 +2,7 @@ package index
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,7 +28,7 @@ func (t TagSetIDCollection) GoString() string {
 // Returns error if happens or nil.
 // Implements binutils.BinaryWriterTo.
 func (t TagSetIDCollection) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteUint8(uint8(len(t))); err != nil {
+	if err = storage.WriteUvarint(writer, uint64(len(t))); err != nil {
 		return fmt.Errorf("%w: %v", Error, err)
 	}
 
@@ -44,20 +45,27 @@ func (t TagSetIDCollection) BinaryWriteTo(writer *binutils.BinaryWriter) (err er
 // Returns error if happens or nil.
 // Implements binutils.BinaryReaderFrom.
 func (t *TagSetIDCollection) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	return t.binaryReadFrom(reader, FormatCurrent)
+}
+
+// binaryReadFrom reads TagSetIDCollection data written in specified format.
+func (t *TagSetIDCollection) binaryReadFrom(reader *binutils.BinaryReader, format FormatVersion) (err error) {
 	var (
-		tagSetIndexLen uint8
+		tagSetIndexLen int
 		currentUint32  uint32
 	)
 
-	if tagSetIndexLen, err = reader.ReadUint8(); err != nil {
+	if tagSetIndexLen, err = readCount(reader, format, math.MaxUint16); err != nil {
 		return fmt.Errorf("%w: read: tagset: %v", Error, err)
 	}
 
 	*t = make(TagSetIDCollection, tagSetIndexLen)
-	for idx := 0; idx < int(tagSetIndexLen); idx++ {
+
+	for idx := 0; idx < tagSetIndexLen; idx++ {
 		if currentUint32, err = reader.ReadUint32(); err != nil {
 			return fmt.Errorf("%w: read: tagset: %v", Error, err)
 		}
+
 		(*t)[idx] = TagSetID(currentUint32)
 	}
 
@@ -123,3 +131,21 @@ func (t TagSetIDCollection) Add(additionalTagSetID TagSetID) TagSetIDCollection
 
 	return res
 }
+
+// Remove makes a new TagSetIDCollection having all TagSetID's from original except specified one.
+// If original TagSetIDCollection does not contain specified element,
+// resulting TagSetIDCollection will be the same as original one.
+func (t TagSetIDCollection) Remove(removeTagSetID TagSetID) TagSetIDCollection {
+	if !t.Has(removeTagSetID) {
+		return t
+	}
+
+	res := make(TagSetIDCollection, 0, len(t)-1)
+	for _, tsID := range t {
+		if tsID != removeTagSetID {
+			res = append(res, tsID)
+		}
+	}
+
+	return res
+}
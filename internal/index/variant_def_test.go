@@ -148,3 +148,23 @@ func TestTagSetIDCollection_Add(t *testing.T) {
 		})
 	}
 }
+
+func TestTagSetIDCollection_Remove(t *testing.T) {
+	tests := []struct {
+		name           string
+		t              index.TagSetIDCollection
+		removeTagSetID index.TagSetID
+		want           index.TagSetIDCollection
+	}{
+		{"remove_from_empty", index.TagSetIDCollection{}, 1, index.TagSetIDCollection{}},
+		{"remove_unknown", index.TagSetIDCollection{1, 3, 7}, 2, index.TagSetIDCollection{1, 3, 7}},
+		{"remove_existed", index.TagSetIDCollection{1, 3, 7}, 3, index.TagSetIDCollection{1, 7}},
+		{"remove_last", index.TagSetIDCollection{3}, 3, index.TagSetIDCollection{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tt.t.Remove(tt.removeTagSetID)
+			require.True(t, tt.want.EqualTo(res))
+		})
+	}
+}
@@ -2,19 +2,35 @@ package index
 
 import (
 	"fmt"
+	"math"
 	"sort"
 
 	"github.com/amarin/binutils"
+
+	"github.com/amarin/gomorphy/pkg/storage"
 )
 
 // VariantsIndex stores index of all possible TagSetIDCollection.
 type VariantsIndex []VariantsTable
 
+// Clone makes a deep copy of VariantsIndex.
+func (tagSetIndex VariantsIndex) Clone() VariantsIndex {
+	res := make(VariantsIndex, len(tagSetIndex))
+	for tableIdx, table := range tagSetIndex {
+		res[tableIdx] = make(VariantsTable, len(table))
+		for collectionIdx, collection := range table {
+			res[tableIdx][collectionIdx] = append(make(TagSetIDCollection, 0, len(collection)), collection...)
+		}
+	}
+
+	return res
+}
+
 // BinaryWriteTo writes CollectionTable data using specified binutils.BinaryWriter instance.
 // Returns error if happens or nil.
 // Implements binutils.BinaryWriterTo.
 func (tagSetIndex VariantsIndex) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteUint8(uint8(len(tagSetIndex))); err != nil {
+	if err = storage.WriteUvarint(writer, uint64(len(tagSetIndex))); err != nil {
 		return fmt.Errorf("%w: %v", Error, err)
 	}
 
@@ -31,15 +47,21 @@ func (tagSetIndex VariantsIndex) BinaryWriteTo(writer *binutils.BinaryWriter) (e
 // Returns error if happens or nil.
 // Implements binutils.BinaryReaderFrom.
 func (tagSetIndex *VariantsIndex) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
-	var tagSetIndexLen uint8
+	return tagSetIndex.binaryReadFrom(reader, FormatCurrent)
+}
+
+// binaryReadFrom reads VariantsIndex data written in specified format.
+func (tagSetIndex *VariantsIndex) binaryReadFrom(reader *binutils.BinaryReader, format FormatVersion) (err error) {
+	var tagSetIndexLen int
 
-	if tagSetIndexLen, err = reader.ReadUint8(); err != nil {
+	if tagSetIndexLen, err = readCount(reader, format, math.MaxUint16); err != nil {
 		return fmt.Errorf("%w: read: tagset: %v", Error, err)
 	}
 
 	*tagSetIndex = make(VariantsIndex, tagSetIndexLen)
-	for idx := 0; idx < int(tagSetIndexLen); idx++ {
-		if err = (*tagSetIndex)[idx].BinaryReadFrom(reader); err != nil {
+
+	for idx := 0; idx < tagSetIndexLen; idx++ {
+		if err = (*tagSetIndex)[idx].binaryReadFrom(reader, format); err != nil {
 			return fmt.Errorf("%w: read: tagset: %v", Error, err)
 		}
 	}
@@ -105,6 +127,22 @@ func (tagSetIndex VariantsIndex) Get(storageIdx VariantID) TagSetIDCollection {
 	return tagSetIndex[tableNumber.Add(-1)].Get(storageIdx.CollectionTableID())
 }
 
+// Lookup returns set by index if present or found indicator will be false.
+// Unlike Get it never panics, so it is suitable to check untrusted VariantID values.
+func (tagSetIndex VariantsIndex) Lookup(storageIdx VariantID) (collection TagSetIDCollection, found bool) {
+	tableNumber := storageIdx.TableNum()
+	if tableNumber < 1 || int(tableNumber) > len(tagSetIndex) {
+		return nil, false
+	}
+
+	table := tagSetIndex[tableNumber.Add(-1)]
+	if int(storageIdx.CollectionTableID()) >= len(table) {
+		return nil, false
+	}
+
+	return table[storageIdx.CollectionTableID()], true
+}
+
 // Table returns internal CollectionTable by its index.
 func (tagSetIndex VariantsIndex) Table(tableID CollectionTableNumber) VariantsTable {
 	return tagSetIndex[tableID.Add(-1)]
@@ -31,6 +31,11 @@ func (variantsTable VariantsTable) BinaryWriteTo(writer *binutils.BinaryWriter)
 // Returns error if happens or nil.
 // Implements binutils.BinaryReaderFrom.
 func (variantsTable *VariantsTable) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
+	return variantsTable.binaryReadFrom(reader, FormatCurrent)
+}
+
+// binaryReadFrom reads VariantsTable data written in specified format.
+func (variantsTable *VariantsTable) binaryReadFrom(reader *binutils.BinaryReader, format FormatVersion) (err error) {
 	var tagSetIndexLen uint32
 
 	if tagSetIndexLen, err = reader.ReadUint32(); err != nil {
@@ -39,7 +44,7 @@ func (variantsTable *VariantsTable) BinaryReadFrom(reader *binutils.BinaryReader
 
 	*variantsTable = make(VariantsTable, tagSetIndexLen)
 	for idx := 0; idx < int(tagSetIndexLen); idx++ {
-		if err = (*variantsTable)[idx].BinaryReadFrom(reader); err != nil {
+		if err = (*variantsTable)[idx].binaryReadFrom(reader, format); err != nil {
 			return fmt.Errorf("%w: read: tagset: %v", Error, err)
 		}
 	}
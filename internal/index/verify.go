@@ -0,0 +1,211 @@
+package index
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// maxVerifyProblems limits count of problems collected by Verify, so broken index does not flood memory.
+const maxVerifyProblems = 100
+
+// ErrInconsistent indicates index data refers missed or mismatched data.
+var ErrInconsistent = fmt.Errorf("%w: inconsistent", Error)
+
+// VerifyError lists index consistency problems found by Verify. Wraps ErrInconsistent.
+type VerifyError struct {
+	Problems  []string // problems descriptions, at most maxVerifyProblems first ones
+	Truncated bool     // true if more problems found than listed
+}
+
+// Error returns problems list as a single string. Implements error.
+func (verifyError *VerifyError) Error() string {
+	suffix := ""
+	if verifyError.Truncated {
+		suffix = "; ..."
+	}
+
+	return fmt.Sprintf("%v: %d problems: %s%s",
+		ErrInconsistent, len(verifyError.Problems), strings.Join(verifyError.Problems, "; "), suffix)
+}
+
+// Unwrap returns ErrInconsistent, so errors.Is could be used to check VerifyError.
+func (verifyError *VerifyError) Unwrap() error {
+	return ErrInconsistent
+}
+
+// verifier collects index consistency problems.
+type verifier struct {
+	index    *ReadOnlyIndex
+	problems *VerifyError
+}
+
+// addf registers formatted problem description.
+func (v *verifier) addf(format string, args ...interface{}) {
+	if len(v.problems.Problems) >= maxVerifyProblems {
+		v.problems.Truncated = true
+		return
+	}
+
+	v.problems.Problems = append(v.problems.Problems, fmt.Sprintf(format, args...))
+}
+
+// Verify checks every item is stored in its own slot and has existed parent,
+// children relations agree with items parents, every VariantID resolves in VariantsIndex,
+// every TagSetID resolves in TagSetIndex, every TagID resolves in tags index
+// and every lemma form refers existed word having form tag set.
+// Returns nil if index is consistent or VerifyError listing found problems.
+func (index *ReadOnlyIndex) Verify() error {
+	v := &verifier{index: index, problems: &VerifyError{Problems: nil, Truncated: false}}
+
+	v.verifyItems()
+	v.verifyChildren()
+	v.verifyCollections()
+	v.verifyTagSets()
+	v.verifyLemmas()
+
+	if len(v.problems.Problems) > 0 {
+		return v.problems
+	}
+
+	return nil
+}
+
+// Verify checks index consistency the same way as ReadOnlyIndex.Verify does.
+func (index *IndexBuilder) Verify() error {
+	index.mu.Lock()
+	defer index.mu.Unlock()
+
+	view := index.view()
+	view.children = index.children
+
+	return view.Verify()
+}
+
+// verifyItems checks items IDs, parents and variants.
+func (v *verifier) verifyItems() {
+	wordsCount := 0
+
+	for slot, item := range v.index.items {
+		if slot == 0 {
+			continue
+		}
+
+		if int(item.ID) != slot {
+			v.addf("item %d: stored in slot %d", item.ID, slot)
+		}
+
+		if int(item.Parent) >= len(v.index.items) || (item.Parent != 0 && v.index.items[item.Parent].ID != item.Parent) {
+			v.addf("item %d: no parent %d", slot, item.Parent)
+		}
+
+		if item.Variants == 0 {
+			continue
+		}
+
+		wordsCount++
+
+		if _, found := v.index.collectionIdx.Lookup(item.Variants); !found {
+			v.addf("item %d: no variants %#08x", slot, item.Variants)
+		}
+	}
+
+	if wordsCount != v.index.wordsCount {
+		v.addf("words count %d, but %d items have variants", v.index.wordsCount, wordsCount)
+	}
+}
+
+// verifyChildren checks children relations agree with items parents and letters.
+func (v *verifier) verifyChildren() {
+	if v.index.children == nil {
+		v.addf("no children relations")
+		return
+	}
+
+	relations := 0
+
+	for parent := range v.index.items {
+		for _, child := range v.index.children.Children(dag.ID(parent)) {
+			relations++
+
+			if int(child.ID) >= len(v.index.items) || child.ID == 0 {
+				v.addf("item %d: no child %d", parent, child.ID)
+				continue
+			}
+
+			item := v.index.items[child.ID]
+			if int(item.Parent) != parent || item.Letter != child.Letter {
+				v.addf("item %d: child %d `%c` has parent %d letter `%c`",
+					parent, child.ID, child.Letter, item.Parent, item.Letter)
+			}
+		}
+	}
+
+	for slot, item := range v.index.items {
+		if slot == 0 {
+			continue
+		}
+
+		if childID, found := v.index.children.Find(item.Parent, item.Letter); !found || int(childID) != slot {
+			v.addf("item %d: not a child of parent %d", slot, item.Parent)
+		}
+	}
+
+	if len(v.index.items) > 0 && relations != len(v.index.items)-1 {
+		v.addf("%d children relations for %d items", relations, len(v.index.items)-1)
+	}
+}
+
+// verifyCollections checks every collection TagSetID resolves in TagSetIndex.
+func (v *verifier) verifyCollections() {
+	for tableIdx, table := range v.index.collectionIdx {
+		tableNumber := CollectionTableNumber(tableIdx).Add(1)
+
+		for subID, collection := range table {
+			for _, tagSetID := range collection {
+				if _, found := v.index.tagSets.Get(tagSetID); !found {
+					v.addf("variants %#08x: no tag set %#08x", tableNumber.VariantID(VariantSubID(subID)), tagSetID)
+				}
+			}
+		}
+	}
+}
+
+// verifyTagSets checks every tag set TagID resolves in tags index.
+func (v *verifier) verifyTagSets() {
+	for tableIdx, table := range v.index.tagSets {
+		tableNumber := TagSetTableNumber(tableIdx)
+
+		for subID, tagSet := range table {
+			for _, tagID := range tagSet {
+				if _, found := v.index.tags.Get(tagID); !found {
+					v.addf("tag set %#08x: no tag %d", tableNumber.TagSetID(TagSetSubID(subID)), tagID)
+				}
+			}
+		}
+	}
+}
+
+// verifyLemmas checks every lemma form refers existed word having form tag set
+// and lemma normal form is one of lemma forms.
+func (v *verifier) verifyLemmas() {
+	for _, id := range v.index.lemmas.IDs() {
+		lemma := v.index.lemmas[id]
+		if lemma.Normal.Node != 0 && !lemma.hasForm(lemma.Normal) {
+			v.addf("lemma %d: normal form %d %#08x is not lemma form", id, lemma.Normal.Node, lemma.Normal.TagSet)
+		}
+
+		for _, form := range lemma.Forms {
+			if form.Node == 0 || int(form.Node) >= len(v.index.items) {
+				v.addf("lemma %d: no item %d", id, form.Node)
+				continue
+			}
+
+			collection, _ := v.index.collectionIdx.Lookup(v.index.items[form.Node].Variants)
+			if !collection.Has(form.TagSet) {
+				v.addf("lemma %d: item %d has no tag set %#08x", id, form.Node, form.TagSet)
+			}
+		}
+	}
+}
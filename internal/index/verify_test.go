@@ -0,0 +1,69 @@
+package index
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func newVerifyTestIndex(t *testing.T) *ReadOnlyIndex {
+	t.Helper()
+
+	builder := NewBuilder()
+	builder.TagID("NOUN", "POST")
+	builder.TagID("sing", "NMbr")
+	require.NoError(t, builder.AddLemma(1, 1,
+		WordForm{Word: "кот", Tags: []dag.TagName{"NOUN", "sing"}},
+		WordForm{Word: "коты", Tags: []dag.TagName{"NOUN"}}))
+	require.NoError(t, builder.Verify())
+
+	compiled, err := builder.Build()
+	require.NoError(t, err)
+	require.NoError(t, compiled.Verify())
+
+	return compiled
+}
+
+func TestReadOnlyIndex_Verify(t *testing.T) {
+	for _, fileName := range []string{"testdata/legacy.idx", "testdata/varint.idx", "testdata/tagnames.idx"} {
+		reader, err := binutils.OpenFile(fileName)
+		require.NoError(t, err)
+
+		loaded := new(ReadOnlyIndex)
+		require.NoError(t, loaded.BinaryReadFrom(reader))
+		require.NoError(t, reader.Close())
+		require.NoError(t, loaded.Verify(), fileName)
+	}
+
+	tests := []struct {
+		name    string
+		corrupt func(index *ReadOnlyIndex)
+		problem string
+	}{
+		{"item_slot", func(index *ReadOnlyIndex) { index.items[2].ID = 3 }, "item 3: stored in slot 2"},
+		{"item_parent", func(index *ReadOnlyIndex) { index.items[2].Parent = 100 }, "item 2: no parent 100"},
+		{"child_letter", func(index *ReadOnlyIndex) { index.items[2].Letter = 'x' }, "child 2 `о` has parent 1 letter `x`"},
+		{"variants", func(index *ReadOnlyIndex) { index.items[3].Variants = 0x00070000 }, "item 3: no variants 0x00070000"},
+		{"words_count", func(index *ReadOnlyIndex) { index.wordsCount = 5 }, "words count 5, but 2 items have variants"},
+		{"tag_set", func(index *ReadOnlyIndex) { index.collectionIdx[0][0][0] = 0x00030000 }, "no tag set 0x00030000"},
+		{"tag", func(index *ReadOnlyIndex) { index.tagSets[0][0][0] = 10 }, "tag set 0x00000000: no tag 10"},
+		{"lemma", func(index *ReadOnlyIndex) { index.lemmas[1].Forms[0].Node = 100 }, "lemma 1: no item 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiled := newVerifyTestIndex(t)
+			tt.corrupt(compiled)
+
+			err := compiled.Verify()
+			require.ErrorIs(t, err, ErrInconsistent)
+
+			var verifyError *VerifyError
+			require.True(t, errors.As(err, &verifyError))
+			require.Contains(t, err.Error(), tt.problem)
+		})
+	}
+}
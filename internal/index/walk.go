@@ -0,0 +1,189 @@
+package index
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// WordEntry provides indexed word with one of its tag sets.
+type WordEntry struct {
+	Word   string     // word text
+	TagSet dag.TagSet // word tag set
+	Lemmas []LemmaID  // lemmas having word form with tag set, nil if none
+}
+
+// LemmaEntry provides dictionary lemma with its forms words and tag sets.
+type LemmaEntry struct {
+	ID       LemmaID     // dictionary lemma ID
+	Revision uint32      // dictionary lemma revision
+	Forms    []WordEntry // lemma forms, normal form first if known, then others in index order
+}
+
+// formLemma links lemma form with lemma.
+type formLemma struct {
+	form  LemmaForm
+	lemma LemmaID
+}
+
+// formLemmas provides lemmas of forms sorted by forms then lemma IDs.
+type formLemmas []formLemma
+
+// lemmas returns IDs of lemmas having specified form or nil if none.
+func (links formLemmas) lemmas(form LemmaForm) (res []LemmaID) {
+	start := sort.Search(len(links), func(i int) bool { return !lessLemmaForm(links[i].form, form) })
+	for idx := start; idx < len(links) && links[idx].form == form; idx++ {
+		res = append(res, links[idx].lemma)
+	}
+
+	return res
+}
+
+// formLemmas returns lemmas of all lemma forms.
+func (index *ReadOnlyIndex) formLemmas() formLemmas {
+	count := 0
+	for _, lemma := range index.lemmas {
+		count += len(lemma.Forms)
+	}
+
+	links := make(formLemmas, 0, count)
+	for _, id := range index.lemmas.IDs() {
+		for _, form := range index.lemmas[id].Forms {
+			links = append(links, formLemma{form: form, lemma: id})
+		}
+	}
+
+	sort.SliceStable(links, func(i, j int) bool { return lessLemmaForm(links[i].form, links[j].form) })
+
+	return links
+}
+
+// tagSet returns tags of specified tag set.
+func (index *ReadOnlyIndex) tagSet(id TagSetID) (dag.TagSet, error) {
+	tagIDs, found := index.tagSets.Get(id)
+	if !found {
+		return nil, fmt.Errorf("%w: no tag set: %#08x", Error, id)
+	}
+
+	res := make(dag.TagSet, tagIDs.Len())
+	for idx, tagID := range tagIDs {
+		if res[idx], found = index.tags.Get(tagID); !found {
+			return nil, fmt.Errorf("%w: tag set %#08x: no tag: %d", Error, id, tagID)
+		}
+	}
+
+	return res, nil
+}
+
+// WalkWords calls fn for every tag set of every indexed word.
+// Words are visited in depth-first order taking children in letters order, so only current word
+// is kept in memory besides lemmas of forms. Walking stops at first fn error which is returned as is.
+func (index *ReadOnlyIndex) WalkWords(fn func(entry WordEntry) error) error {
+	type frame struct {
+		id    dag.ID // item to visit
+		depth int    // item letter position in word
+	}
+
+	links := index.formLemmas()
+	word := make([]rune, 0)
+	stack := make([]frame, 0)
+
+	pushChildren := func(parentID dag.ID, depth int) {
+		children := index.children.Children(parentID)
+		for idx := len(children) - 1; idx >= 0; idx-- {
+			stack = append(stack, frame{id: children[idx].ID, depth: depth})
+		}
+	}
+
+	pushChildren(0, 0)
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		item := index.getItem(current.id)
+		if item == nil {
+			return fmt.Errorf("%w: walk: no item: %d", Error, current.id)
+		}
+
+		word = append(word[:current.depth], item.Letter)
+
+		if item.Variants != 0 {
+			collection, found := index.collectionIdx.Lookup(item.Variants)
+			if !found {
+				return fmt.Errorf("%w: walk: `%s`: no collection: %#08x", Error, string(word), item.Variants)
+			}
+
+			for _, tagSetID := range collection {
+				tagSet, err := index.tagSet(tagSetID)
+				if err != nil {
+					return fmt.Errorf("%w: walk: `%s`: %v", Error, string(word), err)
+				}
+
+				lemmas := links.lemmas(LemmaForm{Node: current.id, TagSet: tagSetID})
+				if err = fn(WordEntry{Word: string(word), TagSet: tagSet, Lemmas: lemmas}); err != nil {
+					return err
+				}
+			}
+		}
+
+		pushChildren(current.id, current.depth+1)
+	}
+
+	return nil
+}
+
+// WalkLemmas calls fn for every known lemma in lemma IDs order.
+// Walking stops at first fn error which is returned as is.
+func (index *ReadOnlyIndex) WalkLemmas(fn func(lemma LemmaEntry) error) error {
+	for _, id := range index.lemmas.IDs() {
+		entry, err := index.LemmaEntry(id)
+		if err != nil {
+			return fmt.Errorf("%w: walk: %v", Error, err)
+		}
+
+		if err = fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// LemmaEntry returns lemma with its forms words and tag sets, lemma normal form first.
+// Returns error if lemma is unknown or refers unknown item or tag set.
+func (index *ReadOnlyIndex) LemmaEntry(id LemmaID) (LemmaEntry, error) {
+	lemma, found := index.lemmas[id]
+	if !found {
+		return LemmaEntry{}, fmt.Errorf("%w: no lemma: %d", Error, id)
+	}
+
+	forms := make([]LemmaForm, 0, len(lemma.Forms))
+	if lemma.Normal.Node != 0 {
+		forms = append(forms, lemma.Normal)
+	}
+
+	for _, form := range lemma.Forms {
+		if form != lemma.Normal {
+			forms = append(forms, form)
+		}
+	}
+
+	entry := LemmaEntry{ID: id, Revision: lemma.Revision, Forms: make([]WordEntry, len(forms))}
+
+	for idx, form := range forms {
+		if index.getItem(form.Node) == nil {
+			return LemmaEntry{}, fmt.Errorf("%w: lemma %d: no item: %d", Error, id, form.Node)
+		}
+
+		tagSet, err := index.tagSet(form.TagSet)
+		if err != nil {
+			return LemmaEntry{}, fmt.Errorf("%w: lemma %d: %v", Error, id, err)
+		}
+
+		entry.Forms[idx] = WordEntry{Word: index.GetItem(form.Node).Word(), TagSet: tagSet, Lemmas: nil}
+	}
+
+	return entry, nil
+}
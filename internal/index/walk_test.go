@@ -0,0 +1,54 @@
+package index_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+)
+
+func TestReadOnlyIndex_WalkWords(t *testing.T) {
+	compiled := mustBuild(t, newLemmasBuilder(t, lemmasRevision1))
+	walked := make([]string, 0)
+	lemmas := make(map[string][]index.LemmaID)
+
+	require.NoError(t, compiled.WalkWords(func(entry index.WordEntry) error {
+		key := entry.Word + " " + entry.TagSet.String()
+		walked = append(walked, key)
+		lemmas[key] = entry.Lemmas
+
+		return nil
+	}))
+
+	// words are visited in letters order, tag sets in index order
+	require.Equal(t, []string{"кот NOUN,sing", "коты NOUN,plur", "печь VERB", "печь NOUN,sing", "пёк VERB,sing"}, walked)
+	require.Equal(t, []index.LemmaID{2}, lemmas["печь NOUN,sing"])
+	require.Equal(t, []index.LemmaID{3}, lemmas["печь VERB"])
+
+	stop := errors.New("stop")
+	count := 0
+	require.ErrorIs(t, compiled.WalkWords(func(entry index.WordEntry) error {
+		count++
+		return stop
+	}), stop)
+	require.Equal(t, 1, count)
+}
+
+func TestReadOnlyIndex_WalkLemmas(t *testing.T) {
+	compiled := mustBuild(t, newLemmasBuilder(t, lemmasRevision1))
+	walked := make([]index.LemmaEntry, 0)
+
+	require.NoError(t, compiled.WalkLemmas(func(lemma index.LemmaEntry) error {
+		walked = append(walked, lemma)
+		return nil
+	}))
+
+	require.Len(t, walked, 3)
+	require.Equal(t, index.LemmaID(3), walked[2].ID)
+	require.Len(t, walked[2].Forms, 2)
+	require.Equal(t, "печь", walked[2].Forms[0].Word)
+	require.Equal(t, "VERB", walked[2].Forms[0].TagSet.String())
+	require.Equal(t, "пёк", walked[2].Forms[1].Word)
+}
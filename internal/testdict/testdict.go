@@ -0,0 +1,86 @@
+// Package testdict provides OpenCorpora test dictionaries compiled for tests of packages using compiled index.
+// Every dictionary is compiled once per test binary, tests take their own copies of compiled data.
+package testdict
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+var (
+	// Dictionary is OpenCorpora XML test dictionary file.
+	Dictionary = testdataFile("dict.opcorpora.xml")
+	// UpdateDictionary is OpenCorpora XML test dictionary file of Dictionary next revision.
+	UpdateDictionary = testdataFile("dict.update.opcorpora.xml")
+)
+
+var (
+	compiledMu sync.Mutex
+	compiled   = make(map[string][]byte) // compiled index data by dictionary file
+)
+
+// testdataFile returns path of OpenCorpora testdata file regardless of test working directory.
+func testdataFile(name string) string {
+	_, thisFile, _, _ := runtime.Caller(0)
+
+	return filepath.Join(filepath.Dir(thisFile), "..", "..", "pkg", "opencorpora", "testdata", name)
+}
+
+// Data returns compiled index data of XML dictionary fromFile. Dictionary is compiled on first call only.
+func Data(t testing.TB, fromFile string) []byte {
+	t.Helper()
+	logging.MustInit()
+
+	compiledMu.Lock()
+	defer compiledMu.Unlock()
+
+	data, found := compiled[fromFile]
+	if !found {
+		loader := opencorpora.NewLoader(t.TempDir())
+		loader.SetWorkers(1)
+		require.NoError(t, loader.ParseUpdate(fromFile, loader.CompiledFile()))
+
+		var err error
+		data, err = os.ReadFile(loader.CompiledFile())
+		require.NoError(t, err)
+
+		compiled[fromFile] = data
+	}
+
+	return append([]byte{}, data...)
+}
+
+// Index returns index loaded from compiled index data of XML dictionary fromFile.
+func Index(t testing.TB, fromFile string) *index.ReadOnlyIndex {
+	t.Helper()
+
+	data := Data(t, fromFile)
+
+	loaded, err := opencorpora.NewLoader(t.TempDir()).LoadIndexFrom(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	return loaded
+}
+
+// Loader returns loader of temporary data path having compiled index of XML dictionary fromFile.
+func Loader(t testing.TB, fromFile string) *opencorpora.Loader {
+	t.Helper()
+
+	data := Data(t, fromFile)
+
+	loader := opencorpora.NewLoader(t.TempDir())
+	loader.SetWorkers(1)
+	require.NoError(t, os.WriteFile(loader.CompiledFile(), data, 0o600))
+
+	return loader
+}
@@ -3,4 +3,6 @@ package common
 const (
 	DefaultDataPath        = "~/.gomorphy/data"
 	HTTPHeaderLastModified = "Last-Modified"
+	LocalDataPath          = ".data"              // data path used if not set by environment
+	EnvDataPath            = "GOMORPHY_DATA_PATH" // environment variable setting data path
 )
@@ -10,27 +10,36 @@ import (
 // ErrPath represents filepath related errors.
 var ErrPath = errors.New("path")
 
+// GetDataPath returns data path set by GOMORPHY_DATA_PATH environment variable or .data if variable is not set.
 func GetDataPath() string {
-	return ".data"
+	if dataPath := os.Getenv(EnvDataPath); dataPath != "" {
+		return dataPath
+	}
+
+	return LocalDataPath
 }
 
 func DomainDataPath(domain string) string {
 	return path.Join(GetDataPath(), domain)
 }
 
-func MakeDomainDataPath(domain string) (err error) {
-	domainDataPath := DomainDataPath(domain)
-	if domainDataPath, err = filepath.Abs(domainDataPath); err != nil {
+// MakeDataPath creates data path directory with all parents if it does not exist.
+func MakeDataPath(dataPath string) (err error) {
+	if dataPath, err = filepath.Abs(dataPath); err != nil {
 		return err
 	}
 
-	if err := os.MkdirAll(domainDataPath, os.ModePerm); err != nil {
+	if err := os.MkdirAll(dataPath, os.ModePerm); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+func MakeDomainDataPath(domain string) (err error) {
+	return MakeDataPath(DomainDataPath(domain))
+}
+
 func DomainFilePath(domain string, fileName string) string {
 	return path.Join(DomainDataPath(domain), fileName)
 }
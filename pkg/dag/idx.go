@@ -6,6 +6,7 @@ Idx implements Tag ids, providing simple indexed ID in index.
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/amarin/gomorphy/pkg/storage"
 
@@ -15,11 +16,14 @@ import (
 )
 
 // TagID represents Tag id in storage array.
-type TagID storage.ID8
+type TagID storage.ID16
 
-// Uint8 returns uint8 value of TagID.
-func (t TagID) Uint8() uint8 {
-	return uint8(t)
+// MaxTagsCount defines maximum count of tags Idx can address.
+const MaxTagsCount = math.MaxUint16 + 1
+
+// Uint16 returns uint16 value of TagID.
+func (t TagID) Uint16() uint16 {
+	return uint16(t)
 }
 
 // Idx implements Tag index routines.
@@ -44,10 +48,10 @@ func NewIndex(knownTags ...Tag) Idx {
 // Returns error if happens or nil.
 // Implements binutils.BinaryReaderFrom.
 func (tagsIndex *Idx) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
-	var listLen uint8
+	var listLen uint64
 
-	if listLen, err = reader.ReadUint8(); err != nil {
-		return fmt.Errorf("%w: read length byte: %v", common.ErrUnmarshal, err)
+	if listLen, err = storage.ReadUvarintMax(reader, MaxTagsCount); err != nil {
+		return fmt.Errorf("%w: read length: %v", common.ErrUnmarshal, err)
 	}
 
 	*tagsIndex = make(Idx, listLen) // allocate space
@@ -65,8 +69,12 @@ func (tagsIndex *Idx) BinaryReadFrom(reader *binutils.BinaryReader) (err error)
 // Returns error if happens or nil.
 // Implements binutils.BinaryWriterTo.
 func (tagsIndex Idx) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
-	if err = writer.WriteUint8(uint8(tagsIndex.Len())); err != nil {
-		return fmt.Errorf("%w: cant write length byte: %v", common.ErrMarshal, err)
+	if tagsIndex.Len() > MaxTagsCount {
+		return fmt.Errorf("%w: %d tags exceeds %d", common.ErrMarshal, tagsIndex.Len(), MaxTagsCount)
+	}
+
+	if err = storage.WriteUvarint(writer, uint64(tagsIndex.Len())); err != nil {
+		return fmt.Errorf("%w: cant write length: %v", common.ErrMarshal, err)
 	}
 
 	// iterate over known Tag's.
@@ -3,6 +3,7 @@ package dag_test
 import (
 	"bytes"
 	"encoding/hex"
+	"fmt"
 	"testing"
 
 	"github.com/amarin/binutils"
@@ -21,11 +22,13 @@ type testIndexStruct struct {
 
 var testCategoryListData = []testIndexStruct{ // nolint:gochecknoglobals
 	{"empty_tags_list", []dag.Tag{}, "00", false},
-	{"single_empty_tag", []dag.Tag{{dag.EmptyTagName, dag.EmptyTagName}}, "012020202020202020", false},
+	{"single_empty_tag", []dag.Tag{{dag.EmptyTagName, dag.EmptyTagName}}, "010000", false},
 	{"single_filled_tag", []dag.Tag{{dag.EmptyTagName, "POST"}},
-		"0120202020504f5354", false},
+		"010004504f5354", false},
 	{"couple_of_filled_tags", []dag.Tag{{dag.EmptyTagName, "POST"}, {"POST", "NOUN"}},
-		"0220202020504f5354504f53544e4f554e", false},
+		"020004504f535404504f5354044e4f554e", false},
+	{"variable_length_tags", []dag.Tag{{dag.EmptyTagName, "Abbr"}, {"Abbr", "Brand"}, {"Brand", "Имя"}},
+		"030004416262720441626272054272616e64054272616e6406d098d0bcd18f", false},
 }
 
 func TestIndex_Idx(t *testing.T) {
@@ -73,7 +76,7 @@ func TestIndex_ReadFrom(t *testing.T) {
 		{ // extra data in buffer is not taken and not an error
 			"extra_data_after_error",
 			[]dag.Tag{{dag.EmptyTagName, "POST"}},
-			"0120202020504f5354FF", false,
+			"010004504f5354FF", false,
 		},
 		{ // no data len byte should raise
 			"err_empty_data",
@@ -90,7 +93,7 @@ func TestIndex_ReadFrom(t *testing.T) {
 		{ // len of Tag's list greater than available data should raise
 			"err_len_mismatch_data",
 			[]dag.Tag{{"", "POST"}},
-			"02504f535420202020",
+			"0204504f535400",
 			true,
 		},
 	}...)
@@ -232,3 +235,19 @@ func TestIndex_Find(t *testing.T) { //nolint:paralleltest
 		})
 	}
 }
+
+func TestIndex_BinaryWriteTo_ManyTags(t *testing.T) {
+	t.Parallel()
+
+	index := dag.NewIndex()
+	for idx := 0; idx < 1000; idx++ {
+		require.Equal(t, dag.TagID(idx), index.Index(dag.TagName(fmt.Sprintf("%04d", idx)), ""))
+	}
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, index.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	loaded := dag.NewIndex()
+	require.NoError(t, loaded.BinaryReadFrom(binutils.NewBinaryReader(buffer)))
+	require.Equal(t, index, loaded)
+}
@@ -5,15 +5,21 @@ package dag
 import (
 	"errors"
 	"fmt"
+	"unicode/utf8"
 
 	"github.com/amarin/binutils"
+
+	"github.com/amarin/gomorphy/pkg/storage"
 )
 
 const (
 	// EmptyTagName defines constant tag name to replace empty strings and distinct unfilled and empty cases.
 	EmptyTagName TagName = "----"
 
-	tagNameLen = 4
+	// MaxTagNameLen defines maximum TagName length in bytes.
+	MaxTagNameLen = 255
+
+	fixedTagNameLen = 4 // TagName length in legacy fixed-width encoding
 )
 
 var (
@@ -23,21 +29,46 @@ var (
 	emptyTagNameBytes = []byte{0x20, 0x20, 0x20, 0x20}
 )
 
-// TagName represents name of grammatical category. It uses exactly 4 ASCII characters to name categories.
-// If TagName is shorter than 4 character is filled upto 4 with spaces.
+// TagName represents name of grammatical category. Any non-empty UTF-8 string upto MaxTagNameLen bytes
+// is allowed as TagName, i.e. OpenCorpora `NOUN`, `V-ey` or custom `Brand`, `Sku`, `Имя`.
+// Binary encoding writes TagName bytes prefixed with varint length, EmptyTagName uses zero length.
+// Legacy fixed-width encoding used exactly 4 ASCII characters and is still readable with BinaryReadFixedFrom.
 type TagName string
 
+// BinaryReadFrom reads length-prefixed TagName data using specified binutils.BinaryReader instance.
+// Returns error if happens or nil.
+// Implements binutils.BinaryReaderFrom.
 func (g *TagName) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
-	var tagNameBytes []byte
-	if tagNameBytes, err = reader.ReadBytesCount(tagNameLen); err != nil {
+	var (
+		nameLen      uint64
+		tagNameBytes []byte
+	)
+
+	if nameLen, err = storage.ReadUvarintMax(reader, MaxTagNameLen); err != nil {
+		return fmt.Errorf("%w: tag name: read length: %v", Error, err)
+	}
+
+	if nameLen == 0 {
+		*g = EmptyTagName
+		return nil
+	}
+
+	if tagNameBytes, err = reader.ReadBytesCount(int(nameLen)); err != nil {
 		return fmt.Errorf("%w: tag name: read: %v", Error, err)
 	}
 
-	if err = g.UnmarshalBinary(tagNameBytes); err != nil {
-		return err
+	return g.UnmarshalBinary(tagNameBytes)
+}
+
+// BinaryReadFixedFrom reads TagName data written in legacy fixed-width 4 bytes encoding.
+// Returns error if happens or nil.
+func (g *TagName) BinaryReadFixedFrom(reader *binutils.BinaryReader) (err error) {
+	var tagNameBytes []byte
+	if tagNameBytes, err = reader.ReadBytesCount(fixedTagNameLen); err != nil {
+		return fmt.Errorf("%w: tag name: read: %v", Error, err)
 	}
 
-	return nil
+	return g.UnmarshalBinary(tagNameBytes)
 }
 
 // BinaryWriteTo writes TagName data using specified binutils.BinaryWriter instance.
@@ -50,6 +81,10 @@ func (g TagName) BinaryWriteTo(writer *binutils.BinaryWriter) (err error) {
 		return err
 	}
 
+	if err = storage.WriteUvarint(writer, uint64(len(nameBytes))); err != nil {
+		return fmt.Errorf("%w: tag name: write length: %v", Error, err)
+	}
+
 	if err = writer.WriteBytes(nameBytes); err != nil {
 		return fmt.Errorf("%w: tag name: write: %v", Error, err)
 	}
@@ -62,36 +97,30 @@ func (g TagName) String() string {
 	return string(g)
 }
 
-// MarshalBinary makes name bytes string representation.
-// Always produces 4 bytes. EmptyTagName TagName name represents as 4 spaces.
+// MarshalBinary makes name bytes representation. Implements encoding.BinaryMarshaler.
+// Produces UTF-8 name bytes, EmptyTagName or empty TagName represents as empty bytes.
+// Returns error if name is not valid UTF-8 or longer than MaxTagNameLen bytes.
 func (g TagName) MarshalBinary() (data []byte, err error) {
 	if len(g) == 0 || g == EmptyTagName {
-		return []byte("    "), nil
+		return []byte{}, nil
 	}
 
-	res := []byte(g)
-
-	if len(res) != tagNameLen {
-		return []byte{}, fmt.Errorf("%w: %T.MarshalBinary(%v)=%v len=%d", Error, g, g, res, len(res))
+	if len(g) > MaxTagNameLen || !utf8.ValidString(string(g)) {
+		return []byte{}, fmt.Errorf("%w: %T.MarshalBinary(%v) len=%d: invalid name", Error, g, []byte(g), len(g))
 	}
 
-	return res, nil
+	return []byte(g), nil
 }
 
+// UnmarshalBinary takes name from bytes representation. Implements encoding.BinaryUnmarshaler.
+// Empty bytes and legacy 4 spaces are taken as EmptyTagName.
+// Returns error if bytes are not valid UTF-8 or longer than MaxTagNameLen.
 func (g *TagName) UnmarshalBinary(tagNameBytes []byte) error {
-	if len(tagNameBytes) != tagNameLen {
-		return fmt.Errorf("%w: tag name: read: expect %d bytes, not %d", Error, tagNameLen, len(tagNameBytes))
-	}
-
-	isEmpty := false
-	for idx := 0; idx < len(emptyTagNameBytes); idx++ {
-		isEmpty = tagNameBytes[idx] == emptyTagNameBytes[idx]
-		if !isEmpty {
-			break
-		}
+	if len(tagNameBytes) > MaxTagNameLen || !utf8.Valid(tagNameBytes) {
+		return fmt.Errorf("%w: tag name: read: invalid name %v", Error, tagNameBytes)
 	}
 
-	if isEmpty || string(tagNameBytes) == "    " {
+	if len(tagNameBytes) == 0 || string(tagNameBytes) == string(emptyTagNameBytes) {
 		*g = EmptyTagName
 	} else {
 		*g = TagName(tagNameBytes)
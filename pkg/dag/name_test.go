@@ -2,6 +2,7 @@ package dag_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/amarin/gomorphy/pkg/dag"
@@ -15,10 +16,13 @@ func TestTagName_MarshalBinary(t *testing.T) { //nolint:paralleltest
 		wantErr  bool
 	}{
 		{"ok_4_bytes", "NOUN", []byte{78, 79, 85, 78}, false},
-		{"ok_empty", "", []byte{32, 32, 32, 32}, false},
-		{"nok_3_bytes", "ABC", []byte{}, true},
-		{"nok_5_bytes", "bytes", []byte{}, true},
-		{"nok_non_ascii", "байт", []byte{}, true},
+		{"ok_empty", "", []byte{}, false},
+		{"ok_empty_tag_name", dag.EmptyTagName, []byte{}, false},
+		{"ok_3_bytes", "ABC", []byte{65, 66, 67}, false},
+		{"ok_5_bytes", "bytes", []byte{98, 121, 116, 101, 115}, false},
+		{"ok_non_ascii", "байт", []byte{0xd0, 0xb1, 0xd0, 0xb0, 0xd0, 0xb9, 0xd1, 0x82}, false},
+		{"nok_invalid_utf8", "\xff\xfe", []byte{}, true},
+		{"nok_too_long", dag.TagName(strings.Repeat("a", dag.MaxTagNameLen+1)), []byte{}, true},
 	}
 
 	for _, tt := range tests { //nolint:paralleltest
@@ -44,9 +48,12 @@ func TestTagName_UnmarshalBinary(t *testing.T) { //nolint:paralleltest
 		wantErr bool
 	}{
 		{"ok_4_bytes", "aaaa", []byte{97, 97, 97, 97}, false},
-		{"nok_3_bytes", "aaa", []byte{97, 97, 97}, true},
-		{"nok_5_bytes", "aaaaa", []byte{97, 97, 97, 98, 99}, true},
-		{"ok_empty", dag.EmptyTagName, []byte{32, 32, 32, 32}, false},
+		{"ok_3_bytes", "aaa", []byte{97, 97, 97}, false},
+		{"ok_5_bytes", "aaabc", []byte{97, 97, 97, 98, 99}, false},
+		{"ok_non_ascii", "Имя", []byte{0xd0, 0x98, 0xd0, 0xbc, 0xd1, 0x8f}, false},
+		{"ok_empty", dag.EmptyTagName, []byte{}, false},
+		{"ok_legacy_empty", dag.EmptyTagName, []byte{32, 32, 32, 32}, false},
+		{"nok_invalid_utf8", "", []byte{0xff, 0xfe}, true},
 	}
 
 	for _, tt := range tests { //nolint:paralleltest
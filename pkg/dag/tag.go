@@ -45,6 +45,20 @@ func (g *Tag) BinaryReadFrom(reader *binutils.BinaryReader) (err error) {
 	return nil
 }
 
+// BinaryReadFixedFrom reads Tag data having names written in legacy fixed-width 4 bytes encoding.
+// Returns error if happens or nil.
+func (g *Tag) BinaryReadFixedFrom(reader *binutils.BinaryReader) (err error) {
+	if err = g.Parent.BinaryReadFixedFrom(reader); err != nil {
+		return fmt.Errorf("%w: tag: read: %v", Error, err)
+	}
+
+	if err = g.Name.BinaryReadFixedFrom(reader); err != nil {
+		return fmt.Errorf("%w: tag: read: %v", Error, err)
+	}
+
+	return nil
+}
+
 // NewTag makes new tag with required parent, name, alias and description.
 func NewTag(parent TagName, name TagName) *Tag {
 	if parent == "" {
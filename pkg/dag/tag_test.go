@@ -34,18 +34,21 @@ var tagTests = []struct { // nolint:gochecknoglobals
 	},
 }
 var tagSerializeTest = []struct {
-	name    string
-	tag     dag.Tag
-	binData string
+	name      string
+	tag       dag.Tag
+	binData   string
+	fixedData string
 }{
 	{
 		"with_no_parent",
 		*dag.NewTag("", "POST"),
+		"0004504f5354",
 		"2d2d2d2d504f5354",
 	},
 	{
 		"with_parent",
 		*dag.NewTag("NOUN", "POST"),
+		"044e4f554e04504f5354",
 		"4e4f554e504f5354",
 	},
 }
@@ -123,3 +126,27 @@ func TestTag_BinaryReadFrom(t *testing.T) {
 		})
 	}
 }
+
+func TestTag_BinaryWriteTo(t *testing.T) {
+	for _, tt := range tagSerializeTest {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := new(bytes.Buffer)
+			require.NoError(t, tt.tag.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+			require.Equal(t, tt.binData, hex.EncodeToString(buffer.Bytes()))
+		})
+	}
+}
+
+func TestTag_BinaryReadFixedFrom(t *testing.T) {
+	for _, tt := range tagSerializeTest {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &dag.Tag{}
+			data, err := hex.DecodeString(tt.fixedData)
+			require.NoError(t, err)
+			reader := binutils.NewBinaryReader(bytes.NewBuffer(data))
+			require.NoError(t, g.BinaryReadFixedFrom(reader))
+			require.Equal(t, tt.tag.Name, g.Name)
+			require.Equal(t, tt.tag.Parent, g.Parent)
+		})
+	}
+}
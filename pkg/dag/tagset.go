@@ -1,6 +1,7 @@
 package dag
 
 import (
+	"sort"
 	"strings"
 )
 
@@ -16,3 +17,17 @@ func (tagSet TagSet) String() string {
 
 	return strings.Join(tagStrings, ",")
 }
+
+// Key returns TagSet key independent of tags order and IDs.
+// Tag sets of different indexes having the same tag names have equal keys,
+// as indexes may number and order tags differently.
+func (tagSet TagSet) Key() string {
+	tagStrings := make([]string, len(tagSet))
+	for idx, tag := range tagSet {
+		tagStrings[idx] = string(tag.Name)
+	}
+
+	sort.Strings(tagStrings)
+
+	return strings.Join(tagStrings, ",")
+}
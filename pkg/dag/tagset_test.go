@@ -0,0 +1,21 @@
+package dag_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+func TestTagSet_Key(t *testing.T) {
+	nounMasc := dag.TagSet{{Parent: "", Name: "NOUN"}, {Parent: "", Name: "masc"}}
+	mascNoun := dag.TagSet{{Parent: "gndr", Name: "masc"}, {Parent: "POST", Name: "NOUN"}}
+	nounFemn := dag.TagSet{{Parent: "", Name: "NOUN"}, {Parent: "", Name: "femn"}}
+
+	require.Equal(t, "NOUN,masc", nounMasc.Key())
+	require.Equal(t, nounMasc.Key(), mascNoun.Key())
+	require.NotEqual(t, nounMasc.Key(), nounFemn.Key())
+	require.Equal(t, "masc,NOUN", mascNoun.String())
+	require.Empty(t, dag.TagSet{}.Key())
+}
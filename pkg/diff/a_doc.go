@@ -0,0 +1,5 @@
+package diff
+
+// Package diff compares two compiled indexes, e.g. old and new dictionary revisions.
+// Report lists added, removed and changed words tag sets, added, removed and changed grammemes
+// and index sizes, and is written in human readable or JSON form.
@@ -0,0 +1,108 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// Compare returns report of differences between old and new indexes.
+// If words specified only these words are compared, otherwise all words of both indexes are walked.
+func Compare(oldIndex *index.ReadOnlyIndex, newIndex *index.ReadOnlyIndex, words ...string) (*Report, error) {
+	report := &Report{
+		Old:              sizesOf(oldIndex),
+		New:              sizesOf(newIndex),
+		AddedGrammemes:   make([]Grammeme, 0),
+		RemovedGrammemes: make([]Grammeme, 0),
+		ChangedGrammemes: make([]GrammemeChange, 0),
+		AddedWords:       make([]WordChange, 0),
+		RemovedWords:     make([]WordChange, 0),
+		ChangedWords:     make([]WordChange, 0),
+	}
+
+	report.compareGrammemes(oldIndex.Tags(), newIndex.Tags())
+
+	if len(words) > 0 {
+		for _, word := range words {
+			oldTagSets, newTagSets := wordTagSets(oldIndex, word), wordTagSets(newIndex, word)
+			if removed, added := index.DiffTagSets(oldTagSets, newTagSets); len(removed) > 0 || len(added) > 0 {
+				report.addWordDiff(index.WordDiff{
+					Word:    word,
+					InOld:   len(oldTagSets) > 0,
+					InNew:   len(newTagSets) > 0,
+					Removed: removed,
+					Added:   added,
+				})
+			}
+		}
+
+		return report, nil
+	}
+
+	err := index.DiffWords(oldIndex, newIndex, func(wordDiff index.WordDiff) error {
+		report.addWordDiff(wordDiff)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: words: %v", Error, err)
+	}
+
+	return report, nil
+}
+
+// wordTagSets returns word tag sets or nil if word is not known to index.
+func wordTagSets(idx *index.ReadOnlyIndex, word string) []dag.TagSet {
+	node, err := idx.FetchString(word)
+	if err != nil {
+		return nil
+	}
+
+	return node.TagSets()
+}
+
+// compareGrammemes puts grammemes added, removed or having changed parent into report sorted by name.
+func (report *Report) compareGrammemes(oldTags dag.Idx, newTags dag.Idx) {
+	oldGrammemes := make(map[dag.TagName]Grammeme, len(oldTags))
+	for _, tag := range oldTags {
+		oldGrammemes[tag.Name] = newGrammeme(tag)
+	}
+
+	newGrammemes := make(map[dag.TagName]Grammeme, len(newTags))
+	for _, tag := range newTags {
+		newGrammemes[tag.Name] = newGrammeme(tag)
+	}
+
+	for name, oldGrammeme := range oldGrammemes {
+		newGrammeme, found := newGrammemes[name]
+
+		switch {
+		case !found:
+			report.RemovedGrammemes = append(report.RemovedGrammemes, oldGrammeme)
+		case newGrammeme.Parent != oldGrammeme.Parent:
+			report.ChangedGrammemes = append(report.ChangedGrammemes, GrammemeChange{
+				Name:      name,
+				OldParent: oldGrammeme.Parent,
+				NewParent: newGrammeme.Parent,
+			})
+		}
+	}
+
+	for name, newGrammeme := range newGrammemes {
+		if _, found := oldGrammemes[name]; !found {
+			report.AddedGrammemes = append(report.AddedGrammemes, newGrammeme)
+		}
+	}
+
+	sort.Slice(report.AddedGrammemes, func(i, j int) bool {
+		return report.AddedGrammemes[i].Name < report.AddedGrammemes[j].Name
+	})
+	sort.Slice(report.RemovedGrammemes, func(i, j int) bool {
+		return report.RemovedGrammemes[i].Name < report.RemovedGrammemes[j].Name
+	})
+	sort.Slice(report.ChangedGrammemes, func(i, j int) bool {
+		return report.ChangedGrammemes[i].Name < report.ChangedGrammemes[j].Name
+	})
+}
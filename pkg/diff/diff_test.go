@@ -0,0 +1,128 @@
+package diff_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/internal/testdict"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/diff"
+)
+
+func words(changes []diff.WordChange) []string {
+	res := make([]string, len(changes))
+	for idx, change := range changes {
+		res[idx] = change.Word
+	}
+
+	return res
+}
+
+func TestCompare(t *testing.T) {
+	oldIndex := testdict.Index(t, testdict.Dictionary)
+	newIndex := testdict.Index(t, testdict.UpdateDictionary)
+
+	report, err := diff.Compare(oldIndex, newIndex)
+	require.NoError(t, err)
+	require.False(t, report.Empty())
+	require.Equal(t, oldIndex.WordsCount(), report.Old.Words)
+	require.Equal(t, newIndex.WordsCount(), report.New.Words)
+	require.Equal(t, oldIndex.LemmasCount(), report.Old.Lemmas)
+	require.Empty(t, report.AddedGrammemes)
+	require.Empty(t, report.RemovedGrammemes)
+	require.Empty(t, report.ChangedGrammemes)
+	require.Equal(t, []string{"кит", "кита", "китов", "киты"}, words(report.AddedWords))
+	require.Equal(t, []string{"ёлка", "ёлки", "ёлку", "ёлок"}, words(report.RemovedWords))
+	require.Equal(t, []string{"лес", "леса"}, words(report.ChangedWords))
+	require.Equal(t, []string{"NOUN,inan,masc,sing,accs"}, report.ChangedWords[0].Removed)
+	require.Empty(t, report.ChangedWords[0].Added)
+
+	same, err := diff.Compare(oldIndex, oldIndex)
+	require.NoError(t, err)
+	require.True(t, same.Empty())
+}
+
+func TestCompare_Words(t *testing.T) {
+	oldIndex := testdict.Index(t, testdict.Dictionary)
+	newIndex := testdict.Index(t, testdict.UpdateDictionary)
+
+	report, err := diff.Compare(oldIndex, newIndex, "кит", "лес", "ёлка", "стол", "несуществующий")
+	require.NoError(t, err)
+	require.Equal(t, []string{"кит"}, words(report.AddedWords))
+	require.Equal(t, []string{"ёлка"}, words(report.RemovedWords))
+	require.Equal(t, []string{"лес"}, words(report.ChangedWords))
+}
+
+func TestCompare_Grammemes(t *testing.T) {
+	oldBuilder := index.NewBuilder()
+	oldBuilder.TagID("NOUN", "POST")
+	oldBuilder.TagID("sing", "NMbr")
+	oldBuilder.TagID("Name", "")
+	require.NoError(t, oldBuilder.AddTagSet("кот", "NOUN", "sing"))
+	require.NoError(t, oldBuilder.AddTagSet("мурка", "NOUN", "Name"))
+
+	newBuilder := index.NewBuilder()
+	newBuilder.TagID("NOUN", "POST")
+	newBuilder.TagID("sing", "NMbr")
+	newBuilder.TagID("Name", "ANim")
+	newBuilder.TagID("plur", "NMbr")
+	require.NoError(t, newBuilder.AddTagSet("кот", "NOUN", "sing"))
+	require.NoError(t, newBuilder.AddTagSet("мурка", "NOUN", "Name"))
+	require.NoError(t, newBuilder.AddTagSet("коты", "NOUN", "plur"))
+
+	oldIndex, err := oldBuilder.Build()
+	require.NoError(t, err)
+	newIndex, err := newBuilder.Build()
+	require.NoError(t, err)
+
+	report, err := diff.Compare(oldIndex, newIndex)
+	require.NoError(t, err)
+	require.Equal(t, []diff.Grammeme{{Name: "plur", Parent: "NMbr"}}, report.AddedGrammemes)
+	require.Empty(t, report.RemovedGrammemes)
+	require.Equal(t, []diff.GrammemeChange{{Name: "Name", OldParent: "", NewParent: "ANim"}}, report.ChangedGrammemes)
+	require.Equal(t, []string{"коты"}, words(report.AddedWords))
+	require.Empty(t, report.ChangedWords) // tag sets are compared by tag names
+
+	report, err = diff.Compare(newIndex, oldIndex)
+	require.NoError(t, err)
+	require.Equal(t, []diff.Grammeme{{Name: dag.TagName("plur"), Parent: "NMbr"}}, report.RemovedGrammemes)
+}
+
+func TestReport_WriteText(t *testing.T) {
+	oldIndex := testdict.Index(t, testdict.Dictionary)
+	newIndex := testdict.Index(t, testdict.UpdateDictionary)
+
+	report, err := diff.Compare(oldIndex, newIndex)
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, report.WriteText(buffer, 2))
+	text := buffer.String()
+
+	require.Contains(t, text, "lemmas: 6 -> 6 (+0)\n")
+	require.Contains(t, text, "added words: 4\n  кит\n    + NOUN,anim,masc,sing,nomn\n")
+	require.Contains(t, text, "  ... 2 more\n")
+	require.Contains(t, text, "changed words: 2\n  лес\n    - NOUN,inan,masc,sing,accs\n")
+	require.NotContains(t, text, "added grammemes:")
+	require.False(t, strings.Contains(text, "китов"), "listing limited")
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	oldIndex := testdict.Index(t, testdict.Dictionary)
+	newIndex := testdict.Index(t, testdict.UpdateDictionary)
+
+	report, err := diff.Compare(oldIndex, newIndex)
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, report.WriteJSON(buffer))
+
+	decoded := new(diff.Report)
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), decoded))
+	require.Equal(t, report, decoded)
+}
@@ -0,0 +1,8 @@
+package diff
+
+import (
+	"errors"
+)
+
+// Error identifies indexes comparison errors.
+var Error = errors.New("diff")
@@ -0,0 +1,102 @@
+package diff
+
+import (
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// Sizes provides index sizes.
+type Sizes struct {
+	Words     int `json:"words"`     // count of indexed words
+	Nodes     int `json:"nodes"`     // count of index nodes
+	TagSets   int `json:"tagSets"`   // count of distinct tag sets
+	Lemmas    int `json:"lemmas"`    // count of known lemmas
+	Grammemes int `json:"grammemes"` // count of known grammemes
+}
+
+// sizesOf returns sizes of specified index.
+func sizesOf(idx *index.ReadOnlyIndex) Sizes {
+	return Sizes{
+		Words:     idx.WordsCount(),
+		Nodes:     idx.NodesCount(),
+		TagSets:   idx.TagSetIndex().Size(),
+		Lemmas:    idx.LemmasCount(),
+		Grammemes: idx.Tags().Len(),
+	}
+}
+
+// Grammeme provides grammeme name and parent name, parent is empty for root grammemes.
+type Grammeme struct {
+	Name   dag.TagName `json:"name"`
+	Parent dag.TagName `json:"parent,omitempty"`
+}
+
+// newGrammeme returns grammeme of specified tag.
+func newGrammeme(tag dag.Tag) Grammeme {
+	if tag.Parent == dag.EmptyTagName {
+		tag.Parent = ""
+	}
+
+	return Grammeme{Name: tag.Name, Parent: tag.Parent}
+}
+
+// GrammemeChange provides grammeme having changed parent.
+type GrammemeChange struct {
+	Name      dag.TagName `json:"name"`
+	OldParent dag.TagName `json:"oldParent,omitempty"`
+	NewParent dag.TagName `json:"newParent,omitempty"`
+}
+
+// WordChange provides word tag sets removed from old index and added to new one.
+type WordChange struct {
+	Word    string   `json:"word"`
+	Removed []string `json:"removed,omitempty"`
+	Added   []string `json:"added,omitempty"`
+}
+
+// newWordChange returns change of specified word diff having tag sets as comma separated tag names.
+func newWordChange(wordDiff index.WordDiff) WordChange {
+	change := WordChange{Word: wordDiff.Word, Removed: nil, Added: nil}
+
+	for _, tagSet := range wordDiff.Removed {
+		change.Removed = append(change.Removed, tagSet.String())
+	}
+
+	for _, tagSet := range wordDiff.Added {
+		change.Added = append(change.Added, tagSet.String())
+	}
+
+	return change
+}
+
+// Report provides differences between old and new indexes.
+type Report struct {
+	Old              Sizes            `json:"old"`
+	New              Sizes            `json:"new"`
+	AddedGrammemes   []Grammeme       `json:"addedGrammemes"`
+	RemovedGrammemes []Grammeme       `json:"removedGrammemes"`
+	ChangedGrammemes []GrammemeChange `json:"changedGrammemes"`
+	AddedWords       []WordChange     `json:"addedWords"`   // words missed in old index
+	RemovedWords     []WordChange     `json:"removedWords"` // words missed in new index
+	ChangedWords     []WordChange     `json:"changedWords"` // words having changed tag sets
+}
+
+// Empty reports whether indexes have the same grammemes and words tag sets.
+func (report Report) Empty() bool {
+	return len(report.AddedGrammemes)+len(report.RemovedGrammemes)+len(report.ChangedGrammemes)+
+		len(report.AddedWords)+len(report.RemovedWords)+len(report.ChangedWords) == 0
+}
+
+// addWordDiff puts word diff into added, removed or changed words.
+func (report *Report) addWordDiff(wordDiff index.WordDiff) {
+	change := newWordChange(wordDiff)
+
+	switch {
+	case !wordDiff.InOld:
+		report.AddedWords = append(report.AddedWords, change)
+	case !wordDiff.InNew:
+		report.RemovedWords = append(report.RemovedWords, change)
+	default:
+		report.ChangedWords = append(report.ChangedWords, change)
+	}
+}
@@ -0,0 +1,105 @@
+package diff
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// sizeLine returns size line having old and new values with delta.
+func sizeLine(name string, oldValue int, newValue int) string {
+	return fmt.Sprintf("%s: %d -> %d (%+d)", name, oldValue, newValue, newValue-oldValue)
+}
+
+// WriteText writes human readable report into writer.
+// Limit restricts count of listed words in every section, 0 lists all words.
+func (report Report) WriteText(writer io.Writer, limit int) error {
+	buffered := bufio.NewWriter(writer)
+
+	lines := []string{
+		sizeLine("words", report.Old.Words, report.New.Words),
+		sizeLine("nodes", report.Old.Nodes, report.New.Nodes),
+		sizeLine("tag sets", report.Old.TagSets, report.New.TagSets),
+		sizeLine("lemmas", report.Old.Lemmas, report.New.Lemmas),
+		sizeLine("grammemes", report.Old.Grammemes, report.New.Grammemes),
+	}
+
+	if len(report.AddedGrammemes) > 0 {
+		lines = append(lines, fmt.Sprintf("added grammemes: %d", len(report.AddedGrammemes)))
+		for _, grammeme := range report.AddedGrammemes {
+			lines = append(lines, fmt.Sprintf("  + %v %v", grammeme.Name, grammeme.Parent))
+		}
+	}
+
+	if len(report.RemovedGrammemes) > 0 {
+		lines = append(lines, fmt.Sprintf("removed grammemes: %d", len(report.RemovedGrammemes)))
+		for _, grammeme := range report.RemovedGrammemes {
+			lines = append(lines, fmt.Sprintf("  - %v %v", grammeme.Name, grammeme.Parent))
+		}
+	}
+
+	if len(report.ChangedGrammemes) > 0 {
+		lines = append(lines, fmt.Sprintf("changed grammemes: %d", len(report.ChangedGrammemes)))
+		for _, change := range report.ChangedGrammemes {
+			lines = append(lines, fmt.Sprintf("  * %v %v -> %v", change.Name, change.OldParent, change.NewParent))
+		}
+	}
+
+	lines = append(lines, wordsLines("added words", report.AddedWords, limit)...)
+	lines = append(lines, wordsLines("removed words", report.RemovedWords, limit)...)
+	lines = append(lines, wordsLines("changed words", report.ChangedWords, limit)...)
+
+	for _, line := range lines {
+		if _, err := buffered.WriteString(strings.TrimRight(line, " ") + "\n"); err != nil {
+			return fmt.Errorf("%w: text: %v", Error, err)
+		}
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("%w: text: %v", Error, err)
+	}
+
+	return nil
+}
+
+// wordsLines returns section lines listing words changes up to limit if limit is not 0.
+func wordsLines(title string, changes []WordChange, limit int) []string {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	lines := []string{fmt.Sprintf("%s: %d", title, len(changes))}
+
+	for idx, change := range changes {
+		if limit > 0 && idx == limit {
+			lines = append(lines, fmt.Sprintf("  ... %d more", len(changes)-limit))
+
+			break
+		}
+
+		lines = append(lines, "  "+change.Word)
+		for _, tagSet := range change.Removed {
+			lines = append(lines, "    - "+tagSet)
+		}
+
+		for _, tagSet := range change.Added {
+			lines = append(lines, "    + "+tagSet)
+		}
+	}
+
+	return lines
+}
+
+// WriteJSON writes report as indented JSON object into writer.
+func (report Report) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("%w: json: %v", Error, err)
+	}
+
+	return nil
+}
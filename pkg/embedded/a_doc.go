@@ -0,0 +1,9 @@
+package embedded
+
+// Package embedded loads compiled index embedded into application binary, so applications need no data directory
+// at runtime. Embed compiled index by go:embed directive into byte slice or embed.FS and load it once at start:
+//
+//	//go:embed opencorpora.dat
+//	var dictionary []byte
+//
+//	var morphIndex = embedded.MustLoad(dictionary)
@@ -0,0 +1,51 @@
+package embedded
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+// Load loads compiled index from data, e.g. compiled index embedded into byte slice.
+func Load(data []byte) (*index.ReadOnlyIndex, error) {
+	loaded, err := opencorpora.NewLoader("").LoadIndexFrom(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", Error, err)
+	}
+
+	return loaded, nil
+}
+
+// LoadFS loads compiled index from named file of file system, e.g. embed.FS.
+func LoadFS(fileSystem fs.FS, name string) (*index.ReadOnlyIndex, error) {
+	loaded, err := opencorpora.NewLoader("").LoadIndexFromFS(fileSystem, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v: %v", Error, name, err)
+	}
+
+	return loaded, nil
+}
+
+// MustLoad loads compiled index from data like Load but panics if data is not a compiled index.
+// Intended for package variables initialization from embedded data.
+func MustLoad(data []byte) *index.ReadOnlyIndex {
+	loaded, err := Load(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return loaded
+}
+
+// MustLoadFS loads compiled index from named file of file system like LoadFS but panics on errors.
+func MustLoadFS(fileSystem fs.FS, name string) *index.ReadOnlyIndex {
+	loaded, err := LoadFS(fileSystem, name)
+	if err != nil {
+		panic(err)
+	}
+
+	return loaded
+}
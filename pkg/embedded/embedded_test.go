@@ -0,0 +1,41 @@
+package embedded_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/testdict"
+	"github.com/amarin/gomorphy/pkg/embedded"
+)
+
+func TestLoad(t *testing.T) {
+	data := testdict.Data(t, testdict.Dictionary)
+
+	loaded, err := embedded.Load(data)
+	require.NoError(t, err)
+	require.Equal(t, 21, loaded.WordsCount())
+
+	node, err := loaded.FetchString("ёж")
+	require.NoError(t, err)
+	require.NotEmpty(t, node.TagSets())
+
+	require.Equal(t, loaded.WordsCount(), embedded.MustLoad(data).WordsCount())
+
+	_, err = embedded.Load(data[:len(data)/2])
+	require.ErrorIs(t, err, embedded.Error)
+	require.Panics(t, func() { embedded.MustLoad([]byte("not an index")) })
+}
+
+func TestLoadFS(t *testing.T) {
+	fileSystem := fstest.MapFS{"dict/opencorpora.dat": &fstest.MapFile{Data: testdict.Data(t, testdict.Dictionary)}} //nolint:exhaustivestruct
+
+	loaded, err := embedded.LoadFS(fileSystem, "dict/opencorpora.dat")
+	require.NoError(t, err)
+	require.Equal(t, 21, loaded.WordsCount())
+	require.Equal(t, loaded.WordsCount(), embedded.MustLoadFS(fileSystem, "dict/opencorpora.dat").WordsCount())
+
+	_, err = embedded.LoadFS(fileSystem, "missed.dat")
+	require.ErrorIs(t, err, embedded.Error)
+}
@@ -0,0 +1,8 @@
+package embedded
+
+import (
+	"errors"
+)
+
+// Error identifies embedded index loading errors.
+var Error = errors.New("embedded")
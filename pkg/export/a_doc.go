@@ -0,0 +1,5 @@
+package export
+
+// Package export implements compiled index exporters.
+// All words with their tag sets and lemmas are streamed from index items into TSV, JSON Lines
+// or OpenCorpora-compatible XML, so exported data are never collected in memory.
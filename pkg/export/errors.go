@@ -0,0 +1,14 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// Error identifies export errors.
+	Error = errors.New("export")
+
+	// ErrUnknownFormat indicates unknown export format requested.
+	ErrUnknownFormat = fmt.Errorf("%w: unknown format", Error)
+)
@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amarin/gomorphy/internal/index"
+)
+
+// Format defines export format.
+type Format string
+
+// Known export formats.
+const (
+	FormatTSV   Format = "tsv"   // tab separated word, tags and lemma IDs line per word tag set
+	FormatJSONL Format = "jsonl" // JSON object line per word tag set
+	FormatXML   Format = "xml"   // OpenCorpora XML dictionary
+)
+
+// Formats lists known export formats.
+var Formats = []Format{FormatTSV, FormatJSONL, FormatXML}
+
+// ParseFormat returns export format by its name ignoring case.
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %v", ErrUnknownFormat, name)
+}
+
+// exporter writes index data to buffered writer.
+type exporter func(writer *bufio.Writer, idx *index.ReadOnlyIndex) error
+
+// Write exports index data into writer using specified format.
+func Write(writer io.Writer, idx *index.ReadOnlyIndex, format Format) error {
+	var export exporter
+
+	switch format {
+	case FormatTSV:
+		export = writeTSV
+	case FormatJSONL:
+		export = writeJSONL
+	case FormatXML:
+		export = writeXML
+	default:
+		return fmt.Errorf("%w: %v", ErrUnknownFormat, format)
+	}
+
+	buffered := bufio.NewWriter(writer)
+
+	if err := export(buffered, idx); err != nil {
+		return fmt.Errorf("%w: %v: %v", Error, format, err)
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("%w: %v: %v", Error, format, err)
+	}
+
+	return nil
+}
+
+// lemmaIDs returns comma separated lemma IDs.
+func lemmaIDs(ids []index.LemmaID) string {
+	res := make([]string, len(ids))
+	for idx, id := range ids {
+		res[idx] = fmt.Sprint(id)
+	}
+
+	return strings.Join(res, ",")
+}
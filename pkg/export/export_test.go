@@ -0,0 +1,118 @@
+package export_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/internal/testdict"
+	"github.com/amarin/gomorphy/pkg/export"
+)
+
+func exportString(t *testing.T, compiled *index.ReadOnlyIndex, format export.Format) string {
+	t.Helper()
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, export.Write(buffer, compiled, format))
+
+	return buffer.String()
+}
+
+func TestWrite_TSV(t *testing.T) {
+	compiled := testdict.Index(t, testdict.Dictionary)
+	lines := strings.Split(strings.TrimSuffix(exportString(t, compiled, export.FormatTSV), "\n"), "\n")
+
+	tagSetsCount := 0
+	require.NoError(t, compiled.WalkWords(func(entry index.WordEntry) error {
+		tagSetsCount++
+		return nil
+	}))
+
+	require.Len(t, lines, tagSetsCount)
+	require.Contains(t, lines, "ёж\tNOUN,anim,masc,sing,nomn\t1")
+	require.Contains(t, lines, "ежей\tNOUN,anim,masc,plur,gent\t1")
+}
+
+func TestWrite_JSONL(t *testing.T) {
+	compiled := testdict.Index(t, testdict.Dictionary)
+	scanner := bufio.NewScanner(strings.NewReader(exportString(t, compiled, export.FormatJSONL)))
+	words := make(map[string]int)
+
+	for scanner.Scan() {
+		record := struct {
+			Word   string   `json:"word"`
+			Tags   []string `json:"tags"`
+			Lemmas []int    `json:"lemmas"`
+		}{}
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record), scanner.Text())
+		require.NotEmpty(t, record.Tags, scanner.Text())
+		require.NotEmpty(t, record.Lemmas, scanner.Text())
+		words[record.Word]++
+	}
+
+	require.Len(t, words, compiled.WordsCount())
+	require.Equal(t, 2, words["ежей"])
+}
+
+func TestWrite_XML(t *testing.T) {
+	expected, compiled := testdict.Data(t, testdict.Dictionary), testdict.Index(t, testdict.Dictionary)
+
+	exported := filepath.Join(t.TempDir(), "exported.xml")
+	exportedXML := exportString(t, compiled, export.FormatXML)
+	require.NoError(t, os.WriteFile(exported, []byte(exportedXML), 0o600))
+
+	// lemma normal form is written as lemma text and the first lemma form
+	require.Contains(t, exportedXML,
+		`<lemma id="1" rev="1"><l t="ёж"><g v="NOUN"/><g v="anim"/><g v="masc"/></l><f t="ёж">`)
+	// lemma text not listed among lemma forms is not kept by index
+	require.Contains(t, exportedXML, `<lemma id="5" rev="5"><l t="стоит">`)
+
+	// dictionary compiled from exported XML is the same
+	require.Equal(t, expected, testdict.Data(t, exported))
+}
+
+func TestWrite_XML_NoLemmas(t *testing.T) {
+	builder := index.NewBuilder()
+	builder.TagID("NOUN", "")
+	builder.TagID("sing", "")
+	require.NoError(t, builder.AddTagSet("кот", "NOUN", "sing"))
+	require.NoError(t, builder.AddTagSet("кот", "NOUN"))
+	require.NoError(t, builder.AddTagSet("лес", "NOUN"))
+
+	compiled, err := builder.Build()
+	require.NoError(t, err)
+
+	exported := filepath.Join(t.TempDir(), "exported.xml")
+	require.NoError(t, os.WriteFile(exported, []byte(exportString(t, compiled, export.FormatXML)), 0o600))
+
+	reloaded := testdict.Index(t, exported)
+	require.Equal(t, 3, reloaded.LemmasCount()) // every word tag set is a lemma
+
+	for _, word := range []string{"кот", "лес"} {
+		expectedNode, err := compiled.FetchString(word)
+		require.NoError(t, err)
+		actualNode, err := reloaded.FetchString(word)
+		require.NoError(t, err)
+		require.Equal(t, len(expectedNode.TagSets()), len(actualNode.TagSets()), word)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for _, format := range export.Formats {
+		parsed, err := export.ParseFormat(strings.ToUpper(string(format)))
+		require.NoError(t, err)
+		require.Equal(t, format, parsed)
+	}
+
+	_, err := export.ParseFormat("csv")
+	require.ErrorIs(t, err, export.ErrUnknownFormat)
+	require.ErrorIs(t, export.Write(new(bytes.Buffer), new(index.ReadOnlyIndex), "csv"), export.ErrUnknownFormat)
+}
@@ -0,0 +1,33 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// jsonWord defines JSON Lines record layout.
+type jsonWord struct {
+	Word   string          `json:"word"`
+	Tags   []dag.TagName   `json:"tags"`
+	Lemmas []index.LemmaID `json:"lemmas,omitempty"`
+}
+
+// writeJSONL writes JSON object line per word tag set:
+//
+//	{"word":"ёж","tags":["NOUN","anim","masc","sing","nomn"],"lemmas":[1]}
+func writeJSONL(writer *bufio.Writer, idx *index.ReadOnlyIndex) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	return idx.WalkWords(func(entry index.WordEntry) error {
+		tags := make([]dag.TagName, len(entry.TagSet))
+		for tagIdx, tag := range entry.TagSet {
+			tags[tagIdx] = tag.Name
+		}
+
+		return encoder.Encode(jsonWord{Word: entry.Word, Tags: tags, Lemmas: entry.Lemmas})
+	})
+}
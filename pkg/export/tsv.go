@@ -0,0 +1,19 @@
+package export
+
+import (
+	"bufio"
+
+	"github.com/amarin/gomorphy/internal/index"
+)
+
+// writeTSV writes line per word tag set: word, comma separated tags and comma separated lemma IDs
+// separated by tabs. Lemma IDs column is empty if no lemma has word form.
+//
+//	ёж	NOUN,anim,masc,sing,nomn	1
+func writeTSV(writer *bufio.Writer, idx *index.ReadOnlyIndex) error {
+	return idx.WalkWords(func(entry index.WordEntry) error {
+		_, err := writer.WriteString(entry.Word + "\t" + entry.TagSet.String() + "\t" + lemmaIDs(entry.Lemmas) + "\n")
+
+		return err
+	})
+}
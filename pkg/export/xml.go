@@ -0,0 +1,153 @@
+package export
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// writeXML writes OpenCorpora XML dictionary having index grammemes and lemmas.
+// Lemma tags are the tags shared by all lemma forms, stored lemma normal form is written as lemma text
+// and as the first lemma form, so compiled dictionary keeps it. Words not referred by any lemma are not written
+// unless index has no lemmas at all, then every word tag set is written as separate lemma.
+// Dictionary compiled from written XML is the same as exported one.
+func writeXML(writer *bufio.Writer, idx *index.ReadOnlyIndex) error {
+	if _, err := writer.WriteString(xml.Header + "<dictionary version=\"0.92\" revision=\"0\">\n"); err != nil {
+		return err
+	}
+
+	if err := writeXMLGrammemes(writer, idx.Tags()); err != nil {
+		return err
+	}
+
+	if _, err := writer.WriteString("<lemmata>\n"); err != nil {
+		return err
+	}
+
+	var err error
+	if idx.LemmasCount() > 0 {
+		err = idx.WalkLemmas(func(lemma index.LemmaEntry) error {
+			return writeXMLLemma(writer, lemma)
+		})
+	} else {
+		id := index.LemmaID(0)
+		err = idx.WalkWords(func(entry index.WordEntry) error {
+			id++
+
+			return writeXMLLemma(writer, index.LemmaEntry{ID: id, Revision: 0, Forms: []index.WordEntry{entry}})
+		})
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.WriteString("</lemmata>\n</dictionary>\n")
+
+	return err
+}
+
+// writeXMLGrammemes writes grammemes definitions.
+func writeXMLGrammemes(writer *bufio.Writer, tags dag.Idx) error {
+	if _, err := writer.WriteString("<grammemes>\n"); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		parent := tag.Parent
+		if parent == dag.EmptyTagName {
+			parent = ""
+		}
+
+		if _, err := fmt.Fprintf(writer, "<grammeme parent=\"%s\"><name>%s</name></grammeme>\n",
+			escapeXML(string(parent)), escapeXML(string(tag.Name))); err != nil {
+			return err
+		}
+	}
+
+	_, err := writer.WriteString("</grammemes>\n")
+
+	return err
+}
+
+// writeXMLLemma writes single lemma having lemma tags shared by all forms.
+// Lemma entry lists normal form first, so its word is written as lemma text.
+func writeXMLLemma(writer *bufio.Writer, lemma index.LemmaEntry) error {
+	lemmaTags, normalForm := dag.TagSet(nil), ""
+	if len(lemma.Forms) > 0 {
+		lemmaTags, normalForm = sharedTags(lemma.Forms), lemma.Forms[0].Word
+	}
+
+	if _, err := fmt.Fprintf(writer, "<lemma id=\"%d\" rev=\"%d\"><l t=\"%s\">%s</l>",
+		lemma.ID, lemma.Revision, escapeXML(normalForm), xmlTags(lemmaTags, nil)); err != nil {
+		return err
+	}
+
+	for _, form := range lemma.Forms {
+		if _, err := fmt.Fprintf(writer, "<f t=\"%s\">%s</f>",
+			escapeXML(form.Word), xmlTags(form.TagSet, lemmaTags)); err != nil {
+			return err
+		}
+	}
+
+	_, err := writer.WriteString("</lemma>\n")
+
+	return err
+}
+
+// sharedTags returns tags of the first form found in all forms.
+func sharedTags(forms []index.WordEntry) dag.TagSet {
+	shared := make(dag.TagSet, 0, len(forms[0].TagSet))
+
+	for _, tag := range forms[0].TagSet {
+		found := true
+		for _, form := range forms[1:] {
+			if !hasTag(form.TagSet, tag.Name) {
+				found = false
+				break
+			}
+		}
+
+		if found {
+			shared = append(shared, tag)
+		}
+	}
+
+	return shared
+}
+
+// hasTag reports whether tag set has tag of specified name.
+func hasTag(tagSet dag.TagSet, name dag.TagName) bool {
+	for _, tag := range tagSet {
+		if tag.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// xmlTags returns grammeme elements of tags not found in excluded tags.
+func xmlTags(tagSet dag.TagSet, excluded dag.TagSet) string {
+	res := new(strings.Builder)
+
+	for _, tag := range tagSet {
+		if !hasTag(excluded, tag.Name) {
+			res.WriteString("<g v=\"" + escapeXML(string(tag.Name)) + "\"/>")
+		}
+	}
+
+	return res.String()
+}
+
+// escapeXML returns text having XML special characters escaped.
+func escapeXML(text string) string {
+	escaped := new(strings.Builder)
+	_ = xml.EscapeText(escaped, []byte(text))
+
+	return escaped.String()
+}
@@ -0,0 +1,6 @@
+package hunspell
+
+// Package hunspell implements Hunspell dictionaries import.
+// Dictionary words of .dic file are expanded into forms using prefix and suffix rules of .aff file,
+// Hunspell flags are mapped onto grammemes by configurable Mapping.
+// Dictionary implements source.Source, so it is compiled into the same index structures as OpenCorpora dictionary.
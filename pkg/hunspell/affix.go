@@ -0,0 +1,384 @@
+package hunspell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// FlagMode defines how flags are encoded in dictionary and affix files.
+type FlagMode string
+
+// Known flag modes.
+const (
+	FlagChar FlagMode = ""      // single character flags, Hunspell default
+	FlagLong FlagMode = "long"  // two characters flags
+	FlagNum  FlagMode = "num"   // comma separated decimal flags
+	FlagUTF8 FlagMode = "UTF-8" // single Unicode character flags
+)
+
+// parseFlags splits flags string into flags using specified mode.
+func (mode FlagMode) parseFlags(flags string) ([]string, error) {
+	if flags == "" {
+		return nil, nil
+	}
+
+	switch mode {
+	case FlagLong:
+		runes := []rune(flags)
+		if len(runes)%2 != 0 {
+			return nil, fmt.Errorf("odd long flags length: %v", flags)
+		}
+
+		result := make([]string, 0, len(runes)/2)
+		for idx := 0; idx < len(runes); idx += 2 {
+			result = append(result, string(runes[idx:idx+2]))
+		}
+
+		return result, nil
+	case FlagNum:
+		result := strings.Split(flags, ",")
+		for _, flag := range result {
+			if _, err := strconv.ParseUint(flag, 10, 16); err != nil {
+				return nil, fmt.Errorf("numeric flag: %v", flag)
+			}
+		}
+
+		return result, nil
+	default:
+		result := make([]string, 0, len(flags))
+		for _, flag := range flags {
+			result = append(result, string(flag))
+		}
+
+		return result, nil
+	}
+}
+
+// conditionChar matches single character of affix condition.
+type conditionChar struct {
+	any    bool   // matches any character
+	negate bool   // matches any character except listed
+	runes  []rune // matching characters
+}
+
+// match reports whether character matches condition character.
+func (char conditionChar) match(letter rune) bool {
+	if char.any {
+		return true
+	}
+
+	for _, known := range char.runes {
+		if known == letter {
+			return !char.negate
+		}
+	}
+
+	return char.negate
+}
+
+// condition provides affix rule condition, a simplified regular expression of characters, [...] and [^...] groups.
+type condition []conditionChar
+
+// parseCondition parses affix rule condition. Condition "." matches any word.
+func parseCondition(text string) (condition, error) {
+	if text == "." {
+		return nil, nil
+	}
+
+	result := make(condition, 0)
+	runes := []rune(text)
+
+	for idx := 0; idx < len(runes); idx++ {
+		switch runes[idx] {
+		case '.':
+			result = append(result, conditionChar{any: true, negate: false, runes: nil})
+		case '[':
+			end := idx + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+
+			if end == len(runes) {
+				return nil, fmt.Errorf("condition %v: unclosed group", text)
+			}
+
+			group := runes[idx+1 : end]
+			negate := len(group) > 0 && group[0] == '^'
+			if negate {
+				group = group[1:]
+			}
+
+			result = append(result, conditionChar{any: false, negate: negate, runes: group})
+			idx = end
+		default:
+			result = append(result, conditionChar{any: false, negate: false, runes: []rune{runes[idx]}})
+		}
+	}
+
+	return result, nil
+}
+
+// matchEnd reports whether word ends with characters matching condition.
+func (cond condition) matchEnd(word []rune) bool {
+	if len(word) < len(cond) {
+		return false
+	}
+
+	offset := len(word) - len(cond)
+	for idx, char := range cond {
+		if !char.match(word[offset+idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchStart reports whether word starts with characters matching condition.
+func (cond condition) matchStart(word []rune) bool {
+	if len(word) < len(cond) {
+		return false
+	}
+
+	for idx, char := range cond {
+		if !char.match(word[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AffixRule provides single prefix or suffix rule.
+type AffixRule struct {
+	Strip        string    // characters removed from word
+	Add          string    // characters added to word
+	Continuation []string  // flags applied to word produced by rule
+	condition    condition // condition word must match before stripping
+}
+
+// AffixClass provides prefix or suffix rules set identified by flag.
+type AffixClass struct {
+	Flag         string      // class flag
+	Prefix       bool        // class rules add prefixes, otherwise suffixes
+	CrossProduct bool        // class forms can be combined with opposite affix class forms
+	Rules        []AffixRule // class rules
+	expected     int         // count of rules declared by class header
+}
+
+// apply returns word produced by rule or false if rule not applicable to word.
+func (class *AffixClass) apply(rule AffixRule, word string) (string, bool) {
+	runes := []rune(word)
+
+	if class.Prefix {
+		if !strings.HasPrefix(word, rule.Strip) || !rule.condition.matchStart(runes) {
+			return "", false
+		}
+
+		return rule.Add + strings.TrimPrefix(word, rule.Strip), true
+	}
+
+	if !strings.HasSuffix(word, rule.Strip) || !rule.condition.matchEnd(runes) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(word, rule.Strip) + rule.Add, true
+}
+
+// Affixes provides affix file data.
+type Affixes struct {
+	Encoding      string                 // dictionary and affix files encoding
+	FlagMode      FlagMode               // flags encoding
+	Aliases       [][]string             // flag vectors aliases, dictionary refers them by 1-based number
+	Classes       map[string]*AffixClass // affix classes by flags
+	NeedAffix     string                 // flag of words valid only with affixes
+	ForbiddenWord string                 // flag of forbidden words
+}
+
+// affixesEncoding returns charset name set by SET directive or empty string.
+func affixesEncoding(data []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[0] == "SET" {
+			return fields[1]
+		}
+	}
+
+	return ""
+}
+
+// ReadAffix reads Hunspell affix file data.
+// Supported directives are SET, FLAG, AF, PFX, SFX, NEEDAFFIX and FORBIDDENWORD, others are ignored.
+func ReadAffix(reader io.Reader) (*Affixes, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read affix: %v", Error, err)
+	}
+
+	affixes := &Affixes{
+		Encoding:      affixesEncoding(data),
+		FlagMode:      FlagChar,
+		Aliases:       nil,
+		Classes:       make(map[string]*AffixClass),
+		NeedAffix:     "",
+		ForbiddenWord: "",
+	}
+
+	text, err := decode(data, affixes.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read affix: %v", Error, err)
+	}
+
+	for lineIdx, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if err = affixes.parseDirective(fields); err != nil {
+			return nil, fmt.Errorf("%w: read affix: line %d: %v", Error, lineIdx+1, err)
+		}
+	}
+
+	for flag, class := range affixes.Classes {
+		if len(class.Rules) != class.expected {
+			return nil, fmt.Errorf("%w: read affix: class %v: %d rules of %d", Error, flag, len(class.Rules), class.expected)
+		}
+	}
+
+	return affixes, nil
+}
+
+// parseDirective applies single affix file directive.
+func (affixes *Affixes) parseDirective(fields []string) error {
+	switch fields[0] {
+	case "FLAG":
+		if len(fields) < 2 {
+			return fmt.Errorf("FLAG: no mode")
+		}
+
+		switch mode := FlagMode(fields[1]); mode {
+		case FlagLong, FlagNum, FlagUTF8:
+			affixes.FlagMode = mode
+		default:
+			return fmt.Errorf("FLAG: unknown mode: %v", fields[1])
+		}
+	case "AF":
+		if len(fields) < 2 {
+			return fmt.Errorf("AF: no flags")
+		}
+
+		if affixes.Aliases == nil {
+			// first AF line holds aliases count
+			affixes.Aliases = make([][]string, 0)
+			if _, err := strconv.Atoi(fields[1]); err == nil {
+				return nil
+			}
+		}
+
+		flags, err := affixes.FlagMode.parseFlags(fields[1])
+		if err != nil {
+			return fmt.Errorf("AF: %v", err)
+		}
+
+		affixes.Aliases = append(affixes.Aliases, flags)
+	case "NEEDAFFIX", "PSEUDOROOT":
+		if len(fields) > 1 {
+			affixes.NeedAffix = fields[1]
+		}
+	case "FORBIDDENWORD":
+		if len(fields) > 1 {
+			affixes.ForbiddenWord = fields[1]
+		}
+	case "PFX", "SFX":
+		return affixes.parseAffix(fields)
+	}
+
+	return nil
+}
+
+// parseAffix parses PFX or SFX class header or rule.
+func (affixes *Affixes) parseAffix(fields []string) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("%v: too few fields", fields[0])
+	}
+
+	flag := fields[1]
+	class, known := affixes.Classes[flag]
+
+	if !known || len(class.Rules) == class.expected {
+		if known {
+			return fmt.Errorf("%v %v: class redefined", fields[0], flag)
+		}
+
+		expected, err := strconv.Atoi(fields[3])
+		if err != nil || expected < 0 {
+			return fmt.Errorf("%v %v: rules count: %v", fields[0], flag, fields[3])
+		}
+
+		affixes.Classes[flag] = &AffixClass{
+			Flag:         flag,
+			Prefix:       fields[0] == "PFX",
+			CrossProduct: fields[2] == "Y",
+			Rules:        make([]AffixRule, 0, expected),
+			expected:     expected,
+		}
+
+		return nil
+	}
+
+	if class.Prefix != (fields[0] == "PFX") {
+		return fmt.Errorf("%v %v: class type mismatch", fields[0], flag)
+	}
+
+	rule := AffixRule{Strip: fields[2], Add: fields[3], Continuation: nil, condition: nil}
+	if rule.Strip == "0" {
+		rule.Strip = ""
+	}
+
+	if slash := strings.Index(rule.Add, "/"); slash >= 0 {
+		continuation, err := affixes.parseFlagsField(rule.Add[slash+1:])
+		if err != nil {
+			return fmt.Errorf("%v %v: continuation: %v", fields[0], flag, err)
+		}
+
+		rule.Add, rule.Continuation = rule.Add[:slash], continuation
+	}
+
+	if rule.Add == "0" {
+		rule.Add = ""
+	}
+
+	if len(fields) > 4 {
+		cond, err := parseCondition(fields[4])
+		if err != nil {
+			return fmt.Errorf("%v %v: %v", fields[0], flag, err)
+		}
+
+		rule.condition = cond
+	}
+
+	class.Rules = append(class.Rules, rule)
+
+	return nil
+}
+
+// parseFlagsField parses flags field of dictionary word or affix rule continuation.
+// If aliases defined flags field holds alias number.
+func (affixes *Affixes) parseFlagsField(field string) ([]string, error) {
+	if affixes.Aliases == nil {
+		return affixes.FlagMode.parseFlags(field)
+	}
+
+	aliasNumber, err := strconv.Atoi(field)
+	if err != nil || aliasNumber < 1 || aliasNumber > len(affixes.Aliases) {
+		return nil, fmt.Errorf("unknown flags alias: %v", field)
+	}
+
+	return affixes.Aliases[aliasNumber-1], nil
+}
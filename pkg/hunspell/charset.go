@@ -0,0 +1,59 @@
+package hunspell
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// charsets maps normalized Hunspell SET names to upper halves of supported 8-bit charsets.
+// ISO8859-1 upper half equals Unicode code points, so it has no table.
+var charsets = map[string]*[128]rune{
+	"KOI8R":           &koi8RUpper,
+	"KOI8U":           &koi8UUpper,
+	"CP1251":          &cp1251Upper,
+	"MICROSOFTCP1251": &cp1251Upper,
+	"WINDOWS1251":     &cp1251Upper,
+	"ISO88595":        &iso88595Upper,
+}
+
+// normalizeCharset returns charset name in upper case without separators.
+func normalizeCharset(name string) string {
+	return strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToUpper(name))
+}
+
+// decode returns data of specified charset as UTF-8 string.
+// Hunspell treats files without SET as ISO8859-1.
+func decode(data []byte, charset string) (string, error) {
+	switch name := normalizeCharset(charset); name {
+	case "UTF8":
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("%w: invalid UTF-8 data", ErrEncoding)
+		}
+
+		return string(data), nil
+	case "", "ISO88591":
+		runes := make([]rune, len(data))
+		for idx, char := range data {
+			runes[idx] = rune(char)
+		}
+
+		return string(runes), nil
+	default:
+		upper, ok := charsets[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %v", ErrEncoding, charset)
+		}
+
+		runes := make([]rune, len(data))
+		for idx, char := range data {
+			if char < 0x80 {
+				runes[idx] = rune(char)
+			} else {
+				runes[idx] = upper[char-0x80]
+			}
+		}
+
+		return string(runes), nil
+	}
+}
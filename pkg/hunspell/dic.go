@@ -0,0 +1,91 @@
+package hunspell
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Entry provides dictionary word with its flags.
+type Entry struct {
+	Word  string   // dictionary word
+	Flags []string // word flags
+}
+
+// hasFlag reports whether entry has specified flag. Empty flag is never found.
+func (entry Entry) hasFlag(flag string) bool {
+	if flag == "" {
+		return false
+	}
+
+	for _, known := range entry.Flags {
+		if known == flag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReadDic reads Hunspell dictionary file data using encoding and flags of specified affixes.
+// First line holding words count is skipped, morphological fields following words are ignored.
+func ReadDic(reader io.Reader, affixes *Affixes) ([]Entry, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read dictionary: %v", Error, err)
+	}
+
+	text, err := decode(data, affixes.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("%w: read dictionary: %v", Error, err)
+	}
+
+	lines := strings.Split(text, "\n")
+	entries := make([]Entry, 0, len(lines))
+
+	for lineIdx, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if lineIdx == 0 {
+			if _, err = strconv.Atoi(fields[0]); err == nil {
+				continue
+			}
+		}
+
+		entry, err := affixes.parseEntry(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: read dictionary: line %d: %v", Error, lineIdx+1, err)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseEntry parses dictionary word having optional flags separated by slash. Escaped slash belongs to word.
+func (affixes *Affixes) parseEntry(field string) (Entry, error) {
+	slash := -1
+
+	for idx := 0; idx < len(field); idx++ {
+		if field[idx] == '/' && (idx == 0 || field[idx-1] != '\\') {
+			slash = idx
+			break
+		}
+	}
+
+	if slash < 0 {
+		return Entry{Word: strings.ReplaceAll(field, `\/`, "/"), Flags: nil}, nil
+	}
+
+	flags, err := affixes.parseFlagsField(field[slash+1:])
+	if err != nil {
+		return Entry{Word: "", Flags: nil}, fmt.Errorf("%v: %v", field, err)
+	}
+
+	return Entry{Word: strings.ReplaceAll(field[:slash], `\/`, "/"), Flags: flags}, nil
+}
@@ -0,0 +1,231 @@
+package hunspell
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
+)
+
+// maxSuffixDepth limits suffixes applied one after another using rules continuation flags.
+const maxSuffixDepth = 2
+
+// Dictionary provides Hunspell dictionary words, affix rules and flags mapping onto grammemes.
+type Dictionary struct {
+	Affixes *Affixes
+	Entries []Entry
+	Mapping *Mapping
+}
+
+// LoadFiles loads Dictionary from specified dictionary and affix files.
+// If mapping is nil DefaultMapping is used.
+func LoadFiles(dicPath string, affPath string, mapping *Mapping) (dictionary *Dictionary, err error) {
+	var affFile, dicFile *os.File
+
+	if mapping == nil {
+		mapping = DefaultMapping()
+	}
+
+	dictionary = &Dictionary{Affixes: nil, Entries: nil, Mapping: mapping}
+
+	if affFile, err = os.Open(affPath); err != nil {
+		return nil, fmt.Errorf("%w: load: %v", Error, err)
+	}
+
+	defer func() { _ = affFile.Close() }()
+
+	if dictionary.Affixes, err = ReadAffix(affFile); err != nil {
+		return nil, fmt.Errorf("%w: load %v: %v", Error, affPath, err)
+	}
+
+	if dicFile, err = os.Open(dicPath); err != nil {
+		return nil, fmt.Errorf("%w: load: %v", Error, err)
+	}
+
+	defer func() { _ = dicFile.Close() }()
+
+	if dictionary.Entries, err = ReadDic(dicFile, dictionary.Affixes); err != nil {
+		return nil, fmt.Errorf("%w: load %v: %v", Error, dicPath, err)
+	}
+
+	return dictionary, nil
+}
+
+// expansion provides word form produced by affix rules with flags of applied affix classes.
+type expansion struct {
+	word  string   // form text
+	flags []string // flags of applied affix classes
+	cross bool     // form can be combined with cross product prefixes
+}
+
+// expand returns entry forms: entry word itself unless it needs affix, suffixed forms including
+// suffixes added by rules continuation flags, prefixed forms and cross products of prefixes and suffixed forms.
+func (affixes *Affixes) expand(entry Entry) []expansion {
+	base := expansion{word: entry.Word, flags: nil, cross: true}
+	suffixed := affixes.applyClasses(base, entry.Flags, false, maxSuffixDepth)
+	result := make([]expansion, 0, 1+len(suffixed))
+
+	if !entry.hasFlag(affixes.NeedAffix) {
+		result = append(result, base)
+	}
+
+	for _, form := range suffixed {
+		if !hasFlag(form.flags, affixes.NeedAffix) {
+			result = append(result, form)
+		}
+	}
+
+	for _, form := range append([]expansion{base}, suffixed...) {
+		result = append(result, affixes.applyClasses(form, entry.Flags, true, 1)...)
+	}
+
+	return result
+}
+
+// applyClasses returns forms produced from specified form by prefix or suffix classes of specified flags.
+// Prefixes are added to suffixed forms only if both classes allow cross product.
+// Suffixes having continuation flags are followed by continuation suffixes until depth reached.
+func (affixes *Affixes) applyClasses(from expansion, flags []string, prefix bool, depth int) []expansion {
+	result := make([]expansion, 0)
+
+	for _, flag := range flags {
+		class, ok := affixes.Classes[flag]
+		if !ok || class.Prefix != prefix || (len(from.flags) > 0 && prefix && !(class.CrossProduct && from.cross)) {
+			continue
+		}
+
+		for _, rule := range class.Rules {
+			word, applicable := class.apply(rule, from.word)
+			if !applicable {
+				continue
+			}
+
+			form := expansion{
+				word:  word,
+				flags: append(append(make([]string, 0, len(from.flags)+1), from.flags...), flag),
+				cross: from.cross && class.CrossProduct,
+			}
+
+			if hasFlag(rule.Continuation, affixes.NeedAffix) {
+				form.flags = append(form.flags, affixes.NeedAffix)
+			}
+
+			result = append(result, form)
+
+			if !prefix && depth > 1 && len(rule.Continuation) > 0 {
+				result = append(result, affixes.applyClasses(form, rule.Continuation, false, depth-1)...)
+			}
+		}
+	}
+
+	return result
+}
+
+// hasFlag reports whether flags contain specified flag. Empty flag is never found.
+func hasFlag(flags []string, flag string) bool {
+	return Entry{Word: "", Flags: flags}.hasFlag(flag)
+}
+
+// Lemma returns entry expanded into lemma forms tagged using dictionary mapping.
+// Words flags define lemma tags, form tags are defined by base tags or applied affix classes flags.
+// Forms having no tags at all get mapping default tags. Returns ErrNoTags if no default tags set.
+func (dictionary Dictionary) Lemma(entry Entry) (source.Lemma, error) {
+	lemmaTags := make([]dag.TagName, 0)
+	for _, flag := range entry.Flags {
+		lemmaTags = appendTags(lemmaTags, nil, dictionary.Mapping.Words[flag]...)
+	}
+
+	expansions := dictionary.Affixes.expand(entry)
+	forms := make([]source.Form, 0, len(expansions))
+
+	for _, expanded := range expansions {
+		formTags := make([]dag.TagName, 0)
+		if len(expanded.flags) == 0 {
+			formTags = appendTags(formTags, lemmaTags, dictionary.Mapping.Base...)
+		}
+
+		for _, flag := range expanded.flags {
+			formTags = appendTags(formTags, lemmaTags, dictionary.Mapping.Affixes[flag]...)
+		}
+
+		if len(lemmaTags) == 0 && len(formTags) == 0 {
+			if len(dictionary.Mapping.Default) == 0 {
+				return source.Lemma{ID: 0, Revision: 0, Tags: nil, Forms: nil}, fmt.Errorf("%w: %v: form %v", ErrNoTags, entry.Word, expanded.word)
+			}
+
+			formTags = appendTags(formTags, nil, dictionary.Mapping.Default...)
+		}
+
+		forms = append(forms, source.Form{Word: expanded.word, Tags: formTags})
+	}
+
+	return source.Lemma{ID: 0, Revision: 0, Tags: lemmaTags, Forms: forms}, nil
+}
+
+// appendTags appends tags not found in tags and excluded lists yet.
+func appendTags(tags []dag.TagName, excluded []dag.TagName, names ...dag.TagName) []dag.TagName {
+	for _, name := range names {
+		if !hasTag(tags, name) && !hasTag(excluded, name) {
+			tags = append(tags, name)
+		}
+	}
+
+	return tags
+}
+
+// hasTag reports whether tags list contains specified tag.
+func hasTag(tags []dag.TagName, name dag.TagName) bool {
+	for _, tag := range tags {
+		if tag == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Read passes mapping tags and expanded dictionary words to handler. Implements source.Source.
+// Hunspell words have no IDs, so their forms are indexed without lemmas registration.
+// Forbidden words are skipped.
+func (dictionary Dictionary) Read(handler source.Handler) error {
+	for _, tag := range dictionary.Mapping.tags() {
+		if err := handler.Tag(tag); err != nil {
+			return fmt.Errorf("%w: tag %v: %v", Error, tag.Name, err)
+		}
+	}
+
+	for _, entry := range dictionary.Entries {
+		if entry.hasFlag(dictionary.Affixes.ForbiddenWord) {
+			continue
+		}
+
+		lemma, err := dictionary.Lemma(entry)
+		if err != nil {
+			return err
+		}
+
+		if err = handler.Lemma(lemma); err != nil {
+			return fmt.Errorf("%w: add %v: %v", Error, entry.Word, err)
+		}
+	}
+
+	return nil
+}
+
+// Compile makes read-only index from dictionary using specified count of workers filling index.
+func (dictionary Dictionary) Compile(workers int) (*index.ReadOnlyIndex, error) {
+	builder := index.NewBuilder()
+
+	if err := source.Fill(builder, dictionary, workers); err != nil {
+		return nil, fmt.Errorf("%w: compile: %v", Error, err)
+	}
+
+	compiled, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("%w: compile: %v", Error, err)
+	}
+
+	return compiled, nil
+}
@@ -0,0 +1,17 @@
+package hunspell
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// Error identifies Hunspell dictionaries errors.
+	Error = errors.New("hunspell")
+
+	// ErrEncoding indicates dictionary encoding not supported.
+	ErrEncoding = fmt.Errorf("%w: unsupported encoding", Error)
+
+	// ErrNoTags indicates dictionary form having no grammemes mapped.
+	ErrNoTags = fmt.Errorf("%w: no tags", Error)
+)
@@ -0,0 +1,181 @@
+package hunspell_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/hunspell"
+)
+
+func tagSetsStrings(node dag.Node) (res []string) {
+	for _, tagSet := range node.TagSets() {
+		res = append(res, tagSet.String())
+	}
+
+	return res
+}
+
+func loadMapping(t *testing.T) *hunspell.Mapping {
+	t.Helper()
+
+	file, err := os.Open("testdata/mapping.json")
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	mapping, err := hunspell.ReadMapping(file)
+	require.NoError(t, err)
+
+	return mapping
+}
+
+func TestReadAffix(t *testing.T) {
+	file, err := os.Open("testdata/test.aff")
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	affixes, err := hunspell.ReadAffix(file)
+	require.NoError(t, err)
+	require.Equal(t, "UTF-8", affixes.Encoding)
+	require.Equal(t, "!", affixes.NeedAffix)
+	require.Equal(t, "*", affixes.ForbiddenWord)
+	require.Len(t, affixes.Classes, 6)
+	require.Len(t, affixes.Classes["A"].Rules, 2)
+	require.True(t, affixes.Classes["A"].CrossProduct)
+	require.False(t, affixes.Classes["C"].CrossProduct)
+	require.True(t, affixes.Classes["P"].Prefix)
+	require.Equal(t, "ь", affixes.Classes["C"].Rules[0].Strip)
+	require.Equal(t, "ок", affixes.Classes["D"].Rules[0].Add)
+	require.Equal(t, []string{"E"}, affixes.Classes["D"].Rules[0].Continuation)
+}
+
+func TestReadAffix_Errors(t *testing.T) {
+	for _, data := range []string{
+		"SET CP437\n",
+		"FLAG short\n",
+		"SFX A Y 2\nSFX A 0 ы .\n",
+		"SFX A Y many\n",
+		"SFX A Y 1\nSFX A 0 ы [ab\n",
+		"SFX A Y 1\nPFX A 0 не .\n",
+		"SFX A Y 0\nSFX A 0 ы .\n",
+		"SET UTF-8\nSFX A Y 1\nSFX A 0 \xff .\n",
+	} {
+		_, err := hunspell.ReadAffix(strings.NewReader(data))
+		require.ErrorIs(t, err, hunspell.Error, data)
+	}
+}
+
+func TestReadDic_FlagModes(t *testing.T) {
+	for _, testCase := range []struct {
+		affix    string
+		dic      string
+		expected []hunspell.Entry
+	}{
+		{
+			affix:    "SET UTF-8\nFLAG long\n",
+			dic:      "2\nкот/AaBb\nмир\n",
+			expected: []hunspell.Entry{{Word: "кот", Flags: []string{"Aa", "Bb"}}, {Word: "мир", Flags: nil}},
+		},
+		{
+			affix:    "SET UTF-8\nFLAG num\n",
+			dic:      "1\nкот/1,22\tpo:noun\n",
+			expected: []hunspell.Entry{{Word: "кот", Flags: []string{"1", "22"}}},
+		},
+		{
+			affix:    "SET UTF-8\nFLAG UTF-8\n",
+			dic:      "1\nкот/Жж\n",
+			expected: []hunspell.Entry{{Word: "кот", Flags: []string{"Ж", "ж"}}},
+		},
+		{
+			affix:    "SET UTF-8\nAF 2\nAF AB\nAF C\n",
+			dic:      "2\nкот/1\n1\\/2/2\n",
+			expected: []hunspell.Entry{{Word: "кот", Flags: []string{"A", "B"}}, {Word: "1/2", Flags: []string{"C"}}},
+		},
+	} {
+		affixes, err := hunspell.ReadAffix(strings.NewReader(testCase.affix))
+		require.NoError(t, err, testCase.affix)
+
+		entries, err := hunspell.ReadDic(strings.NewReader(testCase.dic), affixes)
+		require.NoError(t, err, testCase.affix)
+		require.Equal(t, testCase.expected, entries, testCase.affix)
+	}
+
+	for _, testCase := range []struct{ affix, dic string }{
+		{affix: "SET UTF-8\nFLAG long\n", dic: "кот/A\n"},
+		{affix: "SET UTF-8\nFLAG num\n", dic: "кот/A\n"},
+		{affix: "SET UTF-8\nAF 1\nAF AB\n", dic: "кот/2\n"},
+	} {
+		affixes, err := hunspell.ReadAffix(strings.NewReader(testCase.affix))
+		require.NoError(t, err, testCase.affix)
+
+		_, err = hunspell.ReadDic(strings.NewReader(testCase.dic), affixes)
+		require.ErrorIs(t, err, hunspell.Error, testCase.affix)
+	}
+}
+
+func TestLoadFiles_Compile(t *testing.T) {
+	mapping := loadMapping(t)
+
+	for _, files := range [][2]string{
+		{"testdata/test.dic", "testdata/test.aff"},
+		{"testdata/koi8.dic", "testdata/koi8.aff"},
+	} {
+		dictionary, err := hunspell.LoadFiles(files[0], files[1], mapping)
+		require.NoError(t, err, files[0])
+		require.Len(t, dictionary.Entries, 8)
+
+		for _, workers := range []int{1, 2} {
+			compiled, err := dictionary.Compile(workers)
+			require.NoError(t, err, files[0])
+			require.Equal(t, 16, compiled.WordsCount(), files[0])
+
+			for word, expected := range map[string][]string{
+				"кот":       {"NOUN,sing"},
+				"коты":      {"NOUN,plur"},
+				"котов":     {"NOUN,plur,gent"},
+				"друг":      {"NOUN,sing"},
+				"други":     {"NOUN,plur"},
+				"недруг":    {"NOUN,NEG"},
+				"недруги":   {"NOUN,plur,NEG"},
+				"печи":      {"NOUN,plur"},
+				"хороший":   {"ADJF,sing"},
+				"нехороший": {"ADJF,NEG"},
+				"шторы":     {"NOUN,plur"},
+				"штор":      nil, // needs affix
+				"лист":      {"sing"},
+				"листок":    {"UNKN"},
+				"листоки":   {"UNKN"},
+				"мир":       {"sing"},
+			} {
+				node, err := compiled.FetchString(word)
+				require.NoError(t, err, word)
+				require.Equal(t, expected, tagSetsStrings(node), word)
+			}
+
+			_, err = compiled.FetchString("плохой") // forbidden
+			require.Error(t, err)
+		}
+	}
+}
+
+func TestDictionary_Lemma(t *testing.T) {
+	dictionary, err := hunspell.LoadFiles("testdata/test.dic", "testdata/test.aff", nil)
+	require.NoError(t, err)
+
+	lemma, err := dictionary.Lemma(hunspell.Entry{Word: "кот", Flags: []string{"A", "B"}})
+	require.NoError(t, err)
+	require.Len(t, lemma.Forms, 3)
+	require.Equal(t, []dag.TagName{hunspell.UnknownTag}, lemma.Forms[2].Tags) // default mapping
+
+	dictionary.Mapping = &hunspell.Mapping{Tags: nil, Words: nil, Affixes: nil, Base: nil, Default: nil}
+	_, err = dictionary.Lemma(hunspell.Entry{Word: "кот", Flags: nil})
+	require.ErrorIs(t, err, hunspell.ErrNoTags)
+
+	_, err = dictionary.Compile(1)
+	require.ErrorIs(t, err, hunspell.Error)
+}
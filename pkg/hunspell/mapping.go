@@ -0,0 +1,127 @@
+package hunspell
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// UnknownTag names tag default Mapping sets to forms having no mapped tags.
+const UnknownTag dag.TagName = "UNKN"
+
+// Mapping maps Hunspell flags onto grammemes.
+// Words flags map onto tags shared by all word forms, affix flags map onto tags of forms produced by affix.
+// Forms having no tags mapped get default tags.
+type Mapping struct {
+	Tags    []dag.Tag                // tags introduced by mapping with their parents
+	Words   map[string][]dag.TagName // tags of all forms of dictionary words having flag
+	Affixes map[string][]dag.TagName // tags of forms produced by affix class flag
+	Base    []dag.TagName            // tags of dictionary word form itself
+	Default []dag.TagName            // tags of forms having no tags mapped
+}
+
+// DefaultMapping returns mapping marking all forms by UnknownTag.
+func DefaultMapping() *Mapping {
+	return &Mapping{
+		Tags:    []dag.Tag{*dag.NewTag(dag.EmptyTagName, UnknownTag)},
+		Words:   nil,
+		Affixes: nil,
+		Base:    nil,
+		Default: []dag.TagName{UnknownTag},
+	}
+}
+
+// tags returns tags to register: mapping tags first then tags used by mapping but not defined, without parent.
+func (mapping *Mapping) tags() []dag.Tag {
+	result := make([]dag.Tag, 0, len(mapping.Tags))
+	known := make(map[dag.TagName]bool)
+
+	add := func(tag dag.Tag) {
+		if !known[tag.Name] {
+			known[tag.Name] = true
+			result = append(result, tag)
+		}
+	}
+
+	for _, tag := range mapping.Tags {
+		add(tag)
+	}
+
+	used := [][]dag.TagName{mapping.Base, mapping.Default}
+	for _, flag := range sortedKeys(mapping.Words) {
+		used = append(used, mapping.Words[flag])
+	}
+
+	for _, flag := range sortedKeys(mapping.Affixes) {
+		used = append(used, mapping.Affixes[flag])
+	}
+
+	for _, names := range used {
+		for _, name := range names {
+			add(*dag.NewTag(dag.EmptyTagName, name))
+		}
+	}
+
+	return result
+}
+
+// jsonMapping defines JSON mapping layout.
+type jsonMapping struct {
+	Tags []struct {
+		Name   dag.TagName `json:"name"`
+		Parent dag.TagName `json:"parent,omitempty"`
+	} `json:"tags,omitempty"`
+	Words   map[string][]dag.TagName `json:"words,omitempty"`
+	Affixes map[string][]dag.TagName `json:"affixes,omitempty"`
+	Base    []dag.TagName            `json:"base,omitempty"`
+	Default []dag.TagName            `json:"default,omitempty"`
+}
+
+// ReadMapping reads Mapping from JSON data:
+//
+//	{
+//	  "tags": [{"name": "NOUN", "parent": "POST"}],
+//	  "words": {"N": ["NOUN"]},
+//	  "affixes": {"S": ["plur"]},
+//	  "base": ["sing"],
+//	  "default": ["UNKN"]
+//	}
+func ReadMapping(reader io.Reader) (*Mapping, error) {
+	data := new(jsonMapping)
+
+	if err := json.NewDecoder(reader).Decode(data); err != nil {
+		return nil, fmt.Errorf("%w: mapping: %v", Error, err)
+	}
+
+	mapping := &Mapping{
+		Tags:    make([]dag.Tag, len(data.Tags)),
+		Words:   data.Words,
+		Affixes: data.Affixes,
+		Base:    data.Base,
+		Default: data.Default,
+	}
+
+	for idx, tag := range data.Tags {
+		if tag.Name == "" {
+			return nil, fmt.Errorf("%w: mapping: tag %d: empty name", Error, idx)
+		}
+		mapping.Tags[idx] = *dag.NewTag(tag.Parent, tag.Name)
+	}
+
+	return mapping, nil
+}
+
+// sortedKeys returns flags of flags map in sorted order.
+func sortedKeys(flags map[string][]dag.TagName) []string {
+	keys := make([]string, 0, len(flags))
+	for flag := range flags {
+		keys = append(keys, flag)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
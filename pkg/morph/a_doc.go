@@ -0,0 +1,19 @@
+package morph
+
+// Package morph shares compiled index between concurrent readers of long-running services and replaces it
+// by updated one without restart. Handle loads and verifies new index in background while readers keep
+// using current one, then swaps indexes atomically, so lookups are never paused or see partial index.
+// Broken or incomplete update is rejected keeping current index. Reload is triggered explicitly,
+// by compiled file change or by signal:
+//
+//	handle, err := morph.NewLoaderHandle(opencorpora.NewLoaderWithOptions(opencorpora.OptionsFromEnv()))
+//	go handle.WatchFile(ctx, compiledFile, time.Minute)
+//	go handle.WatchSignals(ctx, syscall.SIGHUP)
+//
+//	node, err := handle.Index().FetchString(word)
+//
+// Analyzer parses, lemmatizes and inflects single words of index:
+//
+//	analyzer := morph.NewAnalyzer(handle.Index())
+//	parses, err := analyzer.Parse(word)
+//	forms, err := analyzer.Inflect(word, "plur", "gent")
@@ -0,0 +1,226 @@
+package morph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// Form provides word form with its tags.
+type Form struct {
+	Word string        `json:"word"` // word form text
+	Tags []dag.TagName `json:"tags"` // word form tag names
+}
+
+// Parse provides one of word interpretations.
+type Parse struct {
+	Word   string        `json:"word"`   // parsed word
+	Tags   []dag.TagName `json:"tags"`   // word tag names
+	Lemma  uint32        `json:"lemma"`  // dictionary lemma ID, 0 if word tags are not attached by lemma
+	Normal string        `json:"normal"` // lemma normal form, word itself if no lemma
+}
+
+// Lexeme provides dictionary lemma with all its forms.
+type Lexeme struct {
+	Lemma  uint32 `json:"lemma"`  // dictionary lemma ID
+	Normal string `json:"normal"` // lemma normal form
+	Forms  []Form `json:"forms"`  // lemma forms, normal form first then others in index order
+}
+
+// Analyzer parses, lemmatizes and inflects single words using compiled index.
+// Analyzer is safe for concurrent use while index is not changed.
+type Analyzer struct {
+	lookup *index.LemmaLookup
+}
+
+// NewAnalyzer creates analyzer of specified index. Lemmas of index forms are collected once here,
+// so create analyzer once per index.
+func NewAnalyzer(idx *index.ReadOnlyIndex) *Analyzer {
+	return &Analyzer{lookup: idx.LemmaLookup()}
+}
+
+// Index returns analyzed index.
+func (analyzer *Analyzer) Index() *index.ReadOnlyIndex {
+	return analyzer.lookup.Index()
+}
+
+// Parse returns word interpretations in index order, one per every tag set and its lemma.
+// Returns nil if word is not known to index.
+func (analyzer *Analyzer) Parse(word string) ([]Parse, error) {
+	entries, err := analyzer.lookup.Word(word)
+	if err != nil {
+		return nil, fmt.Errorf("%w: parse: %v", Error, err)
+	}
+
+	var parses []Parse
+
+	for _, entry := range entries {
+		if len(entry.Lemmas) == 0 {
+			parses = append(parses, Parse{Word: word, Tags: tagNames(entry.TagSet), Lemma: 0, Normal: word})
+			continue
+		}
+
+		for _, lemmaID := range entry.Lemmas {
+			lexeme, err := analyzer.lexeme(lemmaID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: parse: %v", Error, err)
+			}
+
+			parses = append(parses, Parse{
+				Word:   word,
+				Tags:   tagNames(entry.TagSet),
+				Lemma:  lexeme.Lemma,
+				Normal: lexeme.Normal,
+			})
+		}
+	}
+
+	return parses, nil
+}
+
+// Lemmas returns unique normal forms of word lemmas in parse order. Word itself is its normal form
+// if some of its tag sets are not attached by lemma. Returns nil if word is not known to index.
+func (analyzer *Analyzer) Lemmas(word string) ([]string, error) {
+	parses, err := analyzer.Parse(word)
+	if err != nil {
+		return nil, err
+	}
+
+	var normals []string
+
+	seen := make(map[string]bool, len(parses))
+	for _, parse := range parses {
+		if !seen[parse.Normal] {
+			seen[parse.Normal] = true
+			normals = append(normals, parse.Normal)
+		}
+	}
+
+	return normals, nil
+}
+
+// Lexemes returns lemmas having word among their forms in lemma IDs order.
+// Returns nil if word is not known to index or not attached by any lemma.
+func (analyzer *Analyzer) Lexemes(word string) ([]Lexeme, error) {
+	entries, err := analyzer.lookup.Word(word)
+	if err != nil {
+		return nil, fmt.Errorf("%w: lexemes: %v", Error, err)
+	}
+
+	var (
+		lemmaIDs []index.LemmaID
+		lexemes  []Lexeme
+	)
+
+	seen := make(map[index.LemmaID]bool)
+	for _, entry := range entries {
+		for _, lemmaID := range entry.Lemmas {
+			if !seen[lemmaID] {
+				seen[lemmaID] = true
+				lemmaIDs = append(lemmaIDs, lemmaID)
+			}
+		}
+	}
+
+	sort.Slice(lemmaIDs, func(i, j int) bool { return lemmaIDs[i] < lemmaIDs[j] })
+
+	for _, lemmaID := range lemmaIDs {
+		lexeme, err := analyzer.lexeme(lemmaID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: lexemes: %v", Error, err)
+		}
+
+		lexemes = append(lexemes, lexeme)
+	}
+
+	return lexemes, nil
+}
+
+// Inflect returns forms of word lemmas having all specified grammemes, grammeme names are matched ignoring case.
+// Forms are unique and ordered as lemmas forms. Returns nil if word is not known to index,
+// not attached by any lemma or no lemma form has all grammemes.
+func (analyzer *Analyzer) Inflect(word string, grammemes ...dag.TagName) ([]Form, error) {
+	lexemes, err := analyzer.Lexemes(word)
+	if err != nil {
+		return nil, err
+	}
+
+	var forms []Form
+
+	seen := make(map[string]bool)
+	for _, lexeme := range lexemes {
+		for _, form := range lexeme.Forms {
+			key := form.Word + " " + JoinTags(form.Tags)
+			if seen[key] || !hasTags(form.Tags, grammemes) {
+				continue
+			}
+
+			seen[key] = true
+			forms = append(forms, form)
+		}
+	}
+
+	return forms, nil
+}
+
+// lexeme returns lemma forms having stored lemma normal form first.
+// First lemma form in index order is taken as normal form if index has no normal form of lemma.
+func (analyzer *Analyzer) lexeme(lemmaID index.LemmaID) (Lexeme, error) {
+	entry, err := analyzer.Index().LemmaEntry(lemmaID)
+	if err != nil {
+		return Lexeme{}, err
+	}
+
+	lexeme := Lexeme{Lemma: uint32(lemmaID), Normal: "", Forms: make([]Form, 0, len(entry.Forms))}
+	for _, wordEntry := range entry.Forms {
+		lexeme.Forms = append(lexeme.Forms, Form{Word: wordEntry.Word, Tags: tagNames(wordEntry.TagSet)})
+	}
+
+	if len(lexeme.Forms) > 0 {
+		lexeme.Normal = lexeme.Forms[0].Word
+	}
+
+	return lexeme, nil
+}
+
+// tagNames returns names of tag set tags.
+func tagNames(tagSet dag.TagSet) []dag.TagName {
+	names := make([]dag.TagName, len(tagSet))
+	for idx, tag := range tagSet {
+		names[idx] = tag.Name
+	}
+
+	return names
+}
+
+// JoinTags returns comma separated tag names, e.g. to print Form or Parse tags.
+func JoinTags(names []dag.TagName) string {
+	strs := make([]string, len(names))
+	for idx, name := range names {
+		strs[idx] = string(name)
+	}
+
+	return strings.Join(strs, ",")
+}
+
+// hasTags returns true if names contain every one of required names ignoring case.
+func hasTags(names []dag.TagName, required []dag.TagName) bool {
+	for _, requiredName := range required {
+		found := false
+		for _, name := range names {
+			if strings.EqualFold(string(name), string(requiredName)) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,102 @@
+package morph_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/internal/testdict"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/morph"
+)
+
+// newTestAnalyzer returns analyzer of compiled test dictionary.
+func newTestAnalyzer(t *testing.T) *morph.Analyzer {
+	t.Helper()
+
+	return morph.NewAnalyzer(testdict.Index(t, testdict.Dictionary))
+}
+
+func TestAnalyzer_Parse(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	parses, err := analyzer.Parse("ежа")
+	require.NoError(t, err)
+	require.Len(t, parses, 2)
+
+	for _, parse := range parses {
+		require.Equal(t, "ежа", parse.Word)
+		require.Equal(t, uint32(1), parse.Lemma)
+		require.Equal(t, "ёж", parse.Normal)
+	}
+
+	require.Contains(t, morph.JoinTags(parses[0].Tags)+" "+morph.JoinTags(parses[1].Tags), "gent")
+
+	parses, err = analyzer.Parse("ёжик")
+	require.NoError(t, err)
+	require.Nil(t, parses)
+}
+
+func TestAnalyzer_Lemmas(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	for word, expected := range map[string][]string{
+		"ёлок":  {"ёлка"},
+		"лесов": {"лес"},
+		"кота":  {"кот"},
+		"стоят": {"стоит"}, // first listed form of lemma having no infinitive form
+		"ёжик":  nil,
+	} {
+		normals, err := analyzer.Lemmas(word)
+		require.NoError(t, err)
+		require.Equal(t, expected, normals, word)
+	}
+}
+
+func TestAnalyzer_Lexemes(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	lexemes, err := analyzer.Lexemes("кошек")
+	require.NoError(t, err)
+	require.Len(t, lexemes, 1)
+	require.Equal(t, uint32(3), lexemes[0].Lemma)
+	require.Equal(t, "кошка", lexemes[0].Normal)
+	require.Equal(t, "кошка", lexemes[0].Forms[0].Word) // normal form goes first
+
+	words := make([]string, 0, len(lexemes[0].Forms))
+	for _, form := range lexemes[0].Forms {
+		words = append(words, form.Word)
+	}
+
+	require.Contains(t, words, "кошек")
+	require.Contains(t, words, "кошку")
+
+	// normal form of every lemma is the one index stores, as exporters write it
+	require.NoError(t, analyzer.Index().WalkLemmas(func(lemma index.LemmaEntry) error {
+		lexemes, err := analyzer.Lexemes(lemma.Forms[len(lemma.Forms)-1].Word)
+		require.NoError(t, err)
+
+		for _, lexeme := range lexemes {
+			if lexeme.Lemma == uint32(lemma.ID) {
+				require.Equal(t, lemma.Forms[0].Word, lexeme.Normal, lemma.ID)
+			}
+		}
+
+		return nil
+	}))
+}
+
+func TestAnalyzer_Inflect(t *testing.T) {
+	analyzer := newTestAnalyzer(t)
+
+	forms, err := analyzer.Inflect("ёж", "plur", "GENT")
+	require.NoError(t, err)
+	require.Len(t, forms, 1)
+	require.Equal(t, "ежей", forms[0].Word)
+	require.Contains(t, forms[0].Tags, dag.TagName("gent"))
+
+	forms, err = analyzer.Inflect("ёж", "VERB")
+	require.NoError(t, err)
+	require.Nil(t, forms)
+}
@@ -0,0 +1,13 @@
+package morph
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// Error identifies index handle errors.
+	Error = errors.New("morph")
+	// ErrReload indicates new index is not loaded or not verified, current index is kept.
+	ErrReload = fmt.Errorf("%w: reload", Error)
+)
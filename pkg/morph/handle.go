@@ -0,0 +1,165 @@
+package morph
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/amarin/logging"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+// LoadFunc loads compiled index, e.g. opencorpora.Loader.LoadIndex.
+type LoadFunc func() (*index.ReadOnlyIndex, error)
+
+// Handle holds compiled index shared by concurrent readers. Reload replaces index atomically,
+// readers took index before reload keep using previous one until their lookups done.
+type Handle struct {
+	logging.Logger
+	load      LoadFunc
+	current   atomic.Value // holds *index.ReadOnlyIndex, atomic.Pointer requires go 1.19
+	reloading sync.Mutex   // serializes reloads, readers are never locked
+}
+
+// NewHandle creates handle loading index by load. Initial index is loaded and verified immediately.
+// Logger reports reloads, named morph logger used if nil.
+func NewHandle(load LoadFunc, logger logging.Logger) (*Handle, error) {
+	if logger == nil {
+		logger = logging.NewNamedLogger("morph")
+	}
+
+	handle := &Handle{Logger: logger, load: load, current: atomic.Value{}, reloading: sync.Mutex{}}
+	if err := handle.Reload(); err != nil {
+		return nil, err
+	}
+
+	return handle, nil
+}
+
+// NewLoaderHandle creates handle loading compiled index of loader. Loader logger reports reloads.
+func NewLoaderHandle(loader *opencorpora.Loader) (*Handle, error) {
+	return NewHandle(loader.LoadIndex, loader.Logger)
+}
+
+// Index returns current index. Returned index stays valid after reload, so take it once per lookup.
+func (handle *Handle) Index() *index.ReadOnlyIndex {
+	return handle.current.Load().(*index.ReadOnlyIndex) //nolint:forcetypeassert // only index is stored
+}
+
+// Reload loads and verifies new index replacing current one.
+// Current index is kept and error wrapping ErrReload returned if new index failed to load or verify.
+// Concurrent reloads are serialized.
+func (handle *Handle) Reload() error {
+	handle.reloading.Lock()
+	defer handle.reloading.Unlock()
+
+	started := time.Now()
+
+	loaded, err := handle.load()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrReload, err)
+	}
+
+	return handle.swap(loaded, started)
+}
+
+// Swap verifies specified index and replaces current one, e.g. index loaded from embedded data.
+// Current index is kept and error wrapping ErrReload returned if index is not consistent.
+func (handle *Handle) Swap(newIndex *index.ReadOnlyIndex) error {
+	handle.reloading.Lock()
+	defer handle.reloading.Unlock()
+
+	return handle.swap(newIndex, time.Now())
+}
+
+// swap verifies index and replaces current one. Must be called holding reloading lock.
+func (handle *Handle) swap(newIndex *index.ReadOnlyIndex, started time.Time) error {
+	if err := newIndex.Verify(); err != nil {
+		return fmt.Errorf("%w: %v", ErrReload, err)
+	}
+
+	handle.current.Store(newIndex)
+	handle.Infof("index swapped: %d words %d nodes, took %v",
+		newIndex.WordsCount(), newIndex.NodesCount(), time.Since(started))
+
+	return nil
+}
+
+// WatchFile checks file every interval and reloads index when file is changed, until context done.
+// Changed file is reloaded only when its size and modification time stay unchanged for an interval,
+// so file being written is not loaded. Reload errors are logged keeping current index.
+func (handle *Handle) WatchFile(ctx context.Context, fileName string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	loaded := fileStamp(fileName)
+	pending := loaded
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stamp := fileStamp(fileName)
+
+		switch {
+		case stamp == loaded || stamp == "":
+			pending = loaded
+		case stamp != pending:
+			handle.Debugf("%v changed, wait for it settled", fileName)
+			pending = stamp
+		default:
+			handle.Infof("%v changed, reload", fileName)
+			loaded = stamp
+
+			if err := handle.Reload(); err != nil {
+				handle.Error(err.Error())
+			}
+		}
+	}
+}
+
+// fileStamp returns file size and modification time as string, empty if file is not accessible.
+func fileStamp(fileName string) string {
+	fileStat, err := os.Stat(fileName)
+	if err != nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%d@%v", fileStat.Size(), fileStat.ModTime().UnixNano())
+}
+
+// WatchSignals reloads index every time process receives one of signals, SIGHUP if none specified,
+// until context done. Reload errors are logged keeping current index.
+func (handle *Handle) WatchSignals(ctx context.Context, signals ...os.Signal) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+
+	defer signal.Stop(received)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case receivedSignal := <-received:
+			handle.Infof("%v received, reload", receivedSignal)
+
+			if err := handle.Reload(); err != nil {
+				handle.Error(err.Error())
+			}
+		}
+	}
+}
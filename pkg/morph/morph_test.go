@@ -0,0 +1,116 @@
+package morph_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/testdict"
+	"github.com/amarin/gomorphy/pkg/morph"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+func TestHandle_Reload(t *testing.T) {
+	loader := testdict.Loader(t, testdict.Dictionary)
+
+	handle, err := morph.NewLoaderHandle(loader)
+	require.NoError(t, err)
+
+	initial := handle.Index()
+	require.Equal(t, 21, initial.WordsCount())
+
+	compiled, err := os.ReadFile(loader.CompiledFile())
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(loader.CompiledFile(), compiled[:len(compiled)/2], 0o600))
+	require.ErrorIs(t, handle.Reload(), morph.ErrReload)
+	require.Same(t, initial, handle.Index())
+
+	require.NoError(t, os.WriteFile(loader.CompiledFile(), compiled, 0o600))
+
+	var waitReaders sync.WaitGroup
+
+	for reader := 0; reader < 4; reader++ {
+		waitReaders.Add(1)
+
+		go func() {
+			defer waitReaders.Done()
+
+			for lookup := 0; lookup < 100; lookup++ {
+				node, err := handle.Index().FetchString("ёж")
+				require.NoError(t, err)
+				require.NotEmpty(t, node.TagSets())
+			}
+		}()
+	}
+
+	require.NoError(t, handle.Reload())
+	waitReaders.Wait()
+	require.NotSame(t, initial, handle.Index())
+	require.Equal(t, 21, handle.Index().WordsCount())
+
+	_, err = morph.NewLoaderHandle(opencorpora.NewLoader(t.TempDir()))
+	require.ErrorIs(t, err, morph.ErrReload)
+}
+
+func TestHandle_WatchFile(t *testing.T) {
+	loader := testdict.Loader(t, testdict.Dictionary)
+
+	handle, err := morph.NewLoaderHandle(loader)
+	require.NoError(t, err)
+
+	initial := handle.Index()
+	ctx, cancel := context.WithCancel(context.Background())
+	watched := make(chan struct{})
+
+	go func() {
+		handle.WatchFile(ctx, loader.CompiledFile(), 10*time.Millisecond)
+		close(watched)
+	}()
+
+	later := time.Now()
+	require.Eventually(t, func() bool {
+		later = later.Add(time.Minute) // touch until watcher started and noticed change
+		require.NoError(t, os.Chtimes(loader.CompiledFile(), later, later))
+
+		return handle.Index() != initial
+	}, 5*time.Second, 100*time.Millisecond)
+
+	cancel()
+	<-watched
+}
+
+func TestHandle_WatchSignals(t *testing.T) {
+	loader := testdict.Loader(t, testdict.Dictionary)
+
+	handle, err := morph.NewLoaderHandle(loader)
+	require.NoError(t, err)
+
+	initial := handle.Index()
+	ctx, cancel := context.WithCancel(context.Background())
+	watched := make(chan struct{})
+
+	go func() {
+		handle.WatchSignals(ctx, syscall.SIGHUP)
+		close(watched)
+	}()
+
+	process, err := os.FindProcess(os.Getpid())
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		require.NoError(t, process.Signal(syscall.SIGHUP))
+
+		return handle.Index() != initial
+	}, 5*time.Second, 50*time.Millisecond)
+
+	cancel()
+	<-watched
+
+	require.NoError(t, handle.Swap(initial))
+	require.Same(t, initial, handle.Index())
+	require.Equal(t, 21, handle.Index().WordsCount())
+}
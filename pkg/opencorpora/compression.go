@@ -0,0 +1,121 @@
+package opencorpora
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Compression defines compiled index file compression.
+type Compression string
+
+// Known compressions. Compressed index is detected by its header when loading, so any compression is loaded
+// regardless of loader settings.
+const (
+	CompressionNone  Compression = "none"  // plain compiled index
+	CompressionGzip  Compression = "gzip"  // gzip compressed index
+	CompressionZstd  Compression = "zstd"  // zstandard compressed index, much faster to load than gzip, zstd tag build only
+	CompressionBzip2 Compression = "bzip2" // bzip2 compressed index, load only as bzip2 is not writable
+)
+
+// Compressions lists compressions available to save index. CompressionZstd is available if built with zstd tag.
+var Compressions = availableCompressions()
+
+// availableCompressions returns compressions available to save index.
+func availableCompressions() []Compression {
+	if zstdBuilt {
+		return []Compression{CompressionNone, CompressionGzip, CompressionZstd}
+	}
+
+	return []Compression{CompressionNone, CompressionGzip}
+}
+
+// compressionMagics maps compressions headers.
+var compressionMagics = map[Compression][]byte{
+	CompressionGzip:  {0x1f, 0x8b},
+	CompressionZstd:  {0x28, 0xb5, 0x2f, 0xfd},
+	CompressionBzip2: []byte("BZh"),
+}
+
+// ParseCompression returns compression available to save index by its name ignoring case.
+// Empty name means no compression.
+func ParseCompression(name string) (Compression, error) {
+	if name == "" {
+		return CompressionNone, nil
+	}
+
+	for _, compression := range Compressions {
+		if strings.EqualFold(name, string(compression)) {
+			return compression, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %v", ErrUnknownCompression, name)
+}
+
+// detectCompression returns compression of reader data detected by data header without consuming it.
+func detectCompression(reader *bufio.Reader) Compression {
+	header, _ := reader.Peek(4) // shorter data is not compressed, index reading reports its error then
+
+	for compression, magic := range compressionMagics {
+		if bytes.HasPrefix(header, magic) {
+			return compression
+		}
+	}
+
+	return CompressionNone
+}
+
+// decompressingReader returns reader decompressing data of reader if data are compressed.
+// Returned closer releases decompressor resources, reader itself is not closed.
+func decompressingReader(reader *bufio.Reader) (io.Reader, func(), error) {
+	switch compression := detectCompression(reader); compression {
+	case CompressionGzip:
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v: %v", Error, compression, err)
+		}
+
+		return bufio.NewReader(gzipReader), func() { _ = gzipReader.Close() }, nil
+	case CompressionZstd:
+		zstdReader, closeReader, err := newZstdReader(reader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return bufio.NewReader(zstdReader), closeReader, nil
+	case CompressionBzip2:
+		return bufio.NewReader(bzip2.NewReader(reader)), func() {}, nil
+	default:
+		return reader, func() {}, nil
+	}
+}
+
+// nopWriteCloser wraps writer into io.WriteCloser doing nothing on close.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing. Implements io.Closer.
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// compressingWriter returns writer compressing data into writer using specified compression.
+// Returned writer must be closed to flush compressed data, writer itself is not closed.
+func compressingWriter(writer io.Writer, compression Compression) (io.WriteCloser, error) {
+	switch compression {
+	case CompressionNone, "":
+		return nopWriteCloser{Writer: writer}, nil
+	case CompressionGzip:
+		return gzip.NewWriterLevel(writer, gzip.BestCompression)
+	case CompressionZstd:
+		return newZstdWriter(writer)
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownCompression, compression)
+	}
+}
@@ -0,0 +1,90 @@
+package opencorpora_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+func TestParseCompression(t *testing.T) {
+	for _, compression := range opencorpora.Compressions {
+		parsed, err := opencorpora.ParseCompression(string(compression))
+		require.NoError(t, err)
+		require.Equal(t, compression, parsed)
+	}
+
+	parsed, err := opencorpora.ParseCompression("")
+	require.NoError(t, err)
+	require.Equal(t, opencorpora.CompressionNone, parsed)
+
+	parsed, err = opencorpora.ParseCompression("GZIP")
+	require.NoError(t, err)
+	require.Equal(t, opencorpora.CompressionGzip, parsed)
+
+	_, err = opencorpora.ParseCompression(string(opencorpora.CompressionBzip2))
+	require.ErrorIs(t, err, opencorpora.ErrUnknownCompression)
+}
+
+func TestLoader_SaveIndex_Compression(t *testing.T) {
+	logging.MustInit()
+
+	dataPath := t.TempDir()
+	loader := opencorpora.NewLoader(dataPath)
+	loader.SetWorkers(1)
+	compiled := make(map[opencorpora.Compression][]byte)
+
+	for _, compression := range opencorpora.Compressions {
+		loader.SetCompression(compression)
+		require.Equal(t, compression, loader.Compression())
+		require.NoError(t, loader.Compile(testDictionary, true))
+
+		data, err := os.ReadFile(loader.CompiledFile())
+		require.NoError(t, err)
+		compiled[compression] = data
+
+		loaded, err := loader.LoadIndex()
+		require.NoError(t, err, compression)
+		require.Equal(t, 21, loaded.WordsCount(), compression)
+	}
+
+	plain := compiled[opencorpora.CompressionNone]
+	for _, compression := range opencorpora.Compressions[1:] {
+		require.Less(t, len(compiled[compression]), len(plain), compression)
+	}
+
+	// gzip data decompress to plain index
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compiled[opencorpora.CompressionGzip]))
+	require.NoError(t, err)
+	decompressed := new(bytes.Buffer)
+	_, err = decompressed.ReadFrom(gzipReader)
+	require.NoError(t, err)
+	require.Equal(t, plain, decompressed.Bytes())
+
+	loader.SetCompression("lzma")
+	require.ErrorIs(t, loader.Compile(testDictionary, true), opencorpora.ErrUnknownCompression)
+	require.NoFileExists(t, loader.CompiledFile()+".part")
+
+	// failed save keeps previously compiled index
+	kept, err := os.ReadFile(loader.CompiledFile())
+	require.NoError(t, err)
+	require.Equal(t, compiled[opencorpora.Compressions[len(opencorpora.Compressions)-1]], kept)
+}
+
+func TestLoader_LoadIndexFrom_Bzip2(t *testing.T) {
+	logging.MustInit()
+
+	// plain test dictionary index packed by `bzip2 -9`
+	packed, err := os.ReadFile(filepath.Join("testdata", "opencorpora.dat.bz2"))
+	require.NoError(t, err)
+
+	loaded, err := opencorpora.NewLoader(t.TempDir()).LoadIndexFrom(bytes.NewReader(packed))
+	require.NoError(t, err)
+	require.Equal(t, 21, loaded.WordsCount())
+}
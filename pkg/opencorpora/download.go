@@ -0,0 +1,392 @@
+package opencorpora
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amarin/gomorphy/pkg/common"
+)
+
+// Download validators headers.
+const (
+	httpHeaderETag            = "ETag"
+	httpHeaderIfNoneMatch     = "If-None-Match"
+	httpHeaderIfModifiedSince = "If-Modified-Since"
+	httpHeaderRange           = "Range"
+	httpHeaderIfRange         = "If-Range"
+	httpHeaderContentRange    = "Content-Range"
+)
+
+// Suffixes of download files names.
+const (
+	partialSuffix = ".part" // partially downloaded file, renamed to downloaded file when completed
+	metaSuffix    = ".meta" // validators of downloaded or partially downloaded file
+)
+
+// downloadMeta provides validators of downloaded file used in conditional and resumed requests.
+type downloadMeta struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+}
+
+// readDownloadMeta reads validators stored in file. Returns false if file is missed or not readable.
+func readDownloadMeta(fileName string) (meta downloadMeta, found bool) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return meta, false
+	}
+
+	if err = json.Unmarshal(data, &meta); err != nil {
+		return meta, false
+	}
+
+	return meta, true
+}
+
+// write stores validators into file.
+func (meta downloadMeta) write(fileName string) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, data, 0o600)
+}
+
+// validator returns validator identifying file version in If-Range header, ETag is preferred.
+func (meta downloadMeta) validator() string {
+	if meta.ETag != "" {
+		return meta.ETag
+	}
+
+	return meta.LastModified
+}
+
+// retryableError marks download errors which may pass if download is retried.
+type retryableError struct {
+	err error
+}
+
+// Error returns wrapped error text. Implements error.
+func (retryable retryableError) Error() string {
+	return retryable.err.Error()
+}
+
+// Unwrap returns wrapped error.
+func (retryable retryableError) Unwrap() error {
+	return retryable.err
+}
+
+// isRetryable reports whether error may pass if download is retried.
+func isRetryable(err error) bool {
+	var retryable retryableError
+
+	return errors.As(err, &retryable)
+}
+
+// partialFilePath returns path to partially downloaded file.
+func (loader Loader) partialFilePath() string {
+	return loader.downloadedFilePath() + partialSuffix
+}
+
+// IsUpdateRequired checks remote dictionary is changed since downloaded one.
+// Remote ETag is compared with downloaded one if both known, otherwise remote Last-Modified is compared
+// with downloaded file modification time. Update is required if no file downloaded yet.
+func (loader Loader) IsUpdateRequired() (bool, error) {
+	return loader.isUpdateRequired(context.Background())
+}
+
+// isUpdateRequired checks remote dictionary is changed since downloaded one, check is stopped when ctx done.
+func (loader Loader) isUpdateRequired(ctx context.Context) (bool, error) {
+	loader.Info("check if update required")
+	expectedFile := loader.downloadedFilePath()
+	loader.Debugf("check file %v", expectedFile)
+	fileStat, err := os.Stat(expectedFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		loader.Debugf("file not exists, update required: %v", expectedFile)
+
+		return true, nil // no file, update required
+	case err != nil:
+		loader.Warnf("file %v: error: %v", expectedFile, err)
+
+		return false, err
+	}
+
+	loader.Debugf("check remote %v", loader.sourceURL)
+	request, err := http.NewRequestWithContext(ctx, http.MethodHead, loader.sourceURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	response, err := loader.httpClient.Do(request)
+	if err != nil {
+		loader.Warnf("remote %v: error: %v", loader.sourceURL, err)
+		return false, err
+	}
+
+	_ = response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		loader.Warnf("remote %v: status: %v", loader.sourceURL, response.StatusCode)
+		return false, fmt.Errorf("%w: unexpected response code %v", ErrDownload, response.StatusCode)
+	}
+
+	meta, found := loader.downloadedMeta() // not found if downloaded from other source URL
+	if remoteETag := response.Header.Get(httpHeaderETag); found && remoteETag != "" && meta.ETag != "" {
+		loader.Debugf("remote %v: %v, downloaded %v", httpHeaderETag, remoteETag, meta.ETag)
+
+		return remoteETag != meta.ETag, nil
+	}
+
+	lastModifiedString := response.Header.Get(common.HTTPHeaderLastModified)
+	if lastModifiedString == "" {
+		loader.Debugf("remote %v: missed, assume no update required", common.HTTPHeaderLastModified)
+
+		return false, nil
+	}
+
+	loader.Debugf("remote %v: %v", common.HTTPHeaderLastModified, lastModifiedString)
+	lastModified, err := http.ParseTime(lastModifiedString)
+	if err != nil {
+		return true, nil // cant compare lastModified, do update
+	}
+
+	return lastModified.After(fileStat.ModTime()), nil
+}
+
+// DownloadUpdate downloads dictionary if remote one is changed since downloaded.
+// Request is conditional using ETag and Last-Modified of previous download, so unchanged dictionary is not
+// transferred. Dictionary is downloaded into partial file renamed to downloaded file only when completed and
+// checksum verified, interrupted download is resumed by next attempt using Range request.
+// Network and server errors are retried with growing delays. Returns true if dictionary updated.
+func (loader Loader) DownloadUpdate() (updated bool, err error) {
+	return loader.downloadUpdate(loader.newBuildTracker(context.Background()))
+}
+
+// downloadUpdate downloads changed dictionary reporting downloaded bytes to tracker.
+// Download and retries are stopped when build canceled keeping partial file to resume download.
+func (loader Loader) downloadUpdate(tracker *buildTracker) (updated bool, err error) {
+	if err = common.MakeDataPath(loader.DataPath()); err != nil {
+		return false, err
+	}
+
+	tracker.phase(PhaseDownload, -1)
+	delay := loader.retryDelay
+
+	for attempt := 0; ; attempt++ {
+		updated, err = loader.downloadAttempt(tracker)
+		if err == nil || !isRetryable(err) || attempt >= loader.retries || tracker.canceled() != nil {
+			break
+		}
+
+		loader.Warnf("download attempt %d: %v, retry in %v", attempt+1, err, delay)
+
+		select {
+		case <-tracker.ctx.Done():
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+
+	tracker.flush()
+
+	if canceled := tracker.canceled(); err != nil && canceled != nil {
+		return false, canceled
+	}
+
+	switch {
+	case err != nil:
+		return false, fmt.Errorf("%w: %v", ErrDownload, err)
+	case !updated:
+		loader.Info("remote dictionary not modified")
+
+		return false, nil
+	}
+
+	if err = loader.verifyChecksum(loader.partialFilePath()); err != nil {
+		loader.removeDownloadFile(loader.partialFilePath())
+		loader.removeDownloadFile(loader.partialFilePath() + metaSuffix)
+
+		return false, err
+	}
+
+	if err = os.Rename(loader.partialFilePath(), loader.downloadedFilePath()); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrDownload, err)
+	}
+
+	if err = os.Rename(loader.partialFilePath()+metaSuffix, loader.downloadedFilePath()+metaSuffix); err != nil {
+		loader.Warnf("keep download validators: %v", err)
+	}
+
+	loader.Infof("downloaded %v", loader.downloadedFilePath())
+
+	return true, nil
+}
+
+// downloadAttempt requests dictionary once appending response to partial file if partial download is resumed.
+// Returns false if remote dictionary not modified since previous download.
+func (loader Loader) downloadAttempt(tracker *buildTracker) (updated bool, err error) {
+	request, err := http.NewRequestWithContext(tracker.ctx, http.MethodGet, loader.sourceURL, nil)
+	if err != nil {
+		return false, err
+	}
+
+	if meta, found := loader.downloadedMeta(); found {
+		if meta.ETag != "" {
+			request.Header.Set(httpHeaderIfNoneMatch, meta.ETag)
+		}
+
+		if meta.LastModified != "" {
+			request.Header.Set(httpHeaderIfModifiedSince, meta.LastModified)
+		}
+	}
+
+	offset := loader.partialOffset()
+	if offset > 0 {
+		partialMeta, _ := readDownloadMeta(loader.partialFilePath() + metaSuffix)
+		loader.Debugf("resume download from %d bytes", offset)
+		request.Header.Set(httpHeaderRange, "bytes="+strconv.FormatInt(offset, 10)+"-")
+		request.Header.Set(httpHeaderIfRange, partialMeta.validator())
+	}
+
+	loader.Debugf("get remote %v", loader.sourceURL)
+	response, err := loader.httpClient.Do(request)
+	if err != nil {
+		return false, retryableError{err: err}
+	}
+
+	defer func() { _ = response.Body.Close() }()
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+
+	switch {
+	case response.StatusCode == http.StatusNotModified:
+		return false, nil
+	case response.StatusCode == http.StatusPartialContent && offset > 0 &&
+		strings.HasPrefix(response.Header.Get(httpHeaderContentRange), "bytes "+strconv.FormatInt(offset, 10)+"-"):
+		flags = os.O_WRONLY | os.O_APPEND
+	case response.StatusCode == http.StatusOK:
+		meta := downloadMeta{
+			URL:          loader.sourceURL,
+			ETag:         response.Header.Get(httpHeaderETag),
+			LastModified: response.Header.Get(common.HTTPHeaderLastModified),
+		}
+		if err = meta.write(loader.partialFilePath() + metaSuffix); err != nil {
+			return false, err
+		}
+	case response.StatusCode == http.StatusRequestedRangeNotSatisfiable || response.StatusCode == http.StatusPartialContent:
+		loader.removeDownloadFile(loader.partialFilePath()) // partial file is not a prefix of remote one
+
+		return false, retryableError{err: fmt.Errorf("unexpected partial response %v", response.Status)}
+	case response.StatusCode >= http.StatusInternalServerError || response.StatusCode == http.StatusTooManyRequests:
+		return false, retryableError{err: fmt.Errorf("unexpected response %v", response.Status)}
+	default:
+		return false, fmt.Errorf("unexpected response %v", response.Status)
+	}
+
+	file, err := os.OpenFile(loader.partialFilePath(), flags, 0o600)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	if flags&os.O_APPEND == 0 {
+		offset = 0 // full dictionary is transferred
+	}
+
+	total := int64(0)
+	if response.ContentLength > 0 {
+		total = offset + response.ContentLength
+	}
+
+	counted := &progressReader{
+		reader:   response.Body,
+		read:     0,
+		progress: func(read int64, _ int64) { tracker.bytes(offset+read, total) },
+	}
+
+	if _, err = io.Copy(file, counted); err != nil {
+		return false, retryableError{err: err} // partial file is kept to resume download
+	}
+
+	return true, nil
+}
+
+// downloadedMeta returns validators of downloaded file if it exists and was downloaded from loader source URL.
+func (loader Loader) downloadedMeta() (downloadMeta, bool) {
+	if !loader.IsDownloadExists() {
+		return downloadMeta{URL: "", ETag: "", LastModified: ""}, false
+	}
+
+	meta, found := readDownloadMeta(loader.downloadedFilePath() + metaSuffix)
+
+	return meta, found && meta.URL == loader.sourceURL
+}
+
+// partialOffset returns size of partially downloaded file which download may be resumed from.
+// Returns 0 if no partial file or its source is unknown.
+func (loader Loader) partialOffset() int64 {
+	meta, found := readDownloadMeta(loader.partialFilePath() + metaSuffix)
+	if !found || meta.URL != loader.sourceURL || meta.validator() == "" {
+		return 0
+	}
+
+	fileStat, err := os.Stat(loader.partialFilePath())
+	if err != nil {
+		return 0
+	}
+
+	return fileStat.Size()
+}
+
+// verifyChecksum checks SHA-256 digest of file equals to expected one if expected checksum set.
+func (loader Loader) verifyChecksum(fileName string) error {
+	if loader.checksum == "" {
+		return nil
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrChecksum, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return fmt.Errorf("%w: %v", ErrChecksum, err)
+	}
+
+	if actual := hex.EncodeToString(hash.Sum(nil)); actual != loader.checksum {
+		return fmt.Errorf("%w: expected %v, got %v", ErrChecksum, loader.checksum, actual)
+	}
+
+	loader.Debugf("checksum verified %v", fileName)
+
+	return nil
+}
+
+// removeDownloadFile removes download file if exists.
+func (loader Loader) removeDownloadFile(fileName string) {
+	if err := os.Remove(fileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+		loader.Warnf("remove %v: %v", fileName, err)
+	}
+}
@@ -0,0 +1,248 @@
+package opencorpora_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+// testRemote serves dictionary content supporting conditional and range requests.
+type testRemote struct {
+	mu       sync.Mutex
+	content  []byte
+	etag     string
+	modified time.Time
+	requests []*http.Request
+	failures int  // count of next requests failed with internal server error
+	status   int  // status of all responses if set
+	truncate bool // abort next response after half of content sent
+}
+
+func (remote *testRemote) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	remote.mu.Lock()
+	remote.requests = append(remote.requests, request)
+	content, failed, truncate, status := remote.content, remote.failures > 0, remote.truncate, remote.status
+	remote.failures--
+	remote.truncate = false
+	writer.Header().Set("ETag", remote.etag)
+	remote.mu.Unlock()
+
+	switch {
+	case status != 0:
+		writer.WriteHeader(status)
+	case failed:
+		writer.WriteHeader(http.StatusInternalServerError)
+	case truncate:
+		writer.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		_, _ = writer.Write(content[:len(content)/2])
+		writer.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	default:
+		http.ServeContent(writer, request, "dict.xml.bz2", remote.modified, bytes.NewReader(content))
+	}
+}
+
+// set replaces served content.
+func (remote *testRemote) set(content []byte, etag string) {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+
+	remote.content, remote.etag, remote.modified = content, etag, time.Now().Add(-time.Hour)
+}
+
+// lastRequest returns last served request.
+func (remote *testRemote) lastRequest() *http.Request {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+
+	return remote.requests[len(remote.requests)-1]
+}
+
+// requestsCount returns count of served requests.
+func (remote *testRemote) requestsCount() int {
+	remote.mu.Lock()
+	defer remote.mu.Unlock()
+
+	return len(remote.requests)
+}
+
+func newTestRemote(t *testing.T, retries int, checksum string) (*testRemote, *opencorpora.Loader) {
+	t.Helper()
+	logging.MustInit()
+
+	remote := &testRemote{} //nolint:exhaustivestruct
+	remote.set(bytes.Repeat([]byte("dictionary data "), 4096), `"v1"`)
+	server := httptest.NewServer(remote)
+	t.Cleanup(server.Close)
+
+	loader := opencorpora.NewLoaderWithOptions(opencorpora.Options{
+		DataPath:     t.TempDir(),
+		CompiledFile: "",
+		SourceURL:    server.URL + "/dict.xml.bz2",
+		HTTPClient:   server.Client(),
+		Retries:      retries,
+		RetryDelay:   time.Millisecond,
+		Checksum:     checksum,
+		Logger:       nil,
+		Compression:  opencorpora.CompressionNone,
+	})
+
+	return remote, loader
+}
+
+func downloaded(t *testing.T, loader *opencorpora.Loader) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join(loader.DataPath(), opencorpora.LocalSourceFilename))
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestLoader_DownloadUpdate_Conditional(t *testing.T) {
+	remote, loader := newTestRemote(t, 0, "")
+
+	updated, err := loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, remote.content, downloaded(t, loader))
+
+	required, err := loader.IsUpdateRequired()
+	require.NoError(t, err)
+	require.False(t, required)
+
+	updated, err = loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.False(t, updated)
+	require.Equal(t, `"v1"`, remote.lastRequest().Header.Get("If-None-Match"))
+	require.NotEmpty(t, remote.lastRequest().Header.Get("If-Modified-Since"))
+
+	remote.set([]byte("new dictionary"), `"v2"`)
+
+	required, err = loader.IsUpdateRequired()
+	require.NoError(t, err)
+	require.True(t, required)
+
+	updated, err = loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, []byte("new dictionary"), downloaded(t, loader))
+}
+
+func TestLoader_DownloadUpdate_Resume(t *testing.T) {
+	remote, loader := newTestRemote(t, -1, "")
+	remote.truncate = true
+
+	_, err := loader.DownloadUpdate()
+	require.ErrorIs(t, err, opencorpora.ErrDownload)
+	require.False(t, loader.IsDownloadExists(), "incomplete download is not exposed")
+
+	updated, err := loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, remote.content, downloaded(t, loader))
+	require.Contains(t, remote.lastRequest().Header.Get("Range"), "bytes=")
+	require.Equal(t, `"v1"`, remote.lastRequest().Header.Get("If-Range"))
+
+	// remote file changed since partial download is downloaded from start
+	remote, loader = newTestRemote(t, -1, "")
+	remote.truncate = true
+
+	_, err = loader.DownloadUpdate()
+	require.ErrorIs(t, err, opencorpora.ErrDownload)
+
+	remote.set(bytes.Repeat([]byte("other data "), 4096), `"v2"`)
+
+	updated, err = loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, remote.content, downloaded(t, loader))
+}
+
+func TestLoader_DownloadUpdate_Retries(t *testing.T) {
+	remote, loader := newTestRemote(t, 2, "")
+	remote.failures = 2
+
+	updated, err := loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+	require.Equal(t, 3, remote.requestsCount())
+
+	remote, loader = newTestRemote(t, 2, "")
+	remote.failures = 5
+
+	_, err = loader.DownloadUpdate()
+	require.ErrorIs(t, err, opencorpora.ErrDownload)
+	require.Equal(t, 3, remote.requestsCount())
+	require.False(t, loader.IsDownloadExists())
+
+	// client errors are not retried
+	remote, loader = newTestRemote(t, 2, "")
+	remote.status = http.StatusNotFound
+
+	_, err = loader.DownloadUpdate()
+	require.ErrorIs(t, err, opencorpora.ErrDownload)
+	require.Equal(t, 1, remote.requestsCount())
+}
+
+func TestLoader_DownloadUpdate_Checksum(t *testing.T) {
+	content := bytes.Repeat([]byte("dictionary data "), 4096)
+	digest := sha256.Sum256(content)
+
+	_, loader := newTestRemote(t, 0, hex.EncodeToString(digest[:]))
+	updated, err := loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	_, loader = newTestRemote(t, 0, hex.EncodeToString(make([]byte, sha256.Size)))
+	_, err = loader.DownloadUpdate()
+	require.ErrorIs(t, err, opencorpora.ErrChecksum)
+	require.False(t, loader.IsDownloadExists())
+
+	entries, err := os.ReadDir(loader.DataPath())
+	require.NoError(t, err)
+	require.Empty(t, entries, "corrupted download is removed")
+}
+
+func TestLoader_IsUpdateRequired_OtherSource(t *testing.T) {
+	_, loader := newTestRemote(t, 0, "")
+	updated, err := loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	// other source serves the same ETag, but its dictionary is modified after download
+	otherRemote, otherLoader := newTestRemote(t, 0, "")
+	otherRemote.modified = time.Now().Add(time.Hour)
+	otherLoader.SetDataPath(loader.DataPath())
+
+	required, err := otherLoader.IsUpdateRequired()
+	require.NoError(t, err)
+	require.True(t, required, "ETag of other source download is not compared")
+	require.Equal(t, http.MethodHead, otherRemote.lastRequest().Method)
+}
+
+func TestLoader_IsUpdateRequired_StatError(t *testing.T) {
+	remote, loader := newTestRemote(t, 0, "")
+	notDirectory := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(notDirectory, []byte{}, 0o600))
+	loader.SetDataPath(notDirectory)
+
+	required, err := loader.IsUpdateRequired()
+	require.ErrorIs(t, err, syscall.ENOTDIR)
+	require.False(t, required)
+	require.Zero(t, remote.requestsCount())
+}
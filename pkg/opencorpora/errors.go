@@ -2,6 +2,21 @@ package opencorpora
 
 import (
 	"errors"
+	"fmt"
 )
 
-var Error = errors.New("opencorpora")
+var (
+	Error = errors.New("opencorpora")
+
+	// ErrUnknownCompression indicates unknown compiled index compression requested.
+	ErrUnknownCompression = fmt.Errorf("%w: unknown compression", Error)
+
+	// ErrDownload indicates dictionary download failed.
+	ErrDownload = fmt.Errorf("%w: download", Error)
+
+	// ErrChecksum indicates downloaded dictionary checksum differs from expected one.
+	ErrChecksum = fmt.Errorf("%w: checksum", Error)
+
+	// ErrCanceled indicates dictionary download or build stopped as its context is done.
+	ErrCanceled = fmt.Errorf("%w: canceled", Error)
+)
@@ -1,39 +1,94 @@
 package opencorpora
 
 import (
+	"bufio"
 	"compress/bzip2"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"path"
-	"runtime/debug"
+	"runtime"
+	"strings"
 	"time"
 
 	"github.com/amarin/binutils"
-	"github.com/amarin/libxml"
 	"github.com/amarin/logging"
 
 	"github.com/amarin/gomorphy/internal/index"
 	"github.com/amarin/gomorphy/pkg/common"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
 )
 
 // Loader provides OpenCorpora dictionary parsing utilities.
 type Loader struct {
 	logging.Logger
-	dataPath   string
-	stringData string
+	dataPath      string
+	compiledFile  string // compiled index file path, LocalCompiledFilename in data path if empty
+	sourceURL     string
+	httpClient    *http.Client
+	retries       int           // count of download retries, no retries if negative
+	retryDelay    time.Duration // delay before first download retry, doubled for every next retry
+	checksum      string        // expected SHA-256 hex digest of downloaded file, not verified if empty
+	compression   Compression   // compression of saved index
+	stringData    string
+	workers       int               // count of goroutines filling index
+	grammemes     []dag.Tag         // grammemes of text dumps
+	buildProgress BuildProgressFunc // takes download and build progress if set
 }
 
+// NewLoader returns loader using specified data path and default options.
+// If data path is empty domain data path is used.
 func NewLoader(dataPath string) *Loader {
-	if dataPath == "" {
-		dataPath = common.DomainDataPath(DomainName)
-	}
+	return NewLoaderWithOptions(Options{
+		DataPath:     dataPath,
+		CompiledFile: "",
+		SourceURL:    "",
+		HTTPClient:   nil,
+		Retries:      0,
+		RetryDelay:   0,
+		Checksum:     "",
+		Logger:       nil,
+		Compression:  CompressionNone,
+	})
+}
+
+// NewLoaderWithOptions returns loader using specified options, empty options are set to defaults.
+func NewLoaderWithOptions(options Options) *Loader {
+	options = options.withDefaults()
 
 	return &Loader{
-		Logger:   logging.NewNamedLogger("loader").WithLevel(logging.LevelDebug),
-		dataPath: dataPath,
+		Logger:        options.Logger,
+		dataPath:      options.DataPath,
+		compiledFile:  options.CompiledFile,
+		sourceURL:     options.SourceURL,
+		httpClient:    options.HTTPClient,
+		retries:       options.Retries,
+		retryDelay:    options.RetryDelay,
+		checksum:      strings.ToLower(options.Checksum),
+		compression:   options.Compression,
+		workers:       runtime.NumCPU(),
+		grammemes:     nil,
+		buildProgress: nil,
+	}
+}
+
+// Options returns loader options.
+func (loader Loader) Options() Options {
+	return Options{
+		DataPath:     loader.dataPath,
+		CompiledFile: loader.compiledFile,
+		SourceURL:    loader.sourceURL,
+		HTTPClient:   loader.httpClient,
+		Retries:      loader.retries,
+		RetryDelay:   loader.retryDelay,
+		Checksum:     loader.checksum,
+		Logger:       loader.Logger,
+		Compression:  loader.compression,
 	}
 }
 
@@ -49,6 +104,53 @@ func (loader *Loader) SetDataPath(dataPath string) {
 	loader.dataPath = dataPath
 }
 
+// CompiledFile returns compiled index file path.
+func (loader Loader) CompiledFile() string {
+	return loader.compiledFilePath()
+}
+
+// Compression returns compression of saved index.
+func (loader Loader) Compression() Compression {
+	return loader.compression
+}
+
+// SetCompression sets compression of saved index, empty compression means no compression.
+// Loaded index compression is detected by its header regardless of this setting.
+func (loader *Loader) SetCompression(compression Compression) {
+	if compression == "" {
+		compression = CompressionNone
+	}
+
+	loader.compression = compression
+}
+
+// Workers returns count of goroutines filling index while parsing update.
+func (loader Loader) Workers() int {
+	return loader.workers
+}
+
+// SetWorkers sets count of goroutines filling index while parsing update.
+// Single worker parses and indexes dictionary sequentially in one goroutine.
+// Compiled index is the same for any workers count.
+func (loader *Loader) SetWorkers(workers int) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	loader.workers = workers
+}
+
+// SetGrammemes sets grammemes registered before compiling text dumps as text dumps have no grammemes definitions.
+// If not set grammemes of downloaded or unpacked XML dictionary are used if it exists.
+func (loader *Loader) SetGrammemes(grammemes []dag.Tag) {
+	loader.grammemes = grammemes
+}
+
+// SetBuildProgress sets function taking progress of dictionary download and build, nil to not report progress.
+func (loader *Loader) SetBuildProgress(progress BuildProgressFunc) {
+	loader.buildProgress = progress
+}
+
 func (loader Loader) filePath(fileName string) string {
 	return path.Join(loader.DataPath(), fileName)
 }
@@ -65,6 +167,10 @@ func (loader Loader) unpackedFilePath() string {
 
 // compiledFilePath returns path to compiled lemmata file.
 func (loader Loader) compiledFilePath() string {
+	if loader.compiledFile != "" {
+		return loader.compiledFile
+	}
+
 	return loader.filePath(LocalCompiledFilename)
 }
 
@@ -118,109 +224,26 @@ func (loader Loader) IsUnpackedExists() bool {
 	}
 }
 
-func (loader Loader) IsUpdateRequired() (bool, error) {
-	loader.Info("check if update required")
-	expectedFile := loader.downloadedFilePath()
-	loader.Debugf("check file %v", expectedFile)
-	fileStat, err := os.Stat(expectedFile)
-	if err != nil && errors.Is(err, os.ErrNotExist) {
-		loader.Debugf("file not exists, update required: %v", expectedFile)
-
-		return true, nil // no file, update required
-	}
-
-	loader.Debugf("check remote %v", RemoteURL)
-	response, err := http.Head(RemoteURL)
-	if err != nil {
-		loader.Warnf("remote %v: error: %v", RemoteURL, err)
-		return false, err
-	}
-
-	if response.StatusCode != 200 {
-		loader.Warnf("remote %v: status: %v", RemoteURL, response.StatusCode)
-		return false, fmt.Errorf("unexpected response code %v", response.StatusCode)
-	}
-
-	lastModifiedString, ok := response.Header[common.HTTPHeaderLastModified]
-	if ok && len(lastModifiedString) > 0 {
-		loader.Debugf("remote %v: %v", common.HTTPHeaderLastModified, lastModifiedString)
-		lastModified, err := time.Parse(time.RFC1123, lastModifiedString[0])
-		if err != nil {
-			return true, nil // cant compare lastModified, do update
-		}
-		if lastModified.After(fileStat.ModTime()) {
-			return true, nil // site version is older then local
-		}
-	} else {
-		loader.Debugf("remote %v: missed, assume no update required", common.HTTPHeaderLastModified)
-	}
-
-	return false, nil // site version is older then local
-}
-
-func (loader Loader) DownloadUpdate() (updated bool, err error) {
-	updateRequired, err := loader.IsUpdateRequired()
-
-	switch {
-	case err != nil:
-		return false, err
-	case !updateRequired:
-		return false, nil
-	}
-
-	if err := common.MakeDomainDataPath(DomainName); err != nil {
-		return false, err
-	}
-
-	// Get the response bytes from the url
-	response, err := http.Get(RemoteURL)
-	if err != nil {
-		return false, err
-	}
-
-	defer func() {
-		if response.Body != nil {
-			_ = response.Body.Close()
-		}
-	}()
-
-	// Create a empty file
-	file, err := os.Create(loader.downloadedFilePath())
-	if err != nil {
-		return false, err
-	}
-
-	defer func() {
-		if file != nil {
-			_ = file.Close()
-		}
-	}()
-
-	if _, err = io.Copy(file, response.Body); err != nil {
-		return false, err
-	}
-
-	return true, nil
-}
-
+// UnpackUpdate unpacks downloaded dictionary archive into XML dictionary file.
+// Not required to compile dictionary as archive is decompressed on the fly while parsing.
 func (loader Loader) UnpackUpdate() (err error) {
 	var (
-		source     io.ReadCloser
+		packed     io.ReadCloser
 		bzipSource io.Reader
 		target     io.WriteCloser
 	)
 
-	if err := common.MakeDomainDataPath(DomainName); err != nil {
+	if err := common.MakeDataPath(loader.DataPath()); err != nil {
 		return err
 	}
 
-	if source, err = os.Open(loader.downloadedFilePath()); err != nil {
+	if packed, err = os.Open(loader.downloadedFilePath()); err != nil {
 		return err
 	}
 
-	defer func() { _ = source.Close() }()
+	defer func() { _ = packed.Close() }()
 
-	bzipSource = bzip2.NewReader(source)
+	bzipSource = bzip2.NewReader(packed)
 
 	if target, err = os.Create(loader.unpackedFilePath()); err != nil {
 		return err
@@ -235,59 +258,130 @@ func (loader Loader) UnpackUpdate() (err error) {
 	return nil
 }
 
-func (loader *Loader) LoadIndex() (mainIndex *index.Index, err error) {
-	var reader *binutils.BinaryReader
+// LoadIndex loads compiled index as index.ReadOnlyIndex safe for concurrent lookups.
+func (loader *Loader) LoadIndex() (mainIndex *index.ReadOnlyIndex, err error) {
+	return loader.LoadIndexFile(loader.compiledFilePath())
+}
 
-	fromFile := loader.compiledFilePath()
+// LoadIndexFile loads compiled index from specified file as index.ReadOnlyIndex.
+func (loader *Loader) LoadIndexFile(fromFile string) (mainIndex *index.ReadOnlyIndex, err error) {
+	var file *os.File
 
 	loader.Debugf("opening %v", fromFile)
-	if reader, err = binutils.OpenFile(fromFile); err != nil {
+	if file, err = os.Open(fromFile); err != nil {
 		return nil, fmt.Errorf("%w: open index: %v", Error, err)
 	}
 
 	defer func() {
-		loader.Debugf("loading finished %v", fromFile)
-		if closeErr := reader.Close(); err != nil {
+		if closeErr := file.Close(); closeErr != nil {
 			loader.Warnf("close index: %v", closeErr)
 		}
+	}()
 
-		if err != nil {
-			loader.Error(err.Error())
-		} else {
-			loader.Infof("compiled index loaded from %v", fromFile)
+	if mainIndex, err = loader.LoadIndexFrom(file); err != nil {
+		return nil, err
+	}
+
+	loader.Infof("compiled index loaded from %v", fromFile)
+
+	return mainIndex, nil
+}
+
+// LoadIndexFromFS loads compiled index from named file of file system, e.g. embed.FS.
+func (loader *Loader) LoadIndexFromFS(fileSystem fs.FS, name string) (mainIndex *index.ReadOnlyIndex, err error) {
+	var file fs.File
+
+	loader.Debugf("opening %v", name)
+	if file, err = fileSystem.Open(name); err != nil {
+		return nil, fmt.Errorf("%w: open index: %v", Error, err)
+	}
+
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			loader.Warnf("close index: %v", closeErr)
 		}
 	}()
 
+	if mainIndex, err = loader.LoadIndexFrom(file); err != nil {
+		return nil, err
+	}
+
+	loader.Infof("compiled index loaded from %v", name)
+
+	return mainIndex, nil
+}
+
+// LoadIndexFrom loads compiled index from reader as index.ReadOnlyIndex.
+// Compressed index is detected by its header and decompressed while loading, see Compression.
+// Reader is buffered while loading and is not closed.
+func (loader *Loader) LoadIndexFrom(reader io.Reader) (mainIndex *index.ReadOnlyIndex, err error) {
+	decompressed, closeDecompressor, err := decompressingReader(bufio.NewReader(reader))
+	if err != nil {
+		loader.Error(err.Error())
+
+		return nil, err
+	}
+
+	defer closeDecompressor()
+
 	loader.Debug("create index instance")
-	mainIndex = index.New()
+	mainIndex = new(index.ReadOnlyIndex)
 
 	loader.Debug("load index data")
-	if err = mainIndex.BinaryReadFrom(reader); err != nil {
-		return nil, fmt.Errorf("%w: read index: %v", Error, err)
+	if err = mainIndex.BinaryReadFrom(binutils.NewBinaryReader(decompressed)); err != nil {
+		err = fmt.Errorf("%w: read index: %v", Error, err)
+		loader.Error(err.Error())
+
+		return nil, err
 	}
 
+	loader.Debugf("loaded %d words %d nodes", mainIndex.WordsCount(), mainIndex.NodesCount())
+
 	return mainIndex, nil
 }
 
-func (loader *Loader) SaveIndex(mainIndex *index.Index, toFile string) (err error) {
-	var writer *binutils.BinaryWriter
+// SaveIndex optimizes index and saves it into toFile compressed using loader compression.
+// Index is written into partial file renamed to toFile when completed, so services reloading toFile never read
+// incomplete index. Incomplete file is removed if saving failed.
+func (loader *Loader) SaveIndex(mainIndex *index.IndexBuilder, toFile string) error {
+	return loader.saveIndex(loader.newBuildTracker(context.Background()), mainIndex, toFile)
+}
+
+// saveIndex optimizes index and saves it into toFile reporting phases to tracker.
+// Saving is stopped and incomplete file is removed if build canceled.
+func (loader *Loader) saveIndex(tracker *buildTracker, mainIndex *index.IndexBuilder, toFile string) (err error) {
+	var (
+		file       *os.File
+		compressor io.WriteCloser
+	)
 
 	loader.Info("save compiled index")
 
-	if writer, err = binutils.CreateFile(toFile); err != nil {
+	if err = common.MakeDataPath(path.Dir(toFile)); err != nil {
+		return fmt.Errorf("%w: create index: %v", Error, err)
+	}
+
+	partialFile := toFile + partialSuffix
+	if file, err = os.Create(partialFile); err != nil {
 		return fmt.Errorf("%w: create index: %v", Error, err)
 	}
 
 	defer func() {
 		loader.Debugf("finishing %v", toFile)
-		if closeErr := writer.Close(); err != nil {
-			loader.Warnf("close index: %v", closeErr)
+		if closeErr := file.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("%w: close index: %v", Error, closeErr)
+		}
+
+		if err == nil {
+			if renameErr := os.Rename(partialFile, toFile); renameErr != nil {
+				err = fmt.Errorf("%w: replace index: %v", Error, renameErr)
+			}
 		}
 
 		if err != nil {
 			loader.Error(err.Error())
 
-			if removeErr := os.Remove(toFile); removeErr != nil {
+			if removeErr := os.Remove(partialFile); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
 				loader.Warnf("remove incomplete index: %v", removeErr)
 			}
 		} else {
@@ -295,54 +389,278 @@ func (loader *Loader) SaveIndex(mainIndex *index.Index, toFile string) (err erro
 		}
 	}()
 
+	buffered := bufio.NewWriter(contextWriter{ctx: tracker.ctx, writer: file})
+	if compressor, err = compressingWriter(buffered, loader.compression); err != nil {
+		return err
+	}
+
 	loader.Debugf("indexed %d words %d nodes", mainIndex.WordsCount(), mainIndex.NodesCount())
 	loader.Info("optimize index")
-	mainIndex.Optimize()
-	loader.Info("saving index")
-	if err = mainIndex.BinaryWriteTo(writer); err != nil {
+	tracker.phase(PhaseOptimize, mainIndex.NodesCount())
+	mainIndex.SetLogger(loader.Logger)
+	if _, err = mainIndex.Optimize(); err != nil {
+		return fmt.Errorf("%w: optimize index: %v", Error, err)
+	}
+
+	if err = tracker.canceled(); err != nil {
+		return err
+	}
+
+	loader.Infof("saving index, compression %v", loader.compression)
+	tracker.phase(PhaseSave, mainIndex.NodesCount())
+	if err = mainIndex.BinaryWriteTo(binutils.NewBinaryWriter(compressor)); err != nil {
+		if canceled := tracker.canceled(); canceled != nil {
+			return canceled
+		}
+
+		return fmt.Errorf("%w: save index: %v", Error, err)
+	}
+
+	if err = compressor.Close(); err != nil {
+		return fmt.Errorf("%w: save index: %v: %v", Error, loader.compression, err)
+	}
+
+	if err = buffered.Flush(); err != nil {
+		if canceled := tracker.canceled(); canceled != nil {
+			return canceled
+		}
+
 		return fmt.Errorf("%w: save index: %v", Error, err)
 	}
 
 	return nil
 }
 
-func (loader *Loader) ParseUpdate(fromFile string, toFile string) (err error) {
-	loader.Info("start parse")
-	mainIndex := index.New()
-	parser := newParser(mainIndex)
-	// parser.SetMaxLemmas(1000)
+// ParseUpdate parses OpenCorpora dictionary fromFile and saves compiled index into toFile.
+// Files having .txt extension are parsed as plain-text dumps, any other as XML dictionaries.
+// XML dictionary packed into zip archive or compressed by bzip2, gzip or zstd is decompressed on the fly.
+// If more than one worker set, XML decoding, lemmas assembling and indexing are pipelined
+// across goroutines, index is filled by workers count shards merged at the end.
+func (loader *Loader) ParseUpdate(fromFile string, toFile string) error {
+	return loader.ParseUpdateContext(context.Background(), fromFile, toFile)
+}
 
-	defer func() {
-		if p := recover(); p != nil {
-			debug.PrintStack()
-			err = fmt.Errorf("%w: panic: %v", Error, p)
+// ParseUpdateContext parses dictionary like ParseUpdate reporting progress to build progress function.
+// Parsing is stopped and error wrapping ErrCanceled returned when ctx done, toFile is kept untouched then.
+func (loader *Loader) ParseUpdateContext(ctx context.Context, fromFile string, toFile string) error {
+	return loader.parseUpdate(loader.newBuildTracker(ctx), fromFile, toFile)
+}
+
+// parseUpdate parses dictionary fromFile and saves compiled index into toFile reporting progress to tracker.
+func (loader *Loader) parseUpdate(tracker *buildTracker, fromFile string, toFile string) error {
+	src, err := loader.source(fromFile, tracker)
+	if err != nil {
+		return err
+	}
+
+	return loader.compile(tracker, src, toFile)
+}
+
+// ParseUpdateFrom parses OpenCorpora XML dictionary read from reader and saves compiled index into toFile.
+// Dictionary compressed by bzip2, gzip or zstd is decompressed on the fly, reader is not closed.
+func (loader *Loader) ParseUpdateFrom(reader io.Reader, toFile string) error {
+	return loader.ParseUpdateFromContext(context.Background(), reader, toFile)
+}
+
+// ParseUpdateFromContext parses dictionary read from reader like ParseUpdateFrom reporting progress
+// to build progress function. Parsing is stopped and error wrapping ErrCanceled returned when ctx done.
+func (loader *Loader) ParseUpdateFromContext(ctx context.Context, reader io.Reader, toFile string) error {
+	tracker := loader.newBuildTracker(ctx)
+	xmlSource := NewXMLStreamSource(reader, loader.workers > 1)
+	xmlSource.SetProgress(tracker.bytes)
+
+	return loader.compile(tracker, xmlSource, toFile)
+}
+
+// compile fills new index from source and saves it into toFile reporting progress to tracker.
+func (loader *Loader) compile(tracker *buildTracker, src source.Source, toFile string) error {
+	loader.Infof("start parse using %d workers", loader.workers)
+	tracker.phase(PhaseRead, -1)
+	mainIndex := index.NewBuilder()
+	mainIndex.SetLogger(loader.Logger)
+
+	if err := source.Fill(mainIndex, trackedSource{source: src, tracker: tracker}, loader.workers); err != nil {
+		if canceled := tracker.canceled(); canceled != nil {
+			return canceled
 		}
-	}()
 
-	err = libxml.ParseXMLFile(fromFile, parser)
-	if err != nil && !errors.Is(err, ErrControlledStop) {
-		return fmt.Errorf("parse: %w", err)
+		return fmt.Errorf("%w: %v", Error, err)
+	}
+
+	if err := loader.saveIndex(tracker, mainIndex, toFile); err != nil {
+		return err
+	}
+
+	tracker.phase(PhaseDone, -1)
+
+	return nil
+}
+
+// ApplyUpdate parses OpenCorpora dictionary fromFile and applies it to compiled index stored in toFile.
+// Only lemmas having changed revision are re-indexed, lemmas missed in dictionary are removed from index.
+// If compiled index is not readable or has no lemmas revisions, full ParseUpdate is done instead.
+func (loader *Loader) ApplyUpdate(fromFile string, toFile string) error {
+	return loader.applyUpdate(loader.newBuildTracker(context.Background()), fromFile, toFile)
+}
+
+// applyUpdate applies dictionary fromFile to compiled index stored in toFile reporting progress to tracker.
+func (loader *Loader) applyUpdate(tracker *buildTracker, fromFile string, toFile string) (err error) {
+	var src source.Source
+
+	compiled, err := loader.LoadIndexFile(toFile)
+
+	switch {
+	case err != nil:
+		loader.Warnf("load compiled index: %v, do full compile", err)
+		return loader.parseUpdate(tracker, fromFile, toFile)
+	case compiled.LemmasCount() == 0:
+		loader.Warn("compiled index has no lemmas revisions, do full compile")
+		return loader.parseUpdate(tracker, fromFile, toFile)
+	}
+
+	if src, err = loader.source(fromFile, tracker); err != nil {
+		return err
+	}
+
+	loader.Infof("start applying update to %d lemmas", compiled.LemmasCount())
+	tracker.phase(PhaseRead, -1)
+	mainIndex := compiled.Thaw()
+	mainIndex.SetLogger(loader.Logger)
+
+	stats, err := source.Update(mainIndex, trackedSource{source: src, tracker: tracker})
+	if err != nil {
+		if canceled := tracker.canceled(); canceled != nil {
+			return canceled
+		}
+
+		return fmt.Errorf("%w: %v", Error, err)
+	}
+
+	loader.Infof("lemmas updated: %v", stats)
+
+	if err = loader.saveIndex(tracker, mainIndex, toFile); err != nil {
+		return err
 	}
 
-	return loader.SaveIndex(mainIndex, toFile)
+	tracker.phase(PhaseDone, -1)
+
+	return nil
 }
 
-func (loader Loader) Update(forceRecompile bool) (err error) {
-	var updated, updateRequired, downloadedExists, unpackedExists bool
+// source returns OpenCorpora dictionary source reading fromFile.
+// Files having .txt extension are read as text dumps using loader grammemes, any other as XML dictionaries
+// reporting read progress into log and tracker. XML decoding is pipelined if more than one worker set.
+func (loader *Loader) source(fromFile string, tracker *buildTracker) (source.Source, error) {
+	logProgress := loader.logProgress(fromFile)
+	progress := func(read int64, total int64) {
+		logProgress(read, total)
+		tracker.bytes(read, total)
+	}
+
+	if !strings.EqualFold(path.Ext(fromFile), ".txt") {
+		xmlSource := NewXMLSource(fromFile, loader.workers > 1)
+		xmlSource.SetProgress(progress)
+
+		return xmlSource, nil
+	}
+
+	grammemes := loader.grammemes
+	if dictionaryFile := loader.dictionaryFile(); grammemes == nil && dictionaryFile != "" {
+		loader.Infof("read grammemes of XML dictionary %v", dictionaryFile)
+
+		var err error
+		if grammemes, err = ReadGrammemes(dictionaryFile); err != nil {
+			return nil, fmt.Errorf("%w: read grammemes: %v", Error, err)
+		}
+	}
+
+	if grammemes == nil {
+		loader.Warn("no grammemes known, text dump tags are registered without parents")
+	}
+
+	textSource := NewTextSource(fromFile, grammemes)
+	textSource.SetProgress(progress)
+
+	return textSource, nil
+}
+
+// dictionaryFile returns XML dictionary file to compile and to take text dumps grammemes from.
+// Downloaded archive is preferred to unpacked dictionary. Returns empty string if no dictionary exists.
+func (loader Loader) dictionaryFile() string {
+	switch {
+	case loader.IsDownloadExists():
+		return loader.downloadedFilePath()
+	case loader.IsUnpackedExists():
+		return loader.unpackedFilePath()
+	default:
+		return ""
+	}
+}
+
+// logProgress returns progress logging read percentage of dictionary file every progressLogPercents percents.
+func (loader Loader) logProgress(fileName string) Progress {
+	reported := int64(0)
+
+	return func(read int64, total int64) {
+		if total <= 0 {
+			return
+		}
+
+		if percents := read * 100 / total; percents >= reported+progressLogPercents || (read == total && percents > reported) {
+			reported = percents
+			loader.Infof("read %d%% of %v", percents, fileName)
+		}
+	}
+}
+
+// Compile compiles OpenCorpora dictionary fromFile into compiled index at data path.
+// If forceRecompile is false only changed lemmas are applied to existing compiled index.
+func (loader *Loader) Compile(fromFile string, forceRecompile bool) error {
+	return loader.CompileContext(context.Background(), fromFile, forceRecompile)
+}
+
+// CompileContext compiles dictionary like Compile reporting progress to build progress function.
+// Compiling is stopped and error wrapping ErrCanceled returned when ctx done, compiled index is kept untouched then.
+func (loader *Loader) CompileContext(ctx context.Context, fromFile string, forceRecompile bool) error {
+	return loader.compileFile(loader.newBuildTracker(ctx), fromFile, forceRecompile)
+}
+
+// compileFile compiles dictionary fromFile into compiled index at data path reporting progress to tracker.
+func (loader *Loader) compileFile(tracker *buildTracker, fromFile string, forceRecompile bool) error {
+	if forceRecompile {
+		return loader.parseUpdate(tracker, fromFile, loader.compiledFilePath())
+	}
+
+	return loader.applyUpdate(tracker, fromFile, loader.compiledFilePath())
+}
+
+// Update downloads changed OpenCorpora dictionary and compiles it into compiled index.
+// Downloaded archive is decompressed on the fly while parsing, so no unpacked dictionary is written.
+// If update check failed and forceRecompile set, previously downloaded or unpacked dictionary is compiled.
+func (loader Loader) Update(forceRecompile bool) error {
+	return loader.UpdateContext(context.Background(), forceRecompile)
+}
+
+// UpdateContext downloads and compiles dictionary like Update reporting download and build progress
+// to build progress function. Update is stopped and error wrapping ErrCanceled returned when ctx done,
+// interrupted download is resumed by next update.
+func (loader Loader) UpdateContext(ctx context.Context, forceRecompile bool) (err error) {
+	var updated, updateRequired bool
 
 	loader.Info("check OpenCorpora updates")
 
-	updateRequired, err = loader.IsUpdateRequired()
-	downloadedExists = loader.IsDownloadExists()
-	unpackedExists = loader.IsUnpackedExists()
+	tracker := loader.newBuildTracker(ctx)
+	updateRequired, err = loader.isUpdateRequired(ctx)
+	if canceled := tracker.canceled(); canceled != nil {
+		return canceled
+	}
+
+	fromFile := loader.dictionaryFile()
 
 	switch {
-	case err != nil && forceRecompile && unpackedExists:
-		loader.Errorf("check update: %v, force recompile using previous unpacked file", err)
+	case err != nil && forceRecompile && fromFile != "":
+		loader.Errorf("check update: %v, force recompile using previous %v", err, fromFile)
 		goto compile
-	case err != nil && forceRecompile && downloadedExists:
-		loader.Errorf("check update: %v, force recompile using previous downloaded file", err)
-		goto unpack
 	case err != nil:
 		loader.Errorf("check update: %v", err)
 		return err
@@ -360,7 +678,7 @@ func (loader Loader) Update(forceRecompile bool) (err error) {
 		loader.Info("update required, downloading")
 	}
 
-	updated, err = loader.DownloadUpdate()
+	updated, err = loader.downloadUpdate(tracker)
 
 	switch {
 	case err != nil:
@@ -369,22 +687,13 @@ func (loader Loader) Update(forceRecompile bool) (err error) {
 	case !updated:
 		loader.Warn("files not updated, no errors")
 		return nil
-	default:
-		loader.Info("downloaded, unpacking")
-	}
-unpack:
-	err = loader.UnpackUpdate()
-
-	switch {
-	case err != nil:
-		loader.Errorf("unpack: %v", err)
-		return err
 	default:
 		loader.Info("lemmata updated, compile")
+		fromFile = loader.downloadedFilePath()
 	}
 
 compile:
-	if err = loader.ParseUpdate(loader.unpackedFilePath(), loader.compiledFilePath()); err != nil {
+	if err = loader.compileFile(tracker, fromFile, forceRecompile); err != nil {
 		loader.Errorf("compile: %v", err)
 		return err
 	}
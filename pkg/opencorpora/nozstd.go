@@ -0,0 +1,21 @@
+//go:build !zstd
+
+package opencorpora
+
+import (
+	"fmt"
+	"io"
+)
+
+// zstdBuilt reports zstd compression is built. Zstd package requires go 1.22, so it is built with zstd tag only.
+const zstdBuilt = false
+
+// newZstdReader returns error wrapping ErrUnknownCompression, as zstd is not built.
+func newZstdReader(io.Reader) (io.Reader, func(), error) {
+	return nil, nil, fmt.Errorf("%w: %v: build with zstd tag to load", ErrUnknownCompression, CompressionZstd)
+}
+
+// newZstdWriter returns error wrapping ErrUnknownCompression, as zstd is not built.
+func newZstdWriter(io.Writer) (io.WriteCloser, error) {
+	return nil, fmt.Errorf("%w: %v: build with zstd tag to save", ErrUnknownCompression, CompressionZstd)
+}
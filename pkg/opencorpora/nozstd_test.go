@@ -0,0 +1,35 @@
+//go:build !zstd
+
+package opencorpora_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+func TestParseCompression_NoZstd(t *testing.T) {
+	require.NotContains(t, opencorpora.Compressions, opencorpora.CompressionZstd)
+
+	_, err := opencorpora.ParseCompression("zstd")
+	require.ErrorIs(t, err, opencorpora.ErrUnknownCompression)
+}
+
+func TestLoader_NoZstd(t *testing.T) {
+	logging.MustInit()
+
+	loader := opencorpora.NewLoader(t.TempDir())
+	loader.SetWorkers(1)
+	loader.SetCompression(opencorpora.CompressionZstd)
+	require.ErrorIs(t, loader.Compile(testDictionary, true), opencorpora.ErrUnknownCompression)
+	require.NoFileExists(t, loader.CompiledFile())
+
+	// zstd frame magic followed by any data
+	zstdData := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x00, 0x00, 0x00}
+	_, err := loader.LoadIndexFrom(bytes.NewReader(zstdData))
+	require.ErrorIs(t, err, opencorpora.ErrUnknownCompression)
+}
@@ -0,0 +1,88 @@
+package opencorpora
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/amarin/logging"
+
+	"github.com/amarin/gomorphy/pkg/common"
+)
+
+// Environment variables overriding loader options, data path is set by common.EnvDataPath.
+const (
+	EnvCompiledFile = "GOMORPHY_OPENCORPORA_COMPILED" // compiled index file path
+	EnvSourceURL    = "GOMORPHY_OPENCORPORA_URL"      // dictionary download URL
+	EnvCompression  = "GOMORPHY_OPENCORPORA_COMPRESS" // saved index compression
+	EnvChecksum     = "GOMORPHY_OPENCORPORA_SHA256"   // expected SHA-256 hex digest of downloaded dictionary
+)
+
+// Download retries defaults.
+const (
+	DefaultRetries    = 3               // count of download retries
+	DefaultRetryDelay = 2 * time.Second // delay before first download retry
+)
+
+// Options provides loader settings. Empty options are set to defaults by NewLoaderWithOptions.
+type Options struct {
+	DataPath     string         // directory of downloaded, unpacked and compiled files, domain data path if empty
+	CompiledFile string         // compiled index file path, LocalCompiledFilename in data path if empty
+	SourceURL    string         // dictionary download URL, RemoteURL if empty
+	HTTPClient   *http.Client   // client checking and downloading updates, http.DefaultClient if nil
+	Retries      int            // count of download retries, DefaultRetries if 0, no retries if negative
+	RetryDelay   time.Duration  // delay before first download retry doubled for every next one, DefaultRetryDelay if 0
+	Checksum     string         // expected SHA-256 hex digest of downloaded dictionary, not verified if empty
+	Logger       logging.Logger // loader logger, named loader logger if nil
+	Compression  Compression    // saved index compression, no compression if empty
+}
+
+// OptionsFromEnv returns options set by environment variables.
+// Data path is taken from common.EnvDataPath, compiled file from EnvCompiledFile, source URL from EnvSourceURL,
+// compression from EnvCompression and checksum from EnvChecksum.
+func OptionsFromEnv() Options {
+	return Options{
+		DataPath:     common.DomainDataPath(DomainName),
+		CompiledFile: os.Getenv(EnvCompiledFile),
+		SourceURL:    os.Getenv(EnvSourceURL),
+		HTTPClient:   nil,
+		Retries:      0,
+		RetryDelay:   0,
+		Checksum:     os.Getenv(EnvChecksum),
+		Logger:       nil,
+		Compression:  Compression(os.Getenv(EnvCompression)),
+	}
+}
+
+// withDefaults returns options having empty settings replaced with defaults.
+func (options Options) withDefaults() Options {
+	if options.DataPath == "" {
+		options.DataPath = common.DomainDataPath(DomainName)
+	}
+
+	if options.SourceURL == "" {
+		options.SourceURL = RemoteURL
+	}
+
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+
+	if options.Retries == 0 {
+		options.Retries = DefaultRetries
+	}
+
+	if options.RetryDelay == 0 {
+		options.RetryDelay = DefaultRetryDelay
+	}
+
+	if options.Compression == "" {
+		options.Compression = CompressionNone
+	}
+
+	if options.Logger == nil {
+		options.Logger = logging.NewNamedLogger("loader").WithLevel(logging.LevelDebug)
+	}
+
+	return options
+}
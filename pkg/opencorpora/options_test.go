@@ -0,0 +1,91 @@
+package opencorpora_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/common"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+func TestNewLoaderWithOptions_Defaults(t *testing.T) {
+	t.Setenv(common.EnvDataPath, "")
+
+	options := opencorpora.NewLoaderWithOptions(opencorpora.Options{}).Options() //nolint:exhaustivestruct
+	require.Equal(t, filepath.Join(common.LocalDataPath, opencorpora.DomainName), options.DataPath)
+	require.Equal(t, "", options.CompiledFile)
+	require.Equal(t, opencorpora.RemoteURL, options.SourceURL)
+	require.Equal(t, http.DefaultClient, options.HTTPClient)
+	require.Equal(t, opencorpora.DefaultRetries, options.Retries)
+	require.Equal(t, opencorpora.DefaultRetryDelay, options.RetryDelay)
+	require.Equal(t, "", options.Checksum)
+	require.NotNil(t, options.Logger)
+
+	loader := opencorpora.NewLoader("")
+	require.Equal(t, filepath.Join(options.DataPath, opencorpora.LocalCompiledFilename), loader.CompiledFile())
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	dataPath := t.TempDir()
+	t.Setenv(common.EnvDataPath, dataPath)
+	t.Setenv(opencorpora.EnvCompiledFile, "/srv/opencorpora.dat")
+	t.Setenv(opencorpora.EnvSourceURL, "http://localhost/dict.xml.bz2")
+	t.Setenv(opencorpora.EnvChecksum, "ABCDEF")
+
+	options := opencorpora.OptionsFromEnv()
+	require.Equal(t, filepath.Join(dataPath, opencorpora.DomainName), options.DataPath)
+	require.Equal(t, "/srv/opencorpora.dat", options.CompiledFile)
+	require.Equal(t, "http://localhost/dict.xml.bz2", options.SourceURL)
+
+	loader := opencorpora.NewLoaderWithOptions(options)
+	require.Equal(t, options.DataPath, loader.DataPath())
+	require.Equal(t, "/srv/opencorpora.dat", loader.CompiledFile())
+	require.Equal(t, "abcdef", loader.Options().Checksum)
+}
+
+func TestLoader_Options_Honoured(t *testing.T) {
+	logging.MustInit()
+
+	content := []byte("dictionary")
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write(content)
+	}))
+	defer server.Close()
+
+	workDir := t.TempDir()
+	options := opencorpora.Options{
+		DataPath:     filepath.Join(workDir, "data", "opencorpora"),
+		CompiledFile: filepath.Join(workDir, "compiled", "index.dat"),
+		SourceURL:    server.URL + "/dict.xml.bz2",
+		HTTPClient:   server.Client(),
+		Retries:      0,
+		RetryDelay:   0,
+		Checksum:     "",
+		Logger:       nil,
+		Compression:  opencorpora.CompressionNone,
+	}
+	loader := opencorpora.NewLoaderWithOptions(options)
+	loader.SetWorkers(1)
+
+	updated, err := loader.DownloadUpdate()
+	require.NoError(t, err)
+	require.True(t, updated)
+
+	downloaded, err := os.ReadFile(filepath.Join(options.DataPath, opencorpora.LocalSourceFilename))
+	require.NoError(t, err)
+	require.Equal(t, content, downloaded)
+
+	require.NoError(t, loader.Compile(testDictionary, true))
+	require.FileExists(t, options.CompiledFile)
+	require.NoFileExists(t, filepath.Join(options.DataPath, opencorpora.LocalCompiledFilename))
+
+	compiled, err := loader.LoadIndex()
+	require.NoError(t, err)
+	require.Equal(t, 21, compiled.WordsCount())
+}
@@ -7,8 +7,8 @@ import (
 
 	"github.com/amarin/logging"
 
-	"github.com/amarin/gomorphy/internal/index"
 	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
 )
 
 const defaultLogAverageEachSeconds = 10
@@ -33,7 +33,7 @@ var ignoreElement = elementProcessor{processStart: ignoreElementStart, processDa
 
 type Parser struct {
 	logging.Logger
-	index           dag.Index
+	handler         source.Handler
 	dictionary      *Dictionary
 	collectedData   string
 	currentPath     string
@@ -52,7 +52,7 @@ type Parser struct {
 	maxLemmas int
 }
 
-func newParser(indexInstance dag.Index) *Parser {
+func newParser(handler source.Handler) *Parser {
 	dictionary := &Dictionary{
 		VersionAttr:  0,
 		RevisionAttr: 0,
@@ -65,7 +65,7 @@ func newParser(indexInstance dag.Index) *Parser {
 
 	parser := &Parser{
 		Logger:          logging.NewNamedLogger("parser").WithLevel(logging.LevelDebug),
-		index:           indexInstance,
+		handler:         handler,
 		dictionary:      dictionary,
 		collectedData:   "",
 		parsers:         make(map[string]elementProcessor),
@@ -205,7 +205,9 @@ func (parser *Parser) onGrammeme() *elementProcessor {
 		},
 		processData: ignoreElementData,
 		processEnd: func(element xml.EndElement) error {
-			_ = parser.index.TagID(parser.currentGrammeme.Name, parser.currentGrammeme.Parent)
+			if err := parser.handler.Tag(*parser.currentGrammeme); err != nil {
+				return fmt.Errorf("index: %w", err)
+			}
 			parser.currentGrammeme = nil
 			return nil
 		},
@@ -274,28 +276,8 @@ func (parser *Parser) onDictionaryLemmataLemma() *elementProcessor {
 		},
 		processData: ignoreElementData,
 		processEnd: func(element xml.EndElement) (err error) {
-			var node dag.Node
-			for _, variant := range parser.currentLemma.F {
-				// prepend form categories with Lemma.L categories list
-				variant.G = append(parser.currentLemma.L.G, variant.G...)
-
-				if node, err = parser.index.AddString(variant.Form); err != nil {
-					return fmt.Errorf("index: %w", err)
-				}
-
-				if err = node.AddTagSet(variant.GetTagsFromSet()...); err != nil {
-					return fmt.Errorf("add lemma variant: %w", err)
-				}
-
-				switch indexedNode := node.(type) {
-				case *index.Node:
-					item := indexedNode.Item()
-					parser.Debugf(
-						"IDX+ I%07d P%07d %#08x %v [%v] ",
-						item.ID, item.Parent, item.Variants, variant.Form, variant.G)
-				default:
-					parser.Debugf("+ %v [%v]", variant.Form, variant.G)
-				}
+			if err = parser.handler.Lemma(parser.currentLemma.sourceLemma()); err != nil {
+				return err
 			}
 
 			parser.currentLemma = nil
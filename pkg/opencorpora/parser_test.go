@@ -0,0 +1,95 @@
+package opencorpora_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+const testDictionary = "testdata/dict.opcorpora.xml"
+
+func TestLoader_ParseUpdate_Workers(t *testing.T) {
+	logging.MustInit()
+
+	dataPath := t.TempDir()
+	loader := opencorpora.NewLoader(dataPath)
+	compiled := make([][]byte, 0)
+
+	for _, workers := range []int{1, 2, 4, 7} {
+		toFile := filepath.Join(dataPath, opencorpora.LocalCompiledFilename)
+		loader.SetWorkers(workers)
+		require.Equal(t, workers, loader.Workers())
+		require.NoError(t, loader.ParseUpdate(testDictionary, toFile))
+
+		data, err := os.ReadFile(toFile)
+		require.NoError(t, err)
+		compiled = append(compiled, data)
+	}
+
+	for idx := 1; idx < len(compiled); idx++ {
+		require.Equal(t, compiled[0], compiled[idx], "compiled index differs from sequential one")
+	}
+
+	mainIndex, err := loader.LoadIndex()
+	require.NoError(t, err)
+	require.Equal(t, 21, mainIndex.WordsCount()) // distinct word forms
+
+	for word, expectedTagSets := range map[string]int{"ёж": 1, "ежей": 2, "ёлки": 3, "стоят": 1, "котов": 2} {
+		node, err := mainIndex.FetchString(word)
+		require.NoError(t, err, word)
+		require.Len(t, node.TagSets(), expectedTagSets, word)
+	}
+}
+
+const testDictionaryUpdate = "testdata/dict.update.opcorpora.xml"
+
+func TestLoader_ApplyUpdate(t *testing.T) {
+	logging.MustInit()
+
+	for _, workers := range []int{1, 4} {
+		dataPath := t.TempDir()
+		loader := opencorpora.NewLoader(dataPath)
+		loader.SetWorkers(workers)
+		toFile := filepath.Join(dataPath, opencorpora.LocalCompiledFilename)
+
+		// without compiled index full compile is done
+		require.NoError(t, loader.ApplyUpdate(testDictionary, toFile))
+		previous, err := loader.LoadIndex()
+		require.NoError(t, err)
+		require.Equal(t, 6, previous.LemmasCount())
+
+		require.NoError(t, loader.ApplyUpdate(testDictionaryUpdate, toFile))
+		updated, err := loader.LoadIndex()
+		require.NoError(t, err)
+
+		// updated index is the same as compiled from scratch
+		fullFile := filepath.Join(dataPath, "full.idx")
+		require.NoError(t, loader.ParseUpdate(testDictionaryUpdate, fullFile))
+		updatedData, err := os.ReadFile(toFile)
+		require.NoError(t, err)
+		fullData, err := os.ReadFile(fullFile)
+		require.NoError(t, err)
+		require.Equal(t, fullData, updatedData, "workers %d", workers)
+
+		for word, expectedTagSets := range map[string]int{"ёж": 1, "лес": 1, "леса": 2, "кит": 1, "китов": 2} {
+			node, err := updated.FetchString(word)
+			require.NoError(t, err, word)
+			require.Len(t, node.TagSets(), expectedTagSets, word)
+		}
+
+		for _, word := range []string{"ёлки", "ёлок"} { // removed lemma branches are pruned
+			_, err = updated.FetchString(word)
+			require.Error(t, err, word)
+		}
+
+		lemma, found := updated.Lemma(4)
+		require.True(t, found)
+		require.EqualValues(t, 8, lemma.Revision)
+		require.Len(t, lemma.Forms, 4)
+	}
+}
@@ -0,0 +1,144 @@
+package opencorpora
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// XML decoding pipeline sizes.
+const (
+	tokensBatchSize   = 1024 // count of XML tokens passed from decoder to parser at once
+	pipelineQueueSize = 64   // count of tokens batches decoder passes before parser takes them
+)
+
+// errPipelineStopped returned to XML decoder when parser stops taking tokens.
+var errPipelineStopped = fmt.Errorf("%w: pipeline stopped", Error)
+
+// tokenForwarder takes decoded XML tokens and passes them to parser goroutine in batches.
+// Implements tokenProcessor.
+type tokenForwarder struct {
+	batch  []xml.Token
+	tokens chan<- []xml.Token
+	stop   <-chan struct{}
+}
+
+// forward copies token into current batch and passes filled batch to parser.
+func (forwarder *tokenForwarder) forward(token xml.Token) error {
+	forwarder.batch = append(forwarder.batch, xml.CopyToken(token))
+	if len(forwarder.batch) < tokensBatchSize {
+		return nil
+	}
+
+	return forwarder.flush()
+}
+
+// flush passes current batch to parser.
+func (forwarder *tokenForwarder) flush() error {
+	if len(forwarder.batch) == 0 {
+		return nil
+	}
+
+	select {
+	case forwarder.tokens <- forwarder.batch:
+		forwarder.batch = make([]xml.Token, 0, tokensBatchSize)
+		return nil
+	case <-forwarder.stop:
+		return errPipelineStopped
+	}
+}
+
+// ProcessStartElement forwards xml.StartElement to parser.
+func (forwarder *tokenForwarder) ProcessStartElement(element xml.StartElement) error {
+	return forwarder.forward(element)
+}
+
+// ProcessCharData forwards xml.CharData to parser.
+func (forwarder *tokenForwarder) ProcessCharData(data xml.CharData) error {
+	return forwarder.forward(data)
+}
+
+// ProcessEndElement forwards xml.EndElement to parser.
+func (forwarder *tokenForwarder) ProcessEndElement(element xml.EndElement) error {
+	return forwarder.forward(element)
+}
+
+// ProcessComment skips comments as parser ignores them.
+func (forwarder *tokenForwarder) ProcessComment(_ xml.Comment) error {
+	return nil
+}
+
+// ProcessProcInst skips processing instructions as parser ignores them.
+func (forwarder *tokenForwarder) ProcessProcInst(_ xml.ProcInst) error {
+	return nil
+}
+
+// ProcessDirective skips directives as parser ignores them.
+func (forwarder *tokenForwarder) ProcessDirective(_ xml.Directive) error {
+	return nil
+}
+
+// processToken passes single token into parser.
+func (parser *Parser) processToken(token xml.Token) error {
+	switch typedToken := token.(type) {
+	case xml.StartElement:
+		return parser.ProcessStartElement(typedToken)
+	case xml.CharData:
+		return parser.ProcessCharData(typedToken)
+	case xml.EndElement:
+		return parser.ProcessEndElement(typedToken)
+	default:
+		return nil
+	}
+}
+
+// parsePipelined parses XML tokens passed by parse in the caller goroutine while parser
+// assembles lemmas in a separate one. Parser errors stop parsing.
+// Returns decoder or parser error if happens or nil.
+func parsePipelined(parse func(processor tokenProcessor) error, parser *Parser) (err error) {
+	var (
+		parserErr error
+		done      = make(chan struct{})
+		tokens    = make(chan []xml.Token, pipelineQueueSize)
+		stop      = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+
+		for batch := range tokens {
+			if parserErr != nil {
+				continue
+			}
+
+			for _, token := range batch {
+				if parserErr = parser.processToken(token); parserErr != nil {
+					close(stop)
+					break
+				}
+			}
+		}
+	}()
+
+	forwarder := &tokenForwarder{
+		batch:  make([]xml.Token, 0, tokensBatchSize),
+		tokens: tokens,
+		stop:   stop,
+	}
+
+	err = parse(forwarder)
+	if err == nil {
+		err = forwarder.flush()
+	}
+
+	close(tokens)
+	<-done
+
+	switch {
+	case parserErr != nil:
+		return parserErr
+	case err != nil:
+		return fmt.Errorf("%w: decode: %v", Error, err)
+	default:
+		return nil
+	}
+}
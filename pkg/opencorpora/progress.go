@@ -0,0 +1,202 @@
+package opencorpora
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
+)
+
+// buildProgressInterval limits how often build progress is reported within a phase.
+const buildProgressInterval = 100 * time.Millisecond
+
+// Phase identifies dictionary build stage.
+type Phase string
+
+// Build phases in order they pass.
+const (
+	PhaseDownload Phase = "download" // dictionary download, bytes are downloaded ones
+	PhaseRead     Phase = "read"     // dictionary reading and indexing, bytes are dictionary file bytes read
+	PhaseOptimize Phase = "optimize" // compiled index optimization
+	PhaseSave     Phase = "save"     // compiled index saving
+	PhaseDone     Phase = "done"     // compiled index saved
+)
+
+// BuildProgress provides dictionary build state.
+type BuildProgress struct {
+	Phase      Phase // current build phase
+	BytesRead  int64 // bytes downloaded or read in current phase
+	BytesTotal int64 // total bytes to download or read in current phase, 0 if unknown
+	Lemmas     int   // count of lemmas read
+	Forms      int   // count of lemmas forms read
+	Nodes      int   // count of compiled index nodes, known since optimize phase
+}
+
+// BuildProgressFunc takes dictionary build progress. Every phase start and final phase progress are reported,
+// progress within phase is reported at most every 100ms. Function is called from build goroutines
+// but never concurrently.
+type BuildProgressFunc func(progress BuildProgress)
+
+// buildTracker counts build progress reporting it to progress function and stops build when context done.
+type buildTracker struct {
+	ctx         context.Context
+	report      BuildProgressFunc // nil if progress is not reported
+	mu          sync.Mutex
+	progress    BuildProgress
+	reportAfter time.Time
+	pending     bool // progress changed since last report
+}
+
+// newBuildTracker creates tracker reporting to loader build progress function and stopped by ctx.
+func (loader Loader) newBuildTracker(ctx context.Context) *buildTracker {
+	return &buildTracker{
+		ctx:         ctx,
+		report:      loader.buildProgress,
+		mu:          sync.Mutex{},
+		progress:    BuildProgress{Phase: "", BytesRead: 0, BytesTotal: 0, Lemmas: 0, Forms: 0, Nodes: 0},
+		reportAfter: time.Time{},
+		pending:     false,
+	}
+}
+
+// canceled returns error wrapping ErrCanceled and context error if context is done, nil otherwise.
+func (tracker *buildTracker) canceled() error {
+	if err := tracker.ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCanceled, err)
+	}
+
+	return nil
+}
+
+// phase starts next build phase reporting final progress of previous phase and next phase start.
+// Bytes are reset, nodes are set if not negative.
+func (tracker *buildTracker) phase(phase Phase, nodes int) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.flushLocked()
+
+	tracker.progress.Phase = phase
+	tracker.progress.BytesRead = 0
+	tracker.progress.BytesTotal = 0
+
+	if nodes >= 0 {
+		tracker.progress.Nodes = nodes
+	}
+
+	tracker.reportLocked(true)
+}
+
+// flush reports progress changed since last report.
+func (tracker *buildTracker) flush() {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.flushLocked()
+}
+
+// flushLocked reports progress changed since last report. Must be called holding tracker lock.
+func (tracker *buildTracker) flushLocked() {
+	if tracker.pending {
+		tracker.reportLocked(true)
+	}
+}
+
+// bytes sets bytes read in current phase. Implements Progress.
+func (tracker *buildTracker) bytes(read int64, total int64) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.progress.BytesRead = read
+	tracker.progress.BytesTotal = total
+	tracker.reportLocked(false)
+}
+
+// lemma counts lemma having specified forms count. Returns error if build is canceled.
+func (tracker *buildTracker) lemma(forms int) error {
+	if err := tracker.canceled(); err != nil {
+		return err
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	tracker.progress.Lemmas++
+	tracker.progress.Forms += forms
+	tracker.reportLocked(false)
+
+	return nil
+}
+
+// reportLocked reports progress if forced or report interval passed. Must be called holding tracker lock.
+func (tracker *buildTracker) reportLocked(force bool) {
+	if tracker.report == nil {
+		return
+	}
+
+	if now := time.Now(); force || now.After(tracker.reportAfter) {
+		tracker.reportAfter = now.Add(buildProgressInterval)
+		tracker.pending = false
+		tracker.report(tracker.progress)
+
+		return
+	}
+
+	tracker.pending = true
+}
+
+// trackedSource passes source data to handler through tracker, so build is counted and stopped when canceled.
+// Implements source.Source.
+type trackedSource struct {
+	source  source.Source
+	tracker *buildTracker
+}
+
+// Read reads wrapped source. Implements source.Source.
+func (tracked trackedSource) Read(handler source.Handler) error {
+	return tracked.source.Read(trackedHandler{handler: handler, tracker: tracked.tracker})
+}
+
+// trackedHandler counts lemmas passed to handler and stops reading when build canceled.
+// Implements source.Handler.
+type trackedHandler struct {
+	handler source.Handler
+	tracker *buildTracker
+}
+
+// Tag passes tag to handler unless build canceled. Implements source.Handler.
+func (tracked trackedHandler) Tag(tag dag.Tag) error {
+	if err := tracked.tracker.canceled(); err != nil {
+		return err
+	}
+
+	return tracked.handler.Tag(tag)
+}
+
+// Lemma counts lemma and passes it to handler unless build canceled. Implements source.Handler.
+func (tracked trackedHandler) Lemma(lemma source.Lemma) error {
+	if err := tracked.tracker.lemma(len(lemma.Forms)); err != nil {
+		return err
+	}
+
+	return tracked.handler.Lemma(lemma)
+}
+
+// contextWriter fails writes when context done, so long writes are stopped when build canceled.
+type contextWriter struct {
+	ctx    context.Context
+	writer io.Writer
+}
+
+// Write writes data unless context done. Implements io.Writer.
+func (writer contextWriter) Write(data []byte) (int, error) {
+	if err := writer.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return writer.writer.Write(data)
+}
@@ -0,0 +1,197 @@
+package opencorpora_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+// progressRecorder collects reported build progress.
+type progressRecorder struct {
+	mu       sync.Mutex
+	reported []opencorpora.BuildProgress
+}
+
+func (recorder *progressRecorder) record(progress opencorpora.BuildProgress) {
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+
+	recorder.reported = append(recorder.reported, progress)
+}
+
+// phases returns reported phases without repeats.
+func (recorder *progressRecorder) phases() []opencorpora.Phase {
+	phases := make([]opencorpora.Phase, 0)
+
+	for _, progress := range recorder.reported {
+		if len(phases) == 0 || phases[len(phases)-1] != progress.Phase {
+			phases = append(phases, progress.Phase)
+		}
+	}
+
+	return phases
+}
+
+// last returns last progress reported in phase.
+func (recorder *progressRecorder) last(phase opencorpora.Phase) opencorpora.BuildProgress {
+	var last opencorpora.BuildProgress
+
+	for _, progress := range recorder.reported {
+		if progress.Phase == phase {
+			last = progress
+		}
+	}
+
+	return last
+}
+
+func TestLoader_ParseUpdateContext_Progress(t *testing.T) {
+	logging.MustInit()
+
+	fileStat, err := os.Stat(testArchive)
+	require.NoError(t, err)
+
+	for _, workers := range []int{1, 4} {
+		loader := opencorpora.NewLoader(t.TempDir())
+		loader.SetWorkers(workers)
+
+		recorder := new(progressRecorder)
+		loader.SetBuildProgress(recorder.record)
+		require.NoError(t, loader.ParseUpdateContext(context.Background(), testArchive, loader.CompiledFile()))
+
+		require.Equal(t, []opencorpora.Phase{
+			opencorpora.PhaseRead, opencorpora.PhaseOptimize, opencorpora.PhaseSave, opencorpora.PhaseDone,
+		}, recorder.phases())
+
+		read := recorder.last(opencorpora.PhaseRead)
+		require.Equal(t, fileStat.Size(), read.BytesRead)
+		require.Equal(t, fileStat.Size(), read.BytesTotal)
+		require.Equal(t, 6, read.Lemmas)
+		require.Positive(t, read.Forms)
+
+		done := recorder.last(opencorpora.PhaseDone)
+		require.Equal(t, read.Lemmas, done.Lemmas)
+		require.Equal(t, read.Forms, done.Forms)
+		require.Positive(t, done.Nodes)
+	}
+}
+
+func TestLoader_ParseUpdateFromContext_Progress(t *testing.T) {
+	logging.MustInit()
+
+	file, err := os.Open(testArchive)
+	require.NoError(t, err)
+
+	defer func() { require.NoError(t, file.Close()) }()
+
+	fileStat, err := file.Stat()
+	require.NoError(t, err)
+
+	loader := opencorpora.NewLoader(t.TempDir())
+	loader.SetWorkers(1)
+
+	recorder := new(progressRecorder)
+	loader.SetBuildProgress(recorder.record)
+	require.NoError(t, loader.ParseUpdateFromContext(context.Background(), file, loader.CompiledFile()))
+
+	read := recorder.last(opencorpora.PhaseRead)
+	require.Equal(t, fileStat.Size(), read.BytesRead)
+	require.Zero(t, read.BytesTotal)
+	require.Equal(t, 6, read.Lemmas)
+}
+
+func TestLoader_ParseUpdateContext_Cancel(t *testing.T) {
+	logging.MustInit()
+
+	for _, workers := range []int{1, 4} {
+		loader := opencorpora.NewLoader(t.TempDir())
+		loader.SetWorkers(workers)
+
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := loader.ParseUpdateContext(canceled, testDictionary, loader.CompiledFile())
+		require.ErrorIs(t, err, opencorpora.ErrCanceled)
+		require.ErrorIs(t, err, context.Canceled)
+		require.NoFileExists(t, loader.CompiledFile())
+
+		// build canceled while reading keeps previously compiled index
+		require.NoError(t, loader.ParseUpdate(testDictionary, loader.CompiledFile()))
+		compiled, err := os.ReadFile(loader.CompiledFile())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		loader.SetBuildProgress(func(progress opencorpora.BuildProgress) {
+			if progress.Phase == opencorpora.PhaseRead {
+				cancel()
+			}
+		})
+
+		require.ErrorIs(t, loader.CompileContext(ctx, testDictionaryUpdate, true), opencorpora.ErrCanceled)
+		require.NoFileExists(t, loader.CompiledFile()+".part")
+
+		kept, err := os.ReadFile(loader.CompiledFile())
+		require.NoError(t, err)
+		require.Equal(t, compiled, kept)
+	}
+}
+
+func TestLoader_UpdateContext_Progress(t *testing.T) {
+	logging.MustInit()
+
+	fileStat, err := os.Stat(testArchive)
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.ServeFile(writer, request, testArchive)
+	}))
+	defer server.Close()
+
+	options := opencorpora.Options{
+		DataPath:     t.TempDir(),
+		CompiledFile: "",
+		SourceURL:    server.URL + "/dict.opcorpora.xml.bz2",
+		HTTPClient:   server.Client(),
+		Retries:      0,
+		RetryDelay:   0,
+		Checksum:     "",
+		Logger:       nil,
+		Compression:  opencorpora.CompressionNone,
+	}
+	loader := opencorpora.NewLoaderWithOptions(options)
+	loader.SetWorkers(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loader.SetBuildProgress(func(progress opencorpora.BuildProgress) {
+		if progress.Phase == opencorpora.PhaseDownload {
+			cancel()
+		}
+	})
+
+	require.ErrorIs(t, loader.UpdateContext(ctx, true), opencorpora.ErrCanceled)
+	require.False(t, loader.IsDownloadExists())
+
+	recorder := new(progressRecorder)
+	loader.SetBuildProgress(recorder.record)
+	require.NoError(t, loader.UpdateContext(context.Background(), true))
+	require.Equal(t, []opencorpora.Phase{
+		opencorpora.PhaseDownload,
+		opencorpora.PhaseRead,
+		opencorpora.PhaseOptimize,
+		opencorpora.PhaseSave,
+		opencorpora.PhaseDone,
+	}, recorder.phases())
+
+	downloaded := recorder.last(opencorpora.PhaseDownload)
+	require.Equal(t, fileStat.Size(), downloaded.BytesRead)
+	require.Equal(t, fileStat.Size(), downloaded.BytesTotal)
+	require.Equal(t, 6, recorder.last(opencorpora.PhaseDone).Lemmas)
+}
@@ -0,0 +1,165 @@
+package opencorpora
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+
+	"github.com/amarin/libxml"
+
+	"github.com/amarin/gomorphy/pkg/source"
+)
+
+// sourceLemma returns lemma as dictionary sources provide it.
+func (lemma Lemma) sourceLemma() source.Lemma {
+	forms := make([]source.Form, 0, len(lemma.F))
+
+	for _, variant := range lemma.F {
+		forms = append(forms, source.Form{Word: variant.Form, Tags: variant.GetTagsFromSet()})
+	}
+
+	return source.Lemma{
+		ID:       uint32(lemma.IdAttr),
+		Revision: uint32(lemma.RevAttr),
+		Tags:     lemma.L.GetTagsFromSet(),
+		Forms:    forms,
+	}
+}
+
+// XMLSource reads OpenCorpora XML dictionary file or stream. Implements source.Source.
+type XMLSource struct {
+	fileName  string
+	reader    io.Reader // dictionary stream read instead of file if set
+	pipelined bool
+	maxLemmas int
+	progress  Progress
+}
+
+// NewXMLSource creates source reading OpenCorpora XML dictionary from specified file.
+// Dictionary packed into zip archive or compressed by bzip2, gzip or zstd is decompressed on the fly.
+// If pipelined set, XML decoding and lemmas assembling are done in separate goroutines.
+func NewXMLSource(fileName string, pipelined bool) *XMLSource {
+	return &XMLSource{fileName: fileName, reader: nil, pipelined: pipelined, maxLemmas: 0, progress: nil}
+}
+
+// NewXMLStreamSource creates source reading OpenCorpora XML dictionary from reader.
+// Dictionary compressed by bzip2, gzip or zstd is decompressed on the fly, reader is not closed.
+// If pipelined set, XML decoding and lemmas assembling are done in separate goroutines.
+func NewXMLStreamSource(reader io.Reader, pipelined bool) *XMLSource {
+	return &XMLSource{fileName: "", reader: reader, pipelined: pipelined, maxLemmas: 0, progress: nil}
+}
+
+// SetMaxLemmas sets maximum lemmas count to read. Reading stops silently when reached. Zero means no limit.
+func (xmlSource *XMLSource) SetMaxLemmas(maxLemmas int) {
+	xmlSource.maxLemmas = maxLemmas
+}
+
+// SetProgress sets progress called while dictionary file or stream is read.
+func (xmlSource *XMLSource) SetProgress(progress Progress) {
+	xmlSource.progress = progress
+}
+
+// tokens returns function passing dictionary XML tokens to processor.
+// Plain XML dictionary file is parsed by libxml, zip archives, compressed files and streams
+// are decoded while decompressed on the fly.
+func (xmlSource *XMLSource) tokens() (func(processor tokenProcessor) error, error) {
+	if xmlSource.reader == nil {
+		plain, err := isPlainDictionary(xmlSource.fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		if plain {
+			return xmlSource.parseFile, nil
+		}
+	}
+
+	stream, err := xmlSource.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(processor tokenProcessor) error {
+		defer func() { _ = stream.Close() }()
+
+		return decodeXML(stream, processor)
+	}, nil
+}
+
+// parseFile parses plain XML dictionary file by libxml. As libxml reads file itself,
+// progress is reported only when parsing starts and when it is done.
+func (xmlSource *XMLSource) parseFile(processor tokenProcessor) error {
+	fileStat, err := os.Stat(xmlSource.fileName)
+	if err != nil {
+		return fmt.Errorf("%w: open dictionary: %v", Error, err)
+	}
+
+	xmlSource.reportProgress(0, fileStat.Size())
+
+	if err = libxml.ParseXMLFile(xmlSource.fileName, processor); err != nil {
+		return err
+	}
+
+	xmlSource.reportProgress(fileStat.Size(), fileStat.Size())
+
+	return nil
+}
+
+// reportProgress calls progress if set.
+func (xmlSource *XMLSource) reportProgress(read int64, total int64) {
+	if xmlSource.progress != nil {
+		xmlSource.progress(read, total)
+	}
+}
+
+// open returns decompressed dictionary stream.
+func (xmlSource *XMLSource) open() (io.ReadCloser, error) {
+	if xmlSource.reader == nil {
+		return openDictionary(xmlSource.fileName, xmlSource.progress)
+	}
+
+	counted := &progressReader{reader: xmlSource.reader, read: 0, progress: xmlSource.progress}
+
+	decompressed, closeDecompressor, err := decompressingReader(bufio.NewReader(counted))
+	if err != nil {
+		return nil, err
+	}
+
+	return &dictionaryStream{
+		Reader:  decompressed,
+		closers: []func() error{func() error { closeDecompressor(); return nil }},
+	}, nil
+}
+
+// Read parses XML dictionary passing grammemes and lemmas to handler. Implements source.Source.
+func (xmlSource *XMLSource) Read(handler source.Handler) (err error) {
+	parser := newParser(handler)
+	parser.SetMaxLemmas(xmlSource.maxLemmas)
+
+	parse, err := xmlSource.tokens()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			debug.PrintStack()
+			err = fmt.Errorf("%w: panic: %v", Error, p)
+		}
+	}()
+
+	if xmlSource.pipelined {
+		err = parsePipelined(parse, parser)
+	} else {
+		err = parse(parser)
+	}
+
+	if err != nil && !errors.Is(err, ErrControlledStop) {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package opencorpora
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// progressLogPercents is a step of dictionary read percentage logged by loader.
+const progressLogPercents = 5
+
+// zipMagic is a header of zip archive.
+var zipMagic = []byte("PK\x03\x04")
+
+// Progress is called while dictionary is read with count of bytes read from dictionary file and its size.
+// Compressed dictionaries report compressed bytes read, so progress is proportional to parsing progress.
+// Size of dictionary read from stream is unknown and reported as 0.
+type Progress func(read int64, total int64)
+
+// tokenProcessor processes decoded XML tokens. Has same methods as libxml processor, so either libxml or decodeXML feeds it.
+type tokenProcessor interface {
+	ProcessStartElement(element xml.StartElement) error
+	ProcessCharData(data xml.CharData) error
+	ProcessEndElement(element xml.EndElement) error
+	ProcessComment(comment xml.Comment) error
+	ProcessProcInst(procInst xml.ProcInst) error
+	ProcessDirective(directive xml.Directive) error
+}
+
+// decodeXML decodes XML tokens from reader passing them to processor until reader ends or processor fails.
+// Tokens are valid only until processor returns.
+func decodeXML(reader io.Reader, processor tokenProcessor) error {
+	decoder := xml.NewDecoder(reader)
+
+	for {
+		token, err := decoder.Token()
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return err
+		}
+
+		switch typedToken := token.(type) {
+		case xml.StartElement:
+			err = processor.ProcessStartElement(typedToken)
+		case xml.CharData:
+			err = processor.ProcessCharData(typedToken)
+		case xml.EndElement:
+			err = processor.ProcessEndElement(typedToken)
+		case xml.Comment:
+			err = processor.ProcessComment(typedToken)
+		case xml.ProcInst:
+			err = processor.ProcessProcInst(typedToken)
+		case xml.Directive:
+			err = processor.ProcessDirective(typedToken)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// progressFile counts bytes read from file reporting them to progress.
+// Both sequential and random access reads are counted, as zip archives are read at offsets.
+type progressFile struct {
+	file     *os.File
+	size     int64
+	read     int64
+	progress Progress
+}
+
+// count adds bytes read and reports progress.
+func (progressFile *progressFile) count(read int) {
+	progressFile.read += int64(read)
+
+	if progressFile.progress != nil {
+		progressFile.progress(progressFile.read, progressFile.size)
+	}
+}
+
+// Read reads file counting bytes read. Implements io.Reader.
+func (progressFile *progressFile) Read(buffer []byte) (int, error) {
+	read, err := progressFile.file.Read(buffer)
+	progressFile.count(read)
+
+	return read, err
+}
+
+// ReadAt reads file at offset counting bytes read. Implements io.ReaderAt.
+func (progressFile *progressFile) ReadAt(buffer []byte, offset int64) (int, error) {
+	read, err := progressFile.file.ReadAt(buffer, offset)
+	progressFile.count(read)
+
+	return read, err
+}
+
+// progressReader counts bytes read from stream of unknown size reporting them to progress.
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	progress Progress
+}
+
+// Read reads stream counting bytes read. Implements io.Reader.
+func (progressReader *progressReader) Read(buffer []byte) (int, error) {
+	read, err := progressReader.reader.Read(buffer)
+	progressReader.read += int64(read)
+
+	if progressReader.progress != nil {
+		progressReader.progress(progressReader.read, 0)
+	}
+
+	return read, err
+}
+
+// dictionaryStream provides decompressed dictionary stream and closes its file when closed.
+type dictionaryStream struct {
+	io.Reader
+	closers []func() error
+}
+
+// Close closes decompressors and dictionary file. Implements io.Closer.
+func (stream *dictionaryStream) Close() (err error) {
+	for idx := len(stream.closers) - 1; idx >= 0; idx-- {
+		if closeErr := stream.closers[idx](); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// openDictionary opens dictionary file decompressing it on the fly if compressed.
+// Zip archives are detected by header and their XML dictionary entry is read, first entry if no XML one.
+// Gzip, zstd and bzip2 compressed files are detected by header too. Progress is reported by file bytes read.
+func openDictionary(fileName string, progress Progress) (io.ReadCloser, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: open dictionary: %v", Error, err)
+	}
+
+	fileStat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("%w: open dictionary: %v", Error, err)
+	}
+
+	counted := &progressFile{file: file, size: fileStat.Size(), read: 0, progress: progress}
+	stream := &dictionaryStream{Reader: nil, closers: []func() error{file.Close}}
+
+	header := make([]byte, len(zipMagic))
+	if _, err = file.ReadAt(header, 0); err == nil && bytes.Equal(header, zipMagic) {
+		entryReader, err := openZipEntry(counted)
+		if err != nil {
+			_ = stream.Close()
+			return nil, err
+		}
+
+		stream.Reader = entryReader
+		stream.closers = append(stream.closers, entryReader.Close)
+
+		return stream, nil
+	}
+
+	decompressed, closeDecompressor, err := decompressingReader(bufio.NewReader(counted))
+	if err != nil {
+		_ = stream.Close()
+		return nil, err
+	}
+
+	stream.Reader = decompressed
+	stream.closers = append(stream.closers, func() error { closeDecompressor(); return nil })
+
+	return stream, nil
+}
+
+// isPlainDictionary returns true if dictionary file is neither zip archive nor compressed one.
+func isPlainDictionary(fileName string) (bool, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return false, fmt.Errorf("%w: open dictionary: %v", Error, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	if header, _ := reader.Peek(len(zipMagic)); bytes.Equal(header, zipMagic) {
+		return false, nil
+	}
+
+	return detectCompression(reader) == CompressionNone, nil
+}
+
+// openZipEntry opens XML dictionary entry of zip archive, first archive entry if no XML one.
+func openZipEntry(archive *progressFile) (io.ReadCloser, error) {
+	zipReader, err := zip.NewReader(archive, archive.size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: zip: %v", Error, err)
+	}
+
+	if len(zipReader.File) == 0 {
+		return nil, fmt.Errorf("%w: zip: no dictionary", Error)
+	}
+
+	entry := zipReader.File[0]
+
+	for _, zipFile := range zipReader.File {
+		if strings.EqualFold(path.Ext(zipFile.Name), ".xml") {
+			entry = zipFile
+			break
+		}
+	}
+
+	entryReader, err := entry.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: zip: %v: %v", Error, entry.Name, err)
+	}
+
+	return entryReader, nil
+}
@@ -0,0 +1,225 @@
+package opencorpora_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+	"github.com/amarin/gomorphy/pkg/source"
+)
+
+const testArchive = "testdata/dict.opcorpora.xml.bz2"
+
+// compileFile compiles dictionary file using workers and returns compiled index data.
+func compileFile(t *testing.T, fromFile string, workers int) []byte {
+	t.Helper()
+
+	loader := opencorpora.NewLoader(t.TempDir())
+	loader.SetWorkers(workers)
+	require.NoError(t, loader.Compile(fromFile, true))
+
+	data, err := os.ReadFile(loader.CompiledFile())
+	require.NoError(t, err)
+
+	return data
+}
+
+// writeZip writes zip archive having entries of specified names and contents.
+func writeZip(t *testing.T, fileName string, entries ...[2]string) {
+	t.Helper()
+
+	buffer := new(bytes.Buffer)
+	writer := zip.NewWriter(buffer)
+
+	for _, entry := range entries {
+		entryWriter, err := writer.Create(entry[0])
+		require.NoError(t, err)
+		_, err = entryWriter.Write([]byte(entry[1]))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.WriteFile(fileName, buffer.Bytes(), 0o600))
+}
+
+func TestLoader_ParseUpdate_Archives(t *testing.T) {
+	logging.MustInit()
+
+	dictionary, err := os.ReadFile(testDictionary)
+	require.NoError(t, err)
+
+	archives := t.TempDir()
+	zipFile := filepath.Join(archives, "dict.opcorpora.xml.zip")
+	writeZip(t, zipFile, [2]string{"README", "not a dictionary"}, [2]string{"dict.opcorpora.xml", string(dictionary)})
+
+	gzipped := new(bytes.Buffer)
+	gzipWriter := gzip.NewWriter(gzipped)
+	_, err = gzipWriter.Write(dictionary)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+	gzipFile := filepath.Join(archives, "dict.opcorpora.xml.gz")
+	require.NoError(t, os.WriteFile(gzipFile, gzipped.Bytes(), 0o600))
+
+	for _, workers := range []int{1, 4} {
+		expected := compileFile(t, testDictionary, workers)
+
+		for _, fromFile := range []string{testArchive, zipFile, gzipFile} {
+			require.Equal(t, expected, compileFile(t, fromFile, workers), fromFile)
+		}
+
+		packed, err := os.ReadFile(testArchive)
+		require.NoError(t, err)
+
+		loader := opencorpora.NewLoader(t.TempDir())
+		loader.SetWorkers(workers)
+		require.NoError(t, loader.ParseUpdateFrom(bytes.NewReader(packed), loader.CompiledFile()))
+
+		compiled, err := os.ReadFile(loader.CompiledFile())
+		require.NoError(t, err)
+		require.Equal(t, expected, compiled, "stream")
+	}
+
+	emptyZip := filepath.Join(archives, "empty.zip")
+	writeZip(t, emptyZip)
+	require.ErrorIs(t, opencorpora.NewLoader(t.TempDir()).ParseUpdate(emptyZip, filepath.Join(archives, "x.dat")),
+		opencorpora.Error)
+}
+
+type countingHandler struct {
+	lemmas int
+}
+
+func (handler *countingHandler) Tag(dag.Tag) error {
+	return nil
+}
+
+func (handler *countingHandler) Lemma(source.Lemma) error {
+	handler.lemmas++
+	return nil
+}
+
+func TestXMLSource_SetProgress(t *testing.T) {
+	logging.MustInit()
+
+	fileStat, err := os.Stat(testArchive)
+	require.NoError(t, err)
+
+	var lastRead, lastTotal int64
+
+	xmlSource := opencorpora.NewXMLSource(testArchive, false)
+	xmlSource.SetProgress(func(read int64, total int64) {
+		require.GreaterOrEqual(t, read, lastRead)
+		lastRead, lastTotal = read, total
+	})
+
+	handler := new(countingHandler)
+	require.NoError(t, xmlSource.Read(handler))
+	require.Equal(t, 6, handler.lemmas)
+	require.Equal(t, fileStat.Size(), lastTotal)
+	require.Equal(t, fileStat.Size(), lastRead)
+}
+
+// collectingHandler collects tags and lemmas read.
+type collectingHandler struct {
+	tags   []dag.Tag
+	lemmas []source.Lemma
+}
+
+func (handler *collectingHandler) Tag(tag dag.Tag) error {
+	handler.tags = append(handler.tags, tag)
+	return nil
+}
+
+func (handler *collectingHandler) Lemma(lemma source.Lemma) error {
+	handler.lemmas = append(handler.lemmas, lemma)
+	return nil
+}
+
+func TestXMLSource_Read_SameAsLibxml(t *testing.T) {
+	logging.MustInit()
+
+	fileStat, err := os.Stat(testDictionary)
+	require.NoError(t, err)
+
+	for _, pipelined := range []bool{false, true} {
+		var lastRead, lastTotal int64
+
+		// plain dictionary file is parsed by libxml
+		fileSource := opencorpora.NewXMLSource(testDictionary, pipelined)
+		fileSource.SetProgress(func(read int64, total int64) { lastRead, lastTotal = read, total })
+
+		expected := new(collectingHandler)
+		require.NoError(t, fileSource.Read(expected))
+		require.Equal(t, fileStat.Size(), lastRead)
+		require.Equal(t, fileStat.Size(), lastTotal)
+		require.NotEmpty(t, expected.tags)
+		require.Len(t, expected.lemmas, 6)
+
+		// dictionary stream is decoded by encoding/xml decoder
+		file, err := os.Open(testDictionary)
+		require.NoError(t, err)
+
+		decoded := new(collectingHandler)
+		err = opencorpora.NewXMLStreamSource(file, pipelined).Read(decoded)
+		require.NoError(t, file.Close())
+		require.NoError(t, err)
+		require.Equal(t, expected, decoded, "pipelined %v", pipelined)
+
+		// same for compressed dictionary file
+		decoded = new(collectingHandler)
+		require.NoError(t, opencorpora.NewXMLSource(testArchive, pipelined).Read(decoded))
+		require.Equal(t, expected, decoded, "pipelined %v", pipelined)
+	}
+}
+
+func TestReadGrammemes_Archive(t *testing.T) {
+	logging.MustInit()
+
+	expected, err := opencorpora.ReadGrammemes(testDictionary)
+	require.NoError(t, err)
+
+	grammemes, err := opencorpora.ReadGrammemes(testArchive)
+	require.NoError(t, err)
+	require.Equal(t, expected, grammemes)
+}
+
+func TestLoader_Update_NoUnpack(t *testing.T) {
+	logging.MustInit()
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.ServeFile(writer, request, testArchive)
+	}))
+	defer server.Close()
+
+	options := opencorpora.Options{
+		DataPath:     t.TempDir(),
+		CompiledFile: "",
+		SourceURL:    server.URL + "/dict.opcorpora.xml.bz2",
+		HTTPClient:   server.Client(),
+		Retries:      0,
+		RetryDelay:   0,
+		Checksum:     "",
+		Logger:       nil,
+		Compression:  opencorpora.CompressionNone,
+	}
+	loader := opencorpora.NewLoaderWithOptions(options)
+	loader.SetWorkers(1)
+
+	require.NoError(t, loader.Update(true))
+	require.True(t, loader.IsDownloadExists())
+	require.False(t, loader.IsUnpackedExists())
+
+	compiled, err := os.ReadFile(loader.CompiledFile())
+	require.NoError(t, err)
+	require.Equal(t, compileFile(t, testDictionary, 1), compiled)
+}
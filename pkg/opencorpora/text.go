@@ -0,0 +1,200 @@
+package opencorpora
+
+import (
+	"bufio"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
+)
+
+// textScannerBufferSize defines maximum line length of text dump.
+const textScannerBufferSize = 1024 * 1024
+
+// TextSource reads OpenCorpora plain-text dictionary dump. Implements source.Source.
+// Dump consists of lemmas separated by empty lines. Each lemma starts with lemma ID line
+// followed by form lines holding upper case word, tab and tags: lemma tags and form tags separated by space.
+//
+// Dump has neither grammemes definitions nor lemmas revisions. Grammemes passed to NewTextSource are
+// registered first, other tags are registered without parent on first use. Lemma revision is a checksum
+// of lemma forms lines, so changed lemmas are re-indexed when dump is applied as update.
+// Except revisions compiled index is the same as compiled from XML dictionary of the same dictionary revision
+// if grammemes of XML dictionary passed.
+type TextSource struct {
+	fileName  string
+	grammemes []dag.Tag
+	progress  Progress
+}
+
+// NewTextSource creates source reading OpenCorpora text dump from specified file using specified grammemes.
+func NewTextSource(fileName string, grammemes []dag.Tag) *TextSource {
+	return &TextSource{fileName: fileName, grammemes: grammemes, progress: nil}
+}
+
+// SetProgress sets progress called while text dump file is read.
+func (textSource *TextSource) SetProgress(progress Progress) {
+	textSource.progress = progress
+}
+
+// Read parses text dump passing grammemes and lemmas to handler. Implements source.Source.
+func (textSource *TextSource) Read(handler source.Handler) (err error) {
+	var (
+		file     *os.File
+		fileStat os.FileInfo
+	)
+
+	if file, err = os.Open(textSource.fileName); err != nil {
+		return fmt.Errorf("%w: open: %v", Error, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	if fileStat, err = file.Stat(); err != nil {
+		return fmt.Errorf("%w: open: %v", Error, err)
+	}
+
+	known := make(map[dag.TagName]bool, len(textSource.grammemes))
+	for _, grammeme := range textSource.grammemes {
+		if err = handler.Tag(grammeme); err != nil {
+			return fmt.Errorf("index: %w", err)
+		}
+
+		known[grammeme.Name] = true
+	}
+
+	parser := &textParser{handler: handler, known: known, lemma: nil, checksum: fnv.New32a()}
+	scanner := bufio.NewScanner(&progressFile{file: file, size: fileStat.Size(), read: 0, progress: textSource.progress})
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), textScannerBufferSize)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if err = parser.line(scanner.Text()); err != nil {
+			return fmt.Errorf("%w: line %d: %v", Error, lineNumber, err)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("%w: read: %v", Error, err)
+	}
+
+	return parser.flush()
+}
+
+// textParser assembles lemmas from text dump lines.
+type textParser struct {
+	handler  source.Handler
+	known    map[dag.TagName]bool // tags passed to handler
+	lemma    *source.Lemma        // lemma being assembled
+	checksum hash.Hash32          // checksum of lemma forms lines
+}
+
+// line processes single text dump line.
+func (parser *textParser) line(text string) error {
+	text = strings.TrimRight(text, "\r")
+
+	switch {
+	case strings.TrimSpace(text) == "":
+		return parser.flush()
+	case parser.lemma == nil:
+		id, err := strconv.ParseUint(strings.TrimSpace(text), 10, 32)
+		if err != nil || id == 0 {
+			return fmt.Errorf("lemma ID expected: %v", text)
+		}
+
+		parser.lemma = &source.Lemma{ID: uint32(id), Revision: 0, Tags: nil, Forms: make([]source.Form, 0)}
+		parser.checksum.Reset()
+
+		return nil
+	}
+
+	word, tagsText, found := strings.Cut(text, "\t")
+	if !found || word == "" {
+		return fmt.Errorf("form expected: %v", text)
+	}
+
+	tags := make([]dag.TagName, 0)
+	for _, group := range strings.Fields(tagsText) {
+		for _, name := range strings.Split(group, ",") {
+			if name == "" {
+				continue
+			}
+
+			tagName := dag.TagName(name)
+			if err := parser.tag(tagName); err != nil {
+				return err
+			}
+
+			tags = append(tags, tagName)
+		}
+	}
+
+	_, _ = parser.checksum.Write([]byte(text))
+	_, _ = parser.checksum.Write([]byte{'\n'})
+	parser.lemma.Forms = append(parser.lemma.Forms, source.Form{Word: strings.ToLower(word), Tags: tags})
+
+	return nil
+}
+
+// tag passes tag unknown yet to handler without parent.
+func (parser *textParser) tag(name dag.TagName) error {
+	if parser.known[name] {
+		return nil
+	}
+
+	if err := parser.handler.Tag(*dag.NewTag(dag.EmptyTagName, name)); err != nil {
+		return fmt.Errorf("index: %w", err)
+	}
+
+	parser.known[name] = true
+
+	return nil
+}
+
+// flush passes assembled lemma to handler.
+func (parser *textParser) flush() error {
+	if parser.lemma == nil {
+		return nil
+	}
+
+	parser.lemma.Revision = parser.checksum.Sum32()
+	if err := parser.handler.Lemma(*parser.lemma); err != nil {
+		return err
+	}
+
+	parser.lemma = nil
+
+	return nil
+}
+
+// ReadGrammemes reads grammemes of OpenCorpora XML dictionary fromFile, archived or compressed one too.
+// Parsing stops at first lemma, so lemmas are not read.
+func ReadGrammemes(fromFile string) ([]dag.Tag, error) {
+	collector := &grammemesCollector{tags: make([]dag.Tag, 0)}
+
+	if err := NewXMLSource(fromFile, false).Read(collector); err != nil {
+		return nil, err
+	}
+
+	return collector.tags, nil
+}
+
+// grammemesCollector collects tags until first lemma taken. Implements source.Handler.
+type grammemesCollector struct {
+	tags []dag.Tag
+}
+
+// Tag remembers tag. Implements source.Handler.
+func (collector *grammemesCollector) Tag(tag dag.Tag) error {
+	collector.tags = append(collector.tags, tag)
+
+	return nil
+}
+
+// Lemma stops parsing. Implements source.Handler.
+func (collector *grammemesCollector) Lemma(_ source.Lemma) error {
+	return ErrControlledStop
+}
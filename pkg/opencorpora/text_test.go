@@ -0,0 +1,143 @@
+package opencorpora_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amarin/logging"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+const (
+	testTextDictionary       = "testdata/dict.opcorpora.txt"
+	testTextDictionaryUpdate = "testdata/dict.update.opcorpora.txt"
+)
+
+// textDumpWords returns lower case words of text dump.
+func textDumpWords(t *testing.T, fileName string) []string {
+	t.Helper()
+
+	file, err := os.Open(fileName)
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	words := make([]string, 0)
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if word, _, found := strings.Cut(scanner.Text(), "\t"); found {
+			words = append(words, strings.ToLower(word))
+		}
+	}
+
+	require.NoError(t, scanner.Err())
+
+	return words
+}
+
+// requireSameContent checks indexes have the same words, tag sets and lemmas forms.
+func requireSameContent(t *testing.T, expected *index.ReadOnlyIndex, actual *index.ReadOnlyIndex, words []string) {
+	t.Helper()
+
+	require.Equal(t, expected.WordsCount(), actual.WordsCount())
+	require.Equal(t, expected.NodesCount(), actual.NodesCount())
+	require.Equal(t, expected.TagSetIndex().Size(), actual.TagSetIndex().Size())
+	require.Equal(t, expected.LemmasCount(), actual.LemmasCount())
+
+	for _, word := range words {
+		expectedNode, err := expected.FetchString(word)
+		require.NoError(t, err, word)
+		actualNode, err := actual.FetchString(word)
+		require.NoError(t, err, word)
+		require.Equal(t, tagSetsStrings(expectedNode), tagSetsStrings(actualNode), word)
+	}
+
+	for id := index.LemmaID(1); id < 10; id++ {
+		expectedLemma, expectedFound := expected.Lemma(id)
+		actualLemma, actualFound := actual.Lemma(id)
+		require.Equal(t, expectedFound, actualFound, id)
+		require.Equal(t, expectedLemma.Forms, actualLemma.Forms, id)
+	}
+}
+
+func tagSetsStrings(node dag.Node) (res []string) {
+	for _, tagSet := range node.TagSets() {
+		res = append(res, tagSet.String())
+	}
+
+	return res
+}
+
+func TestReadGrammemes(t *testing.T) {
+	grammemes, err := opencorpora.ReadGrammemes(testDictionary)
+	require.NoError(t, err)
+	require.Greater(t, len(grammemes), 10)
+	require.Equal(t, dag.Tag{Parent: "", Name: "POST"}, grammemes[0])
+	require.Equal(t, dag.Tag{Parent: "POST", Name: "NOUN"}, grammemes[1])
+}
+
+func TestLoader_ParseUpdate_Text(t *testing.T) {
+	logging.MustInit()
+
+	grammemes, err := opencorpora.ReadGrammemes(testDictionary)
+	require.NoError(t, err)
+
+	for _, workers := range []int{1, 4} {
+		xmlLoader := opencorpora.NewLoader(t.TempDir())
+		xmlLoader.SetWorkers(workers)
+		xmlFile := filepath.Join(xmlLoader.DataPath(), opencorpora.LocalCompiledFilename)
+		textLoader := opencorpora.NewLoader(t.TempDir())
+		textLoader.SetWorkers(workers)
+		textLoader.SetGrammemes(grammemes)
+		textFile := filepath.Join(textLoader.DataPath(), opencorpora.LocalCompiledFilename)
+
+		require.NoError(t, xmlLoader.ParseUpdate(testDictionary, xmlFile))
+		require.NoError(t, textLoader.ParseUpdate(testTextDictionary, textFile))
+
+		fromXML, err := xmlLoader.LoadIndex()
+		require.NoError(t, err)
+		fromText, err := textLoader.LoadIndex()
+		require.NoError(t, err)
+		requireSameContent(t, fromXML, fromText, textDumpWords(t, testTextDictionary))
+
+		tagID, found := fromText.Tags().Find("NOUN")
+		require.True(t, found)
+		tag, found := fromText.Tags().Get(tagID)
+		require.True(t, found)
+		require.Equal(t, dag.TagName("POST"), tag.Parent)
+
+		// update is applied to lemmas having changed forms only
+		require.NoError(t, xmlLoader.ParseUpdate(testDictionaryUpdate, xmlFile))
+		require.NoError(t, textLoader.ApplyUpdate(testTextDictionaryUpdate, textFile))
+
+		updatedXML, err := xmlLoader.LoadIndex()
+		require.NoError(t, err)
+		updatedText, err := textLoader.LoadIndex()
+		require.NoError(t, err)
+		requireSameContent(t, updatedXML, updatedText, textDumpWords(t, testTextDictionaryUpdate))
+	}
+}
+
+func TestTextSource_Errors(t *testing.T) {
+	dataPath := t.TempDir()
+
+	for _, data := range []string{
+		"ЁЖ\tNOUN sing\n",
+		"1\nЁЖ NOUN\n",
+		"x\nЁЖ\tNOUN\n",
+	} {
+		fileName := filepath.Join(dataPath, "dump.txt")
+		require.NoError(t, os.WriteFile(fileName, []byte(data), 0o600))
+
+		loader := opencorpora.NewLoader(dataPath)
+		require.ErrorIs(t, loader.ParseUpdate(fileName, filepath.Join(dataPath, "dump.idx")), opencorpora.Error, data)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build zstd
+
+package opencorpora
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdBuilt reports zstd compression is built. Zstd package requires go 1.22, so it is built with zstd tag only.
+const zstdBuilt = true
+
+// newZstdReader returns reader decompressing zstd data of reader and closer releasing decompressor resources.
+func newZstdReader(reader io.Reader) (io.Reader, func(), error) {
+	zstdReader, err := zstd.NewReader(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v: %v", Error, CompressionZstd, err)
+	}
+
+	return zstdReader, zstdReader.Close, nil
+}
+
+// newZstdWriter returns writer compressing data into writer by zstd.
+func newZstdWriter(writer io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(writer, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+}
@@ -0,0 +1,19 @@
+//go:build zstd
+
+package opencorpora_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/opencorpora"
+)
+
+func TestParseCompression_Zstd(t *testing.T) {
+	require.Contains(t, opencorpora.Compressions, opencorpora.CompressionZstd)
+
+	parsed, err := opencorpora.ParseCompression("ZSTD")
+	require.NoError(t, err)
+	require.Equal(t, opencorpora.CompressionZstd, parsed)
+}
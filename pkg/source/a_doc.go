@@ -0,0 +1,6 @@
+package source
+
+// Package source defines dictionary sources the morphological index is compiled from.
+// Source streams dictionary tags and lemmas with their forms to a Handler.
+// Handlers provided by package fill index builder either from scratch or applying lemmas revisions update,
+// so any dictionary format implementing Source is compiled into the same index structures.
@@ -0,0 +1,8 @@
+package source
+
+import (
+	"errors"
+)
+
+// Error identifies dictionary sources errors.
+var Error = errors.New("source")
@@ -0,0 +1,330 @@
+package source
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// shardQueueSize defines count of pending tasks each index shard accepts before source blocks.
+const shardQueueSize = 256
+
+// indexHandler takes source data to put them into index.
+type indexHandler interface {
+	Handler
+	// close waits until all taken data indexed.
+	close() error
+}
+
+// Fill reads all source data into specified index builder.
+// If more than one worker set, index is filled by workers count shards concurrently, shards are merged at the end.
+// Index compiled from the same source is the same for any workers count.
+// Shards merging is reported by builder logger if set, see index.IndexBuilder.SetLogger.
+func Fill(builder *index.IndexBuilder, src Source, workers int) error {
+	var handler indexHandler
+
+	if workers > 1 {
+		handler = newParallelHandler(builder, workers)
+	} else {
+		handler = newBuilderHandler(builder)
+	}
+
+	return read(src, handler)
+}
+
+// Update applies lemmas of source to specified index builder holding lemmas of previous source revision.
+// Only lemmas having changed revision are re-indexed, lemmas missed in source are removed from index.
+// Every source lemma must have ID. On error index builder is left partially updated and must be discarded.
+func Update(builder *index.IndexBuilder, src Source) (index.UpdateStats, error) {
+	handler := newUpdateHandler(builder)
+
+	if err := read(src, handler); err != nil {
+		return handler.stats, err
+	}
+
+	return handler.stats, nil
+}
+
+// read passes source data to handler then closes handler.
+func read(src Source, handler indexHandler) error {
+	err := src.Read(handler)
+	closeErr := handler.close()
+
+	switch {
+	case err != nil:
+		return fmt.Errorf("%w: read: %v", Error, err)
+	case closeErr != nil:
+		return fmt.Errorf("%w: index: %v", Error, closeErr)
+	}
+
+	return nil
+}
+
+// addLemma puts lemma forms into index. Lemma having ID is registered in index too,
+// its first form is taken as lemma normal form if withNormal set.
+func addLemma(
+	builder *index.IndexBuilder, id index.LemmaID, revision uint32, withNormal bool, forms []index.WordForm,
+) error {
+	switch {
+	case id != 0 && withNormal:
+		return builder.AddLemma(id, revision, forms...)
+	case id != 0:
+		return builder.AddLemmaForms(id, revision, forms...)
+	}
+
+	for _, form := range forms {
+		if len(form.Word) == 0 {
+			return fmt.Errorf("%w: add lemma: empty word", Error)
+		}
+
+		if err := builder.AddTagSet(form.Word, form.Tags...); err != nil {
+			return fmt.Errorf("%w: add lemma: form `%s`: %v", Error, form.Word, err)
+		}
+	}
+
+	return nil
+}
+
+// builderHandler puts source data directly into index in the caller goroutine.
+type builderHandler struct {
+	index *index.IndexBuilder
+}
+
+// newBuilderHandler creates handler putting source data into specified index.
+func newBuilderHandler(indexInstance *index.IndexBuilder) *builderHandler {
+	return &builderHandler{index: indexInstance}
+}
+
+// Tag registers tag in index. Implements Handler.
+func (handler *builderHandler) Tag(tag dag.Tag) error {
+	_ = handler.index.TagID(tag.Name, tag.Parent)
+
+	return nil
+}
+
+// Lemma puts all lemma forms into index. Implements Handler.
+func (handler *builderHandler) Lemma(lemma Lemma) error {
+	forms := lemma.indexForms()
+
+	if err := addLemma(handler.index, index.LemmaID(lemma.ID), lemma.Revision, true, forms); err != nil {
+		return fmt.Errorf("add lemma: %w", err)
+	}
+
+	return nil
+}
+
+// close does nothing as builderHandler has no pending data. Implements indexHandler.
+func (handler *builderHandler) close() error {
+	return nil
+}
+
+// shardTask provides single task for index shard. Either tag or lemma forms are set.
+type shardTask struct {
+	tag      *dag.Tag         // tag to register
+	lemma    index.LemmaID    // lemma ID forms belongs to
+	revision uint32           // lemma revision
+	normal   bool             // first of forms is lemma normal form
+	forms    []index.WordForm // forms to put into shard index
+}
+
+// parallelHandler distributes source forms among several index shards filled concurrently.
+// Forms are routed to shards by their first letter, so every shard holds its own subtree of words.
+// Tags are registered in all shards in source order.
+// When closed shards are merged into target index.
+type parallelHandler struct {
+	target *index.IndexBuilder
+	shards []*index.IndexBuilder
+	queues []chan shardTask
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error // first shard error
+}
+
+// newParallelHandler creates handler filling specified count of shards concurrently and merging them into target.
+func newParallelHandler(target *index.IndexBuilder, shardsCount int) *parallelHandler {
+	if shardsCount < 1 {
+		shardsCount = 1
+	}
+
+	handler := &parallelHandler{
+		target: target,
+		shards: make([]*index.IndexBuilder, shardsCount),
+		queues: make([]chan shardTask, shardsCount),
+	}
+
+	for shardIdx := range handler.shards {
+		handler.shards[shardIdx] = index.NewBuilder()
+		handler.queues[shardIdx] = make(chan shardTask, shardQueueSize)
+		handler.wg.Add(1)
+
+		go handler.fillShard(handler.shards[shardIdx], handler.queues[shardIdx])
+	}
+
+	return handler
+}
+
+// fillShard puts queued tasks into shard index until queue closed.
+// After first error remaining tasks are skipped to not block source.
+func (handler *parallelHandler) fillShard(shard *index.IndexBuilder, queue <-chan shardTask) {
+	defer handler.wg.Done()
+
+	for task := range queue {
+		if handler.failed() != nil {
+			continue
+		}
+
+		if task.tag != nil {
+			_ = shard.TagID(task.tag.Name, task.tag.Parent)
+			continue
+		}
+
+		if err := addLemma(shard, task.lemma, task.revision, task.normal, task.forms); err != nil {
+			handler.fail(fmt.Errorf("add lemma: %w", err))
+		}
+	}
+}
+
+// fail remembers first shard error.
+func (handler *parallelHandler) fail(err error) {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	if handler.err == nil {
+		handler.err = err
+	}
+}
+
+// failed returns first shard error if any.
+func (handler *parallelHandler) failed() error {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+
+	return handler.err
+}
+
+// Tag registers tag in target index and all shards. Implements Handler.
+func (handler *parallelHandler) Tag(tag dag.Tag) error {
+	_ = handler.target.TagID(tag.Name, tag.Parent)
+
+	for _, queue := range handler.queues {
+		queue <- shardTask{
+			tag: &dag.Tag{Parent: tag.Parent, Name: tag.Name}, lemma: 0, revision: 0, normal: false, forms: nil,
+		}
+	}
+
+	return handler.failed()
+}
+
+// Lemma routes lemma forms to shards by forms first letters.
+// Every shard taking lemma forms registers lemma, lemma having no forms is registered by the first shard.
+// Lemma normal form is taken only by the shard taking lemma first form, so merged lemma has it.
+// Implements Handler.
+func (handler *parallelHandler) Lemma(lemma Lemma) error {
+	if err := handler.failed(); err != nil {
+		return err
+	}
+
+	shardForms := make([][]index.WordForm, len(handler.shards))
+	normalShard := -1
+
+	for _, form := range lemma.indexForms() {
+		shardIdx := 0
+		for _, letter := range form.Word {
+			shardIdx = int(letter) % len(handler.shards)
+			break
+		}
+
+		if normalShard < 0 {
+			normalShard = shardIdx
+		}
+
+		shardForms[shardIdx] = append(shardForms[shardIdx], form)
+	}
+
+	for shardIdx, forms := range shardForms {
+		if len(forms) > 0 || (shardIdx == 0 && len(lemma.Forms) == 0 && lemma.ID != 0) {
+			handler.queues[shardIdx] <- shardTask{
+				tag:      nil,
+				lemma:    index.LemmaID(lemma.ID),
+				revision: lemma.Revision,
+				normal:   shardIdx == normalShard,
+				forms:    forms,
+			}
+		}
+	}
+
+	return nil
+}
+
+// close waits all shards filled then merges them into target index. Implements indexHandler.
+func (handler *parallelHandler) close() error {
+	for _, queue := range handler.queues {
+		close(queue)
+	}
+
+	handler.wg.Wait()
+
+	if err := handler.failed(); err != nil {
+		return err
+	}
+
+	for shardIdx, shard := range handler.shards {
+		if logger := handler.target.Logger(); logger != nil {
+			logger.Debugf("merge shard %d of %d", shardIdx+1, len(handler.shards))
+		}
+
+		if err := handler.target.Merge(shard); err != nil {
+			return fmt.Errorf("merge shard %d: %w", shardIdx, err)
+		}
+
+		handler.shards[shardIdx] = nil
+	}
+
+	return nil
+}
+
+// updateHandler applies source lemmas to existing index in the caller goroutine.
+// Only lemmas having changed revision are re-indexed, lemmas not taken until close are removed.
+type updateHandler struct {
+	index  *index.IndexBuilder
+	update *index.LemmaUpdate
+	stats  index.UpdateStats // filled when closed
+}
+
+// newUpdateHandler creates handler applying source data to specified index.
+func newUpdateHandler(indexInstance *index.IndexBuilder) *updateHandler {
+	return &updateHandler{
+		index:  indexInstance,
+		update: indexInstance.BeginUpdate(),
+		stats:  index.UpdateStats{Added: 0, Changed: 0, Unchanged: 0, Removed: 0},
+	}
+}
+
+// Tag registers tag in index. Implements Handler.
+func (handler *updateHandler) Tag(tag dag.Tag) error {
+	_ = handler.index.TagID(tag.Name, tag.Parent)
+
+	return nil
+}
+
+// Lemma applies lemma to index. Implements Handler.
+func (handler *updateHandler) Lemma(lemma Lemma) error {
+	if lemma.ID == 0 {
+		return fmt.Errorf("%w: update lemma: no lemma ID", Error)
+	}
+
+	if err := handler.update.Lemma(index.LemmaID(lemma.ID), lemma.Revision, lemma.indexForms()...); err != nil {
+		return fmt.Errorf("update lemma: %w", err)
+	}
+
+	return nil
+}
+
+// close removes lemmas not taken since handler created. Implements indexHandler.
+func (handler *updateHandler) close() error {
+	handler.stats = handler.update.Finish()
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package source
+
+import (
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// Form provides lemma word form and its own tags.
+type Form struct {
+	Word string        // word form text
+	Tags []dag.TagName // word form own tags
+}
+
+// Lemma provides dictionary lemma with its tags shared by all forms.
+// ID and Revision identify lemma between dictionary revisions. Lemma having zero ID is not identified,
+// its forms are indexed but lemma itself is not registered, so it can't be updated or removed later.
+type Lemma struct {
+	ID       uint32        // lemma ID unique within dictionary, 0 if not identified
+	Revision uint32        // lemma revision
+	Tags     []dag.TagName // tags shared by all lemma forms
+	Forms    []Form        // lemma forms, first one is lemma normal form
+}
+
+// indexForms returns lemma forms having form tags prepended with lemma tags.
+// Each form gets its own tags slice, so forms stays valid after lemma reuse.
+func (lemma Lemma) indexForms() []index.WordForm {
+	forms := make([]index.WordForm, 0, len(lemma.Forms))
+
+	for _, form := range lemma.Forms {
+		tags := make([]dag.TagName, 0, len(lemma.Tags)+len(form.Tags))
+		tags = append(tags, lemma.Tags...)
+		tags = append(tags, form.Tags...)
+		forms = append(forms, index.WordForm{Word: form.Word, Tags: tags})
+	}
+
+	return forms
+}
+
+// Handler takes dictionary data read by Source.
+type Handler interface {
+	// Tag registers dictionary tag. Tag must be registered before lemmas use it.
+	// Tag already known keeps its parent.
+	Tag(tag dag.Tag) error
+	// Lemma takes dictionary lemma. Handler must not keep lemma slices after return.
+	Lemma(lemma Lemma) error
+}
+
+// Source provides dictionary data in any format.
+type Source interface {
+	// Read passes all dictionary tags and lemmas to handler in dictionary order.
+	// Reading stops on first handler error.
+	Read(handler Handler) error
+}
@@ -0,0 +1,165 @@
+package source_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
+)
+
+// sliceSource provides tags and lemmas from memory.
+type sliceSource struct {
+	tags   []dag.Tag
+	lemmas []source.Lemma
+}
+
+// Read passes tags then lemmas to handler. Implements source.Source.
+func (src sliceSource) Read(handler source.Handler) error {
+	for _, tag := range src.tags {
+		if err := handler.Tag(tag); err != nil {
+			return err
+		}
+	}
+
+	for _, lemma := range src.lemmas {
+		if err := handler.Lemma(lemma); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var testTags = []dag.Tag{
+	{Parent: "", Name: "POST"},
+	{Parent: "POST", Name: "NOUN"},
+	{Parent: "", Name: "NMbr"},
+	{Parent: "NMbr", Name: "sing"},
+	{Parent: "NMbr", Name: "plur"},
+}
+
+func testSource(lemmas ...source.Lemma) sliceSource {
+	return sliceSource{tags: testTags, lemmas: lemmas}
+}
+
+var (
+	lemmaCat = source.Lemma{ID: 1, Revision: 1, Tags: []dag.TagName{"NOUN"}, Forms: []source.Form{
+		{Word: "кот", Tags: []dag.TagName{"sing"}}, {Word: "коты", Tags: []dag.TagName{"plur"}},
+	}}
+	lemmaWhale = source.Lemma{ID: 2, Revision: 1, Tags: []dag.TagName{"NOUN"}, Forms: []source.Form{
+		{Word: "кит", Tags: []dag.TagName{"sing"}}, {Word: "киты", Tags: []dag.TagName{"plur"}},
+	}}
+	lemmaWhale2 = source.Lemma{ID: 2, Revision: 2, Tags: []dag.TagName{"NOUN"}, Forms: []source.Form{
+		{Word: "кит", Tags: []dag.TagName{"sing"}},
+	}}
+	lemmaForest = source.Lemma{ID: 3, Revision: 1, Tags: []dag.TagName{"NOUN"}, Forms: []source.Form{
+		{Word: "лес", Tags: []dag.TagName{"sing"}},
+	}}
+)
+
+func compile(t *testing.T, builder *index.IndexBuilder) []byte {
+	t.Helper()
+
+	compiled, err := builder.Build()
+	require.NoError(t, err)
+
+	buffer := new(bytes.Buffer)
+	require.NoError(t, compiled.BinaryWriteTo(binutils.NewBinaryWriter(buffer)))
+
+	return buffer.Bytes()
+}
+
+func TestFill(t *testing.T) {
+	expected := make([]byte, 0)
+
+	for _, workers := range []int{1, 2, 3} {
+		builder := index.NewBuilder()
+		require.NoError(t, source.Fill(builder, testSource(lemmaCat, lemmaWhale), workers))
+		require.Equal(t, 4, builder.WordsCount())
+
+		node, err := builder.FetchString("коты")
+		require.NoError(t, err)
+		require.Len(t, node.TagSets(), 1)
+
+		data := compile(t, builder)
+		if workers == 1 {
+			expected = data
+		}
+
+		require.Equal(t, expected, data, "workers %d", workers)
+	}
+}
+
+func TestFill_NormalForm(t *testing.T) {
+	// forms starting with different letters are filled by different shards
+	hedgehog := source.Lemma{ID: 4, Revision: 1, Tags: []dag.TagName{"NOUN"}, Forms: []source.Form{
+		{Word: "ежи", Tags: []dag.TagName{"plur"}}, {Word: "ёж", Tags: []dag.TagName{"sing"}},
+	}}
+
+	for _, lemma := range []source.Lemma{hedgehog, {
+		ID: 5, Revision: 1, Tags: hedgehog.Tags, Forms: []source.Form{hedgehog.Forms[1], hedgehog.Forms[0]},
+	}} {
+		for _, workers := range []int{1, 2, 3} {
+			builder := index.NewBuilder()
+			require.NoError(t, source.Fill(builder, testSource(lemma), workers))
+
+			compiled, err := builder.Build()
+			require.NoError(t, err)
+
+			stored, found := compiled.Lemma(index.LemmaID(lemma.ID))
+			require.True(t, found)
+
+			node, err := compiled.Get(stored.Normal.Node)
+			require.NoError(t, err)
+			require.Equal(t, lemma.Forms[0].Word, node.Word(), "workers %d", workers)
+		}
+	}
+}
+
+func TestFill_NotIdentified(t *testing.T) {
+	builder := index.NewBuilder()
+	anonymous := lemmaForest
+	anonymous.ID = 0
+
+	require.NoError(t, source.Fill(builder, testSource(lemmaCat, anonymous), 2))
+	require.Equal(t, 3, builder.WordsCount())
+
+	compiled, err := builder.Build()
+	require.NoError(t, err)
+	require.Equal(t, 1, compiled.LemmasCount()) // not identified lemma is not registered
+
+	_, err = compiled.FetchString("лес")
+	require.NoError(t, err)
+}
+
+func TestFill_UnknownTag(t *testing.T) {
+	broken := lemmaForest
+	broken.Tags = []dag.TagName{"ADJF"}
+
+	for _, workers := range []int{1, 2} {
+		require.ErrorIs(t, source.Fill(index.NewBuilder(), testSource(broken), workers), source.Error)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	builder := index.NewBuilder()
+	require.NoError(t, source.Fill(builder, testSource(lemmaCat, lemmaWhale), 1))
+
+	stats, err := source.Update(builder, testSource(lemmaWhale2, lemmaForest))
+	require.NoError(t, err)
+	require.Equal(t, index.UpdateStats{Added: 1, Changed: 1, Unchanged: 0, Removed: 1}, stats)
+
+	expected := index.NewBuilder()
+	require.NoError(t, source.Fill(expected, testSource(lemmaWhale2, lemmaForest), 1))
+	require.Equal(t, compile(t, expected), compile(t, builder))
+
+	anonymous := lemmaForest
+	anonymous.ID = 0
+	_, err = source.Update(expected, testSource(anonymous))
+	require.ErrorIs(t, err, source.Error)
+}
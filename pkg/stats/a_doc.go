@@ -0,0 +1,5 @@
+package stats
+
+// Package stats reports compiled index statistics: words, forms, nodes and lemmas counts, tag sets and
+// variants counts per storage table, words ambiguity distribution, most frequent tag sets and binary
+// sections sizes. Report is written in human readable or JSON form.
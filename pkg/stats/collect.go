@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// Collect returns statistics report of specified index.
+// Top limits count of most frequent tag sets listed, 0 lists all tag sets.
+func Collect(idx *index.ReadOnlyIndex, top int) (*Report, error) {
+	stats, err := idx.Stats()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", Error, err)
+	}
+
+	report := &Report{
+		Counts: Counts{
+			Words:      stats.Words,
+			Forms:      stats.Forms,
+			Nodes:      stats.Nodes,
+			Lemmas:     stats.Lemmas,
+			LemmaForms: stats.LemmaForms,
+			Grammemes:  stats.Tags,
+			TagSets:    0,
+			Variants:   0,
+		},
+		TagSetTables:  tables(stats.TagSetTables),
+		VariantTables: tables(stats.VariantTables),
+		Ambiguity:     make([]Ambiguity, 0, len(stats.Ambiguity)),
+		TopTagSets:    make([]TagSetUsage, 0, len(stats.TagSetUsage)),
+		Sections: Sections{
+			Header:   stats.Sections.Header,
+			Tags:     stats.Sections.Tags,
+			TagSets:  stats.Sections.TagSets,
+			Variants: stats.Sections.Variants,
+			Nodes:    stats.Sections.Items,
+			Lemmas:   stats.Sections.Lemmas,
+			Total:    stats.Sections.Total,
+		},
+	}
+
+	for _, table := range report.TagSetTables {
+		report.Counts.TagSets += table.Count
+	}
+
+	for _, table := range report.VariantTables {
+		report.Counts.Variants += table.Count
+	}
+
+	for tagSets, words := range stats.Ambiguity {
+		report.Ambiguity = append(report.Ambiguity, Ambiguity{TagSets: tagSets, Words: words})
+	}
+
+	sort.Slice(report.Ambiguity, func(i, j int) bool {
+		return report.Ambiguity[i].TagSets < report.Ambiguity[j].TagSets
+	})
+
+	for tagSetID, words := range stats.TagSetUsage {
+		tagSet, err := tagSetString(idx, tagSetID)
+		if err != nil {
+			return nil, err
+		}
+
+		report.TopTagSets = append(report.TopTagSets, TagSetUsage{TagSet: tagSet, Words: words})
+	}
+
+	sort.Slice(report.TopTagSets, func(i, j int) bool {
+		if report.TopTagSets[i].Words != report.TopTagSets[j].Words {
+			return report.TopTagSets[i].Words > report.TopTagSets[j].Words
+		}
+
+		return report.TopTagSets[i].TagSet < report.TopTagSets[j].TagSet
+	})
+
+	if top > 0 && len(report.TopTagSets) > top {
+		report.TopTagSets = report.TopTagSets[:top]
+	}
+
+	return report, nil
+}
+
+// tables returns storage tables having specified items counts.
+func tables(counts []int) []Table {
+	res := make([]Table, len(counts))
+	for tableIdx, count := range counts {
+		res[tableIdx] = Table{Table: tableIdx, Count: count}
+	}
+
+	return res
+}
+
+// tagSetString returns tag set as comma separated tag names.
+func tagSetString(idx *index.ReadOnlyIndex, tagSetID index.TagSetID) (string, error) {
+	tagIDs, found := idx.TagSetIndex().Get(tagSetID)
+	if !found {
+		return "", fmt.Errorf("%w: no tag set: %#08x", Error, tagSetID)
+	}
+
+	tagSet := make(dag.TagSet, tagIDs.Len())
+	for tagIdx, tagID := range tagIDs {
+		if tagSet[tagIdx], found = idx.Tags().Get(tagID); !found {
+			return "", fmt.Errorf("%w: tag set %#08x: no tag: %d", Error, tagSetID, tagID)
+		}
+	}
+
+	return tagSet.String(), nil
+}
@@ -0,0 +1,8 @@
+package stats
+
+import (
+	"errors"
+)
+
+// Error identifies statistics collection errors.
+var Error = errors.New("stats")
@@ -0,0 +1,52 @@
+package stats
+
+// Counts provides index contents counts.
+type Counts struct {
+	Words      int `json:"words"`      // count of indexed words
+	Forms      int `json:"forms"`      // count of words tag sets, i.e. indexed word forms
+	Nodes      int `json:"nodes"`      // count of index nodes
+	Lemmas     int `json:"lemmas"`     // count of known lemmas
+	LemmaForms int `json:"lemmaForms"` // count of known lemmas forms
+	Grammemes  int `json:"grammemes"`  // count of known grammemes
+	TagSets    int `json:"tagSets"`    // count of distinct tag sets
+	Variants   int `json:"variants"`   // count of distinct tag sets collections
+}
+
+// Table provides count of items stored in storage table.
+type Table struct {
+	Table int `json:"table"`
+	Count int `json:"count"`
+}
+
+// Ambiguity provides count of words having the same count of tag sets.
+type Ambiguity struct {
+	TagSets int `json:"tagSets"`
+	Words   int `json:"words"`
+}
+
+// TagSetUsage provides count of words having tag set, tag set is a comma separated tag names.
+type TagSetUsage struct {
+	TagSet string `json:"tagSet"`
+	Words  int    `json:"words"`
+}
+
+// Sections provides sizes in bytes of compiled index binary sections without compression.
+type Sections struct {
+	Header   int `json:"header"`
+	Tags     int `json:"tags"`
+	TagSets  int `json:"tagSets"`
+	Variants int `json:"variants"`
+	Nodes    int `json:"nodes"`
+	Lemmas   int `json:"lemmas"`
+	Total    int `json:"total"`
+}
+
+// Report provides compiled index statistics.
+type Report struct {
+	Counts        Counts        `json:"counts"`
+	TagSetTables  []Table       `json:"tagSetTables"`  // tag sets count per TagSetIndex table
+	VariantTables []Table       `json:"variantTables"` // tag sets collections count per VariantsIndex table
+	Ambiguity     []Ambiguity   `json:"ambiguity"`     // words count by tag sets count ascending
+	TopTagSets    []TagSetUsage `json:"topTagSets"`    // most frequent tag sets, most used first
+	Sections      Sections      `json:"sections"`
+}
@@ -0,0 +1,76 @@
+package stats_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/testdict"
+	"github.com/amarin/gomorphy/pkg/stats"
+)
+
+func TestCollect(t *testing.T) {
+	compiled := testdict.Index(t, testdict.Dictionary)
+
+	report, err := stats.Collect(compiled, 0)
+	require.NoError(t, err)
+	require.Equal(t, 21, report.Counts.Words)
+	require.Equal(t, compiled.NodesCount(), report.Counts.Nodes)
+	require.Equal(t, 6, report.Counts.Lemmas)
+	require.Equal(t, compiled.TagSetIndex().Size(), report.Counts.TagSets)
+	require.Equal(t, len(testdict.Data(t, testdict.Dictionary)), report.Sections.Total)
+
+	words, forms := 0, 0
+	for idx, ambiguity := range report.Ambiguity {
+		if idx > 0 {
+			require.Less(t, report.Ambiguity[idx-1].TagSets, ambiguity.TagSets)
+		}
+
+		words += ambiguity.Words
+		forms += ambiguity.Words * ambiguity.TagSets
+	}
+
+	require.Equal(t, report.Counts.Words, words)
+	require.Equal(t, report.Counts.Forms, forms)
+	require.Equal(t, 3, report.Ambiguity[len(report.Ambiguity)-1].TagSets) // ёлки
+
+	usages := 0
+	for idx, usage := range report.TopTagSets {
+		if idx > 0 {
+			require.GreaterOrEqual(t, report.TopTagSets[idx-1].Words, usage.Words)
+		}
+
+		usages += usage.Words
+	}
+
+	require.Equal(t, report.Counts.Forms, usages)
+
+	top, err := stats.Collect(compiled, 2)
+	require.NoError(t, err)
+	require.Equal(t, report.TopTagSets[:2], top.TopTagSets)
+}
+
+func TestReport_Write(t *testing.T) {
+	compiled := testdict.Index(t, testdict.Dictionary)
+
+	report, err := stats.Collect(compiled, 3)
+	require.NoError(t, err)
+
+	jsonOutput := new(bytes.Buffer)
+	require.NoError(t, report.WriteJSON(jsonOutput))
+
+	decoded := new(stats.Report)
+	require.NoError(t, json.Unmarshal(jsonOutput.Bytes(), decoded))
+	require.Equal(t, report, decoded)
+
+	textOutput := new(bytes.Buffer)
+	require.NoError(t, report.WriteText(textOutput))
+
+	lines := strings.Split(textOutput.String(), "\n")
+	require.Equal(t, "words: 21", lines[0])
+	require.Contains(t, lines, "top tag sets: 3")
+	require.Contains(t, lines, "  3 tag sets: 2 words (9.5%)")
+}
@@ -0,0 +1,102 @@
+package stats
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// percents returns part of total in percents, 0 if total is 0.
+func percents(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(part) * 100 / float64(total)
+}
+
+// tablesLine returns line listing tables items counts.
+func tablesLine(title string, tables []Table) string {
+	items := make([]string, len(tables))
+	for idx, table := range tables {
+		items[idx] = fmt.Sprintf("%02d(%d)", table.Table, table.Count)
+	}
+
+	return fmt.Sprintf("%s: %d: %s", title, len(tables), strings.Join(items, ", "))
+}
+
+// WriteText writes human readable report into writer.
+func (report Report) WriteText(writer io.Writer) error {
+	buffered := bufio.NewWriter(writer)
+	counts := report.Counts
+
+	lines := []string{
+		fmt.Sprintf("words: %d", counts.Words),
+		fmt.Sprintf("forms: %d", counts.Forms),
+		fmt.Sprintf("nodes: %d", counts.Nodes),
+		fmt.Sprintf("lemmas: %d", counts.Lemmas),
+		fmt.Sprintf("lemma forms: %d", counts.LemmaForms),
+		fmt.Sprintf("grammemes: %d", counts.Grammemes),
+		fmt.Sprintf("tag sets: %d", counts.TagSets),
+		fmt.Sprintf("variants: %d", counts.Variants),
+		tablesLine("tag set tables", report.TagSetTables),
+		tablesLine("variant tables", report.VariantTables),
+		"ambiguity:",
+	}
+
+	for _, ambiguity := range report.Ambiguity {
+		lines = append(lines, fmt.Sprintf("  %d tag sets: %d words (%.1f%%)",
+			ambiguity.TagSets, ambiguity.Words, percents(ambiguity.Words, counts.Words)))
+	}
+
+	lines = append(lines, fmt.Sprintf("top tag sets: %d", len(report.TopTagSets)))
+	for _, usage := range report.TopTagSets {
+		lines = append(lines, fmt.Sprintf("  %d %v", usage.Words, usage.TagSet))
+	}
+
+	sections := report.Sections
+	lines = append(lines, "sections:")
+
+	for _, section := range []struct {
+		name string
+		size int
+	}{
+		{"header", sections.Header},
+		{"tags", sections.Tags},
+		{"tag sets", sections.TagSets},
+		{"variants", sections.Variants},
+		{"nodes", sections.Nodes},
+		{"lemmas", sections.Lemmas},
+	} {
+		lines = append(lines, fmt.Sprintf("  %s: %d bytes (%.1f%%)",
+			section.name, section.size, percents(section.size, sections.Total)))
+	}
+
+	lines = append(lines, fmt.Sprintf("  total: %d bytes", sections.Total))
+
+	for _, line := range lines {
+		if _, err := buffered.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("%w: text: %v", Error, err)
+		}
+	}
+
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("%w: text: %v", Error, err)
+	}
+
+	return nil
+}
+
+// WriteJSON writes report as indented JSON object into writer.
+func (report Report) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("%w: json: %v", Error, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/amarin/binutils"
+)
+
+// WriteUvarint writes unsigned value using variable-length encoding.
+// Values below 128 take a single byte, each next 7 bits of value take one more byte.
+func WriteUvarint(writer *binutils.BinaryWriter, value uint64) error {
+	buffer := make([]byte, binary.MaxVarintLen64)
+
+	if err := writer.WriteBytes(buffer[:binary.PutUvarint(buffer, value)]); err != nil {
+		return fmt.Errorf("%w: write varint: %v", Error, err)
+	}
+
+	return nil
+}
+
+// ReadUvarint reads unsigned value written using WriteUvarint.
+func ReadUvarint(reader *binutils.BinaryReader) (value uint64, err error) {
+	var nextByte uint8
+
+	for shift := uint(0); shift < 64; shift += 7 {
+		if nextByte, err = reader.ReadUint8(); err != nil {
+			return 0, fmt.Errorf("%w: read varint: %v", Error, err)
+		}
+
+		value |= uint64(nextByte&0x7f) << shift
+
+		if nextByte < 0x80 {
+			return value, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: read varint: overflow", Error)
+}
+
+// ReadUvarintMax reads unsigned value written using WriteUvarint and checks it not exceeds specified maximum.
+func ReadUvarintMax(reader *binutils.BinaryReader, maxValue uint64) (value uint64, err error) {
+	if value, err = ReadUvarint(reader); err != nil {
+		return 0, err
+	}
+
+	if value > maxValue {
+		return 0, fmt.Errorf("%w: read varint: %d exceeds %d", Error, value, maxValue)
+	}
+
+	return value, nil
+}
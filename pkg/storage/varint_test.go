@@ -0,0 +1,63 @@
+package storage_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/amarin/binutils"
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/pkg/storage"
+)
+
+func TestUvarint_WriteRead(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		value uint64
+		want  string
+	}{
+		{0, "00"},
+		{1, "01"},
+		{127, "7f"},
+		{128, "8001"},
+		{255, "ff01"},
+		{300, "ac02"},
+		{math.MaxUint16, "ffff03"},
+		{math.MaxUint32, "ffffffff0f"},
+		{math.MaxUint64, "ffffffffffffffffff01"},
+	} {
+		tt := tt
+		t.Run(fmt.Sprintf("value_%d", tt.value), func(t *testing.T) {
+			t.Parallel()
+
+			buffer := new(bytes.Buffer)
+			require.NoError(t, storage.WriteUvarint(binutils.NewBinaryWriter(buffer), tt.value))
+			require.Equal(t, tt.want, hex.EncodeToString(buffer.Bytes()))
+
+			got, err := storage.ReadUvarint(binutils.NewBinaryReader(buffer))
+			require.NoError(t, err)
+			require.Equal(t, tt.value, got)
+			require.Zero(t, buffer.Len())
+		})
+	}
+}
+
+func TestReadUvarintMax(t *testing.T) {
+	t.Parallel()
+
+	data, _ := hex.DecodeString("ff01")
+	_, err := storage.ReadUvarintMax(binutils.NewBinaryReader(bytes.NewReader(data)), math.MaxUint8-1)
+	require.ErrorIs(t, err, storage.Error)
+
+	got, err := storage.ReadUvarintMax(binutils.NewBinaryReader(bytes.NewReader(data)), math.MaxUint8)
+	require.NoError(t, err)
+	require.EqualValues(t, math.MaxUint8, got)
+
+	truncated, _ := hex.DecodeString("ff")
+	_, err = storage.ReadUvarint(binutils.NewBinaryReader(bytes.NewReader(truncated)))
+	require.ErrorIs(t, err, storage.Error)
+}
@@ -0,0 +1,6 @@
+package supplement
+
+// Package supplement implements user-supplied supplementary dictionaries.
+// Supplementary dictionary defines domain vocabulary lemmas with forms and tags in a simple TSV or JSON format.
+// It is compiled into the same index structures as OpenCorpora dictionary and stacked over it
+// with priority, so lookups return merged parses of all stacked dictionaries.
@@ -0,0 +1,173 @@
+package supplement
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
+)
+
+// Form provides lemma word form and its own tags.
+type Form struct {
+	Word string        `json:"word"`
+	Tags []dag.TagName `json:"tags,omitempty"`
+}
+
+// Lemma provides lemma with its tags shared by all forms.
+// Lemma having no forms defines a single form equal to lemma word.
+type Lemma struct {
+	Word  string        `json:"word"`
+	Tags  []dag.TagName `json:"tags,omitempty"`
+	Forms []Form        `json:"forms,omitempty"`
+}
+
+// IndexForms returns lemma forms having form tags prepended with lemma tags.
+func (lemma Lemma) IndexForms() []Form {
+	if len(lemma.Forms) == 0 {
+		return []Form{{Word: lemma.Word, Tags: append([]dag.TagName{}, lemma.Tags...)}}
+	}
+
+	forms := make([]Form, len(lemma.Forms))
+
+	for idx, form := range lemma.Forms {
+		tags := make([]dag.TagName, 0, len(lemma.Tags)+len(form.Tags))
+		tags = append(tags, lemma.Tags...)
+		tags = append(tags, form.Tags...)
+		forms[idx] = Form{Word: form.Word, Tags: tags}
+	}
+
+	return forms
+}
+
+// untaggedForm returns word of the first lemma form having neither own nor lemma tags.
+// Such form can't be indexed, as empty tag set is taken for the first tag set of index.
+func (lemma Lemma) untaggedForm() (string, bool) {
+	for _, form := range lemma.IndexForms() {
+		if len(form.Tags) == 0 {
+			return form.Word, true
+		}
+	}
+
+	return "", false
+}
+
+// Dictionary provides supplementary dictionary data.
+// Tags defines tags introduced by dictionary with their parents.
+// Tags used by lemmas but not defined are registered without parent
+// unless they are known to the index dictionary is added to.
+type Dictionary struct {
+	Tags   []dag.Tag
+	Lemmas []Lemma
+}
+
+// LoadFile loads Dictionary from specified file.
+// Files having .json extension are read as JSON, any other as TSV.
+func LoadFile(filePath string) (dictionary *Dictionary, err error) {
+	var file *os.File
+
+	if file, err = os.Open(filePath); err != nil {
+		return nil, fmt.Errorf("%w: load: %v", Error, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	if strings.EqualFold(filepath.Ext(filePath), ".json") {
+		dictionary, err = ReadJSON(file)
+	} else {
+		dictionary, err = ReadTSV(file)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: load %v: %v", Error, filePath, err)
+	}
+
+	return dictionary, nil
+}
+
+// checkTags returns error wrapping ErrNoTags if any lemma form has no tags.
+func (dictionary Dictionary) checkTags() error {
+	for _, lemma := range dictionary.Lemmas {
+		if word, found := lemma.untaggedForm(); found {
+			return fmt.Errorf("%w: lemma %v: form %v", ErrNoTags, lemma.Word, word)
+		}
+	}
+
+	return nil
+}
+
+// Read passes dictionary tags and lemmas to handler. Implements source.Source.
+// Tags used by lemmas but not defined are passed without parent before the first lemma using them.
+// Supplementary lemmas have no IDs, so their forms are indexed without lemmas registration.
+// Returns error wrapping ErrNoTags if any lemma form has no tags, nothing is passed to handler then.
+func (dictionary Dictionary) Read(handler source.Handler) error {
+	if err := dictionary.checkTags(); err != nil {
+		return err
+	}
+
+	passed := make(map[dag.TagName]bool, len(dictionary.Tags))
+
+	for _, tag := range dictionary.Tags {
+		if err := handler.Tag(tag); err != nil {
+			return fmt.Errorf("%w: tag %v: %v", Error, tag.Name, err)
+		}
+
+		passed[tag.Name] = true
+	}
+
+	for _, lemma := range dictionary.Lemmas {
+		indexForms := lemma.IndexForms()
+		forms := make([]source.Form, len(indexForms))
+
+		for idx, form := range indexForms {
+			for _, tagName := range form.Tags {
+				if passed[tagName] {
+					continue
+				}
+
+				if err := handler.Tag(dag.Tag{Parent: dag.EmptyTagName, Name: tagName}); err != nil {
+					return fmt.Errorf("%w: tag %v: %v", Error, tagName, err)
+				}
+
+				passed[tagName] = true
+			}
+
+			forms[idx] = source.Form{Word: form.Word, Tags: form.Tags}
+		}
+
+		if err := handler.Lemma(source.Lemma{ID: 0, Revision: 0, Tags: nil, Forms: forms}); err != nil {
+			return fmt.Errorf("%w: add %v: %v", Error, lemma.Word, err)
+		}
+	}
+
+	return nil
+}
+
+// Compile makes read-only index from dictionary.
+// Known tags are registered first, so dictionary tags used by another dictionary keep their parents.
+// Returns error wrapping ErrNoTags if any lemma form has no tags.
+func (dictionary Dictionary) Compile(knownTags ...dag.Tag) (*index.ReadOnlyIndex, error) {
+	if err := dictionary.checkTags(); err != nil {
+		return nil, err
+	}
+
+	builder := index.NewBuilder()
+
+	for _, tag := range knownTags {
+		_ = builder.TagID(tag.Name, tag.Parent)
+	}
+
+	if err := source.Fill(builder, dictionary, 1); err != nil {
+		return nil, fmt.Errorf("%w: compile: %v", Error, err)
+	}
+
+	compiled, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("%w: compile: %v", Error, err)
+	}
+
+	return compiled, nil
+}
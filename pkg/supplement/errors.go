@@ -0,0 +1,20 @@
+package supplement
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// Error identifies supplementary dictionaries errors.
+	Error = errors.New("supplement")
+
+	// ErrNotFound indicates word not found in any stacked dictionary.
+	ErrNotFound = fmt.Errorf("%w: not found", Error)
+
+	// ErrReadOnly indicates attempt to modify stacked dictionaries.
+	ErrReadOnly = fmt.Errorf("%w: read only", Error)
+
+	// ErrNoTags indicates lemma form having neither own nor lemma tags.
+	ErrNoTags = fmt.Errorf("%w: no tags", Error)
+)
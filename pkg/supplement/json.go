@@ -0,0 +1,48 @@
+package supplement
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// jsonDictionary defines JSON dictionary layout.
+type jsonDictionary struct {
+	Tags []struct {
+		Name   dag.TagName `json:"name"`
+		Parent dag.TagName `json:"parent,omitempty"`
+	} `json:"tags,omitempty"`
+	Lemmas []Lemma `json:"lemmas"`
+}
+
+// ReadJSON reads Dictionary from JSON data. Every lemma form must have own or lemma tags:
+//
+//	{
+//	  "tags": [{"name": "Brand"}, {"name": "Sku", "parent": "Brand"}],
+//	  "lemmas": [{"word": "айфон", "tags": ["NOUN", "Brand"], "forms": [{"word": "айфона", "tags": ["gent"]}]}]
+//	}
+func ReadJSON(reader io.Reader) (*Dictionary, error) {
+	data := new(jsonDictionary)
+
+	if err := json.NewDecoder(reader).Decode(data); err != nil {
+		return nil, fmt.Errorf("%w: json: %v", Error, err)
+	}
+
+	dictionary := &Dictionary{Tags: make([]dag.Tag, len(data.Tags)), Lemmas: data.Lemmas}
+	for idx, tag := range data.Tags {
+		if tag.Name == "" {
+			return nil, fmt.Errorf("%w: json: tag %d: empty name", Error, idx)
+		}
+		dictionary.Tags[idx] = *dag.NewTag(tag.Parent, tag.Name)
+	}
+
+	for idx, lemma := range dictionary.Lemmas {
+		if word, found := lemma.untaggedForm(); found {
+			return nil, fmt.Errorf("%w: json: lemma %d %v: form %v", ErrNoTags, idx, lemma.Word, word)
+		}
+	}
+
+	return dictionary, nil
+}
@@ -0,0 +1,127 @@
+package supplement
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// Layer defines dictionary lookup used by Stack.
+type Layer interface {
+	// FetchString lookups string in dictionary.
+	// If found returns final node or error if not found.
+	FetchString(word string) (dag.Node, error)
+}
+
+// Stack combines several dictionaries ordered by priority.
+// Lookups return parses of all dictionaries having word, higher priority dictionaries parses come first.
+// Parses having the same tags are returned once, in the order of the highest priority dictionary.
+// Stack never changes after creation, so it is safe for concurrent lookups if all its layers are.
+type Stack struct {
+	layers []Layer // ordered from highest priority to lowest
+}
+
+// NewStack creates Stack of specified dictionaries. Dictionaries listed first take priority.
+func NewStack(layers ...Layer) *Stack {
+	return &Stack{layers: append(make([]Layer, 0, len(layers)), layers...)}
+}
+
+// LoadStack loads and compiles specified dictionary files and stacks them over base index.
+// Files listed first take priority, base index has the lowest priority.
+// Dictionaries are compiled using base index tags, so shared tags keep their parents.
+func LoadStack(base *index.ReadOnlyIndex, filePaths ...string) (*Stack, error) {
+	layers := make([]Layer, 0, len(filePaths)+1)
+
+	for _, filePath := range filePaths {
+		dictionary, err := LoadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		compiled, err := dictionary.Compile(base.Tags()...)
+		if err != nil {
+			return nil, fmt.Errorf("%w: compile %v: %v", Error, filePath, err)
+		}
+
+		layers = append(layers, compiled)
+	}
+
+	return NewStack(append(layers, base)...), nil
+}
+
+// Len returns count of stacked dictionaries.
+func (stack *Stack) Len() int {
+	return len(stack.layers)
+}
+
+// FetchRunes lookups runes sequence in all stacked dictionaries.
+// If found returns merged node or error if not found in any dictionary.
+func (stack *Stack) FetchRunes(runes []rune) (dag.Node, error) {
+	return stack.FetchString(string(runes))
+}
+
+// FetchString lookups string in all stacked dictionaries.
+// If found returns merged node or error if not found in any dictionary.
+func (stack *Stack) FetchString(word string) (dag.Node, error) {
+	var (
+		found   bool
+		known   = make(map[string]bool)
+		tagSets = make([]dag.TagSet, 0)
+	)
+
+	for _, layer := range stack.layers {
+		node, err := layer.FetchString(word)
+		if err != nil {
+			continue
+		}
+
+		found = true
+
+		for _, tagSet := range node.TagSets() {
+			key := tagSet.Key() // dictionaries may number tags differently, so tag sets are merged by names
+			if !known[key] {
+				known[key] = true
+				tagSets = append(tagSets, tagSet)
+			}
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, word)
+	}
+
+	return &Node{word: word, tagSets: tagSets}, nil
+}
+
+// Node represents word merged from stacked dictionaries. Implements dag.Node.
+type Node struct {
+	word    string
+	tagSets []dag.TagSet
+}
+
+// TagSets returns merged list of dag.TagSet. Implements dag.Node.
+func (node *Node) TagSets() []dag.TagSet {
+	return node.tagSets
+}
+
+// AddTagSet always returns ErrReadOnly as merged node can't be changed. Implements dag.Node.
+func (node *Node) AddTagSet(_ ...dag.TagName) error {
+	return fmt.Errorf("%w: add tag set", ErrReadOnly)
+}
+
+// Word returns node word. Implements dag.Node.
+func (node *Node) Word() string {
+	return node.word
+}
+
+// String returns string representation of node. Implements fmt.Stringer.
+func (node *Node) String() string {
+	tagSets := make([]string, len(node.tagSets))
+	for idx, tagSet := range node.tagSets {
+		tagSets[idx] = tagSet.String()
+	}
+
+	return "Node(" + node.word + ":" + strings.Join(tagSets, "|") + ")"
+}
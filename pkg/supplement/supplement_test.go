@@ -0,0 +1,195 @@
+package supplement_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/amarin/gomorphy/internal/index"
+	"github.com/amarin/gomorphy/pkg/dag"
+	"github.com/amarin/gomorphy/pkg/source"
+	"github.com/amarin/gomorphy/pkg/supplement"
+)
+
+func tagSetsStrings(node dag.Node) (res []string) {
+	for _, tagSet := range node.TagSets() {
+		res = append(res, tagSet.String())
+	}
+
+	return res
+}
+
+func newBaseIndex(t *testing.T) *index.ReadOnlyIndex {
+	t.Helper()
+
+	builder := index.NewBuilder()
+	builder.TagID("POST", "")
+	builder.TagID("NOUN", "POST")
+	builder.TagID("NMbr", "")
+	builder.TagID("sing", "NMbr")
+	builder.TagID("CAse", "")
+	builder.TagID("nomn", "CAse")
+	builder.TagID("inan", "")
+	builder.TagID("masc", "")
+	require.NoError(t, builder.AddTagSet("лес", "NOUN", "inan", "masc", "sing", "nomn"))
+
+	base, err := builder.Build()
+	require.NoError(t, err)
+
+	return base
+}
+
+func TestReadTSV(t *testing.T) {
+	dictionary, err := supplement.ReadTSV(strings.NewReader(
+		"# comment\ntag\tBrand\ntag\tSku\tBrand\nlemma\tайфон\tNOUN,Brand\nform\tайфона\t sing, gent \nlemma\tок\tINTJ\n"))
+	require.NoError(t, err)
+	require.Equal(t, []dag.Tag{{Parent: dag.EmptyTagName, Name: "Brand"}, {Parent: "Brand", Name: "Sku"}}, dictionary.Tags)
+	require.Equal(t, []supplement.Lemma{
+		{Word: "айфон", Tags: []dag.TagName{"NOUN", "Brand"}, Forms: []supplement.Form{
+			{Word: "айфона", Tags: []dag.TagName{"sing", "gent"}},
+		}},
+		{Word: "ок", Tags: []dag.TagName{"INTJ"}, Forms: nil},
+	}, dictionary.Lemmas)
+
+	for _, data := range []string{"form\tайфон\n", "lemma\n", "word\tайфон\n", "lemma\tа\tb\tc\n"} {
+		_, err = supplement.ReadTSV(strings.NewReader(data))
+		require.ErrorIs(t, err, supplement.Error, data)
+	}
+}
+
+func TestReadTSV_NoTags(t *testing.T) {
+	for data, line := range map[string]string{
+		"lemma\tок\n":                                     "line 1",
+		"lemma\tок\nlemma\tлес\tNOUN\n":                   "line 1",
+		"lemma\tлес\tNOUN\n\nlemma\tок\n":                 "line 3",
+		"lemma\tайфон\nform\tайфон\tsing\nform\tайфона\n": "line 3",
+	} {
+		_, err := supplement.ReadTSV(strings.NewReader(data))
+		require.ErrorIs(t, err, supplement.ErrNoTags, data)
+		require.Contains(t, err.Error(), line, data)
+	}
+
+	// lemma having no tags shares none, so its forms have own tags only
+	dictionary, err := supplement.ReadTSV(strings.NewReader("lemma\tайфон\nform\tайфон\tNOUN,sing\n"))
+	require.NoError(t, err)
+	require.Len(t, dictionary.Lemmas, 1)
+}
+
+func TestReadJSON_NoTags(t *testing.T) {
+	_, err := supplement.ReadJSON(strings.NewReader(
+		`{"lemmas": [{"word": "лес", "tags": ["NOUN"]}, {"word": "ок"}]}`))
+	require.ErrorIs(t, err, supplement.ErrNoTags)
+	require.Contains(t, err.Error(), "lemma 1")
+
+	_, err = supplement.ReadJSON(strings.NewReader(
+		`{"lemmas": [{"word": "комп", "forms": [{"word": "комп", "tags": ["NOUN"]}, {"word": "компа"}]}]}`))
+	require.ErrorIs(t, err, supplement.ErrNoTags)
+}
+
+// countingHandler counts tags and lemmas passed by source. Implements source.Handler.
+type countingHandler struct {
+	tags   int
+	lemmas int
+}
+
+func (handler *countingHandler) Tag(dag.Tag) error {
+	handler.tags++
+	return nil
+}
+
+func (handler *countingHandler) Lemma(source.Lemma) error {
+	handler.lemmas++
+	return nil
+}
+
+func TestDictionary_Compile_NoTags(t *testing.T) {
+	tagless := supplement.Lemma{Word: "ок", Tags: nil, Forms: nil}
+	tagged := supplement.Lemma{Word: "лес", Tags: []dag.TagName{"NOUN"}, Forms: nil}
+
+	for _, lemmas := range [][]supplement.Lemma{{tagless}, {tagged, tagless}, {tagless, tagged}} {
+		dictionary := supplement.Dictionary{Tags: nil, Lemmas: lemmas}
+
+		_, err := dictionary.Compile()
+		require.ErrorIs(t, err, supplement.ErrNoTags)
+
+		handler := new(countingHandler)
+		require.ErrorIs(t, dictionary.Read(handler), supplement.ErrNoTags)
+		require.Zero(t, handler.tags+handler.lemmas, "nothing is passed to handler")
+	}
+}
+
+func TestLoadFile_Compile(t *testing.T) {
+	for _, fileName := range []string{"testdata/products.tsv", "testdata/slang.json"} {
+		dictionary, err := supplement.LoadFile(fileName)
+		require.NoError(t, err, fileName)
+
+		compiled, err := dictionary.Compile()
+		require.NoError(t, err, fileName)
+
+		node, err := compiled.FetchString("лес")
+		require.NoError(t, err)
+		require.Len(t, node.TagSets(), 1)
+	}
+
+	dictionary, err := supplement.LoadFile("testdata/products.tsv")
+	require.NoError(t, err)
+	compiled, err := dictionary.Compile()
+	require.NoError(t, err)
+
+	node, err := compiled.FetchString("айфона")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Brand,NOUN,inan,masc,sing,gent"}, tagSetsStrings(node))
+
+	sku, found := compiled.Tags().Find("Sku")
+	require.True(t, found)
+	tag, _ := compiled.Tags().Get(sku)
+	require.Equal(t, dag.TagName("Brand"), tag.Parent)
+
+	_, err = supplement.LoadFile("testdata/missing.tsv")
+	require.ErrorIs(t, err, supplement.Error)
+}
+
+func TestLoadStack(t *testing.T) {
+	base := newBaseIndex(t)
+	stack, err := supplement.LoadStack(base, "testdata/products.tsv", "testdata/slang.json")
+	require.NoError(t, err)
+	require.Equal(t, 3, stack.Len())
+
+	// word known to all dictionaries gets merged parses ordered by priority
+	node, err := stack.FetchString("лес")
+	require.NoError(t, err)
+	require.Equal(t, "лес", node.Word())
+	require.Equal(t, []string{"NOUN,Sku", "NOUN,Slng", "NOUN,sing,nomn,inan,masc"}, tagSetsStrings(node))
+	require.ErrorIs(t, node.AddTagSet("NOUN"), supplement.ErrReadOnly)
+
+	// supplementary tags keep base parents
+	require.Equal(t, dag.TagName("POST"), node.TagSets()[0][0].Parent)
+
+	node, err = stack.FetchRunes([]rune("компа"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"NOUN,sing,inan,masc,Slng,gent"}, tagSetsStrings(node)) // ordered by base tag IDs
+
+	_, err = stack.FetchString("кот")
+	require.ErrorIs(t, err, supplement.ErrNotFound)
+}
+
+func TestStack_Duplicates(t *testing.T) {
+	base := newBaseIndex(t)
+	same, err := supplement.NewStack(base).FetchString("лес")
+	require.NoError(t, err)
+
+	node, err := supplement.NewStack(base, base).FetchString("лес")
+	require.NoError(t, err)
+	require.Equal(t, tagSetsStrings(same), tagSetsStrings(node))
+
+	// the same tags numbered differently in another dictionary are merged too
+	dictionary, err := supplement.ReadTSV(strings.NewReader("lemma\tлес\tmasc,inan,NOUN,nomn,sing\n"))
+	require.NoError(t, err)
+	compiled, err := dictionary.Compile()
+	require.NoError(t, err)
+
+	node, err = supplement.NewStack(compiled, base).FetchString("лес")
+	require.NoError(t, err)
+	require.Len(t, node.TagSets(), 1)
+}
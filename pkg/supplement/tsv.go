@@ -0,0 +1,116 @@
+package supplement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/amarin/gomorphy/pkg/dag"
+)
+
+// TSV dictionary line kinds.
+const (
+	tsvTag   = "tag"
+	tsvLemma = "lemma"
+	tsvForm  = "form"
+)
+
+// ReadTSV reads Dictionary from TSV data.
+// Each line has a kind and tab separated fields, tags are comma separated:
+//
+//	tag    <name>  [<parent>]
+//	lemma  <word>  [<tags>]
+//	form   <word>  [<tags>]
+//
+// Form lines define forms of the nearest preceding lemma. Lemma tags are shared by its forms,
+// so either lemma or every its form must have tags, lemma having no form lines must have tags.
+// Empty lines and lines starting with # are ignored.
+func ReadTSV(reader io.Reader) (*Dictionary, error) {
+	dictionary := &Dictionary{Tags: make([]dag.Tag, 0), Lemmas: make([]Lemma, 0)}
+	scanner := bufio.NewScanner(reader)
+	lineNumber, lemmaLine := 0, 0
+
+	// checkLemma checks the last lemma having no tags got tagged forms
+	checkLemma := func() error {
+		if lemmaLine == 0 {
+			return nil
+		}
+
+		if lemma := dictionary.Lemmas[len(dictionary.Lemmas)-1]; len(lemma.Tags) == 0 && len(lemma.Forms) == 0 {
+			return fmt.Errorf("%w: tsv: line %d: lemma %v", ErrNoTags, lemmaLine, lemma.Word)
+		}
+
+		return nil
+	}
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		for idx := range fields {
+			fields[idx] = strings.TrimSpace(fields[idx])
+		}
+
+		if len(fields) < 2 || len(fields) > 3 || fields[1] == "" {
+			return nil, fmt.Errorf("%w: tsv: line %d: expected 2 or 3 fields", Error, lineNumber)
+		}
+
+		var tags []dag.TagName
+		if len(fields) == 3 {
+			tags = parseTags(fields[2])
+		}
+
+		switch fields[0] {
+		case tsvTag:
+			parent := dag.EmptyTagName
+			if len(fields) == 3 && fields[2] != "" {
+				parent = dag.TagName(fields[2])
+			}
+			dictionary.Tags = append(dictionary.Tags, *dag.NewTag(parent, dag.TagName(fields[1])))
+		case tsvLemma:
+			if err := checkLemma(); err != nil {
+				return nil, err
+			}
+			dictionary.Lemmas = append(dictionary.Lemmas, Lemma{Word: fields[1], Tags: tags, Forms: nil})
+			lemmaLine = lineNumber
+		case tsvForm:
+			if len(dictionary.Lemmas) == 0 {
+				return nil, fmt.Errorf("%w: tsv: line %d: form without lemma", Error, lineNumber)
+			}
+			lemma := &dictionary.Lemmas[len(dictionary.Lemmas)-1]
+			if len(tags) == 0 && len(lemma.Tags) == 0 {
+				return nil, fmt.Errorf("%w: tsv: line %d: form %v", ErrNoTags, lineNumber, fields[1])
+			}
+			lemma.Forms = append(lemma.Forms, Form{Word: fields[1], Tags: tags})
+		default:
+			return nil, fmt.Errorf("%w: tsv: line %d: unknown line kind %v", Error, lineNumber, fields[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: tsv: %v", Error, err)
+	}
+
+	if err := checkLemma(); err != nil {
+		return nil, err
+	}
+
+	return dictionary, nil
+}
+
+// parseTags splits comma separated tags list.
+func parseTags(tagsList string) (tags []dag.TagName) {
+	for _, tagName := range strings.Split(tagsList, ",") {
+		if tagName = strings.TrimSpace(tagName); tagName != "" {
+			tags = append(tags, dag.TagName(tagName))
+		}
+	}
+
+	return tags
+}